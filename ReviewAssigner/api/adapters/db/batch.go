@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"review-assigner/core"
+	"time"
+)
+
+func (db *DB) GetTeams(ctx context.Context, teamNames []string) ([]core.Team, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT name, lead_id FROM teams WHERE tenant = $1 AND name = ANY($2) ORDER BY name`,
+		tenant(ctx), pq.Array(teamNames))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []core.Team
+	index := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			team   core.Team
+			leadID sql.NullString
+		)
+		if err = rows.Scan(&team.TeamName, &leadID); err != nil {
+			return nil, fmt.Errorf("failed to scan team: %w", err)
+		}
+		team.LeadID = leadID.String
+		index[team.TeamName] = len(teams)
+		teams = append(teams, team)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating teams: %w", err)
+	}
+
+	memberRows, err := db.conn.QueryContext(ctx,
+		`SELECT team_name, id, name, active FROM users WHERE tenant = $1 AND team_name = ANY($2) ORDER BY id`,
+		tenant(ctx), pq.Array(teamNames))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query team members: %w", err)
+	}
+	defer memberRows.Close()
+
+	for memberRows.Next() {
+		var (
+			teamName string
+			member   core.TeamMember
+		)
+		if err = memberRows.Scan(&teamName, &member.UserID, &member.Username, &member.IsActive); err != nil {
+			return nil, fmt.Errorf("failed to scan team member: %w", err)
+		}
+		if i, ok := index[teamName]; ok {
+			teams[i].Members = append(teams[i].Members, member)
+		}
+	}
+
+	if err = memberRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating team members: %w", err)
+	}
+
+	return teams, nil
+}
+
+func (db *DB) GetUsers(ctx context.Context, userIds []string) ([]core.User, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT id, name, team_name, active, out_of_office_until FROM users WHERE tenant = $1 AND id = ANY($2) ORDER BY id`,
+		tenant(ctx), pq.Array(userIds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []core.User
+
+	for rows.Next() {
+		var user core.User
+		err = rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.OutOfOfficeUntil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return users, nil
+}
+
+func (db *DB) GetPRs(ctx context.Context, prIds []string) ([]core.PullRequest, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT id, COALESCE(repository, ''), COALESCE(number, 0),
+                labels, lines_added, lines_removed, files_changed, priority,
+                title, author_id, state, created_at, merged_at, version
+         FROM pull_request WHERE tenant = $1 AND id = ANY($2) ORDER BY created_at, id`,
+		tenant(ctx), pq.Array(prIds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prs: %w", err)
+	}
+	defer rows.Close()
+
+	var pullRequests []core.PullRequest
+	index := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			pullRequest         core.PullRequest
+			createdAt, mergedAt *time.Time
+		)
+		err = rows.Scan(&pullRequest.PullRequestID, &pullRequest.Repository, &pullRequest.Number,
+			pq.Array(&pullRequest.Labels), &pullRequest.LinesAdded, &pullRequest.LinesRemoved,
+			&pullRequest.FilesChanged, &pullRequest.Priority,
+			&pullRequest.PullRequestName, &pullRequest.AuthorID,
+			&pullRequest.Status, &createdAt, &mergedAt, &pullRequest.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pr: %w", err)
+		}
+		pullRequest.CreatedAt = formatTime(createdAt)
+		pullRequest.MergedAt = formatTime(mergedAt)
+		index[pullRequest.PullRequestID] = len(pullRequests)
+		pullRequests = append(pullRequests, pullRequest)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating prs: %w", err)
+	}
+
+	assignments, err := db.GetAssignments(ctx, prIds)
+	if err != nil {
+		return nil, err
+	}
+	for prID, prAssignments := range assignments {
+		i, ok := index[prID]
+		if !ok {
+			continue
+		}
+		for _, assignment := range prAssignments {
+			pullRequests[i].AssignedReviewers = append(pullRequests[i].AssignedReviewers, assignment.ReviewerID)
+		}
+	}
+
+	return pullRequests, nil
+}
+
+func (db *DB) GetReviewsByUsers(ctx context.Context, userIds []string) (map[string][]core.PullRequest, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT pr_reviewers.reviewer_id, pr.id, COALESCE(pr.repository, ''), COALESCE(pr.number, 0),
+                pr.labels, pr.lines_added, pr.lines_removed, pr.files_changed, pr.priority,
+                pr.title, pr.author_id, pr.state, pr.created_at, pr.merged_at
+         FROM pull_request pr
+         JOIN pr_reviewers ON pr_reviewers.tenant = pr.tenant AND pr.id = pr_reviewers.pr_id
+         WHERE pr.tenant = $1 AND pr_reviewers.reviewer_id = ANY($2)
+         ORDER BY pr.created_at, pr.id`,
+		tenant(ctx), pq.Array(userIds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := make(map[string][]core.PullRequest)
+
+	for rows.Next() {
+		var (
+			reviewerID          string
+			pullRequest         core.PullRequest
+			createdAt, mergedAt *time.Time
+		)
+		err = rows.Scan(&reviewerID, &pullRequest.PullRequestID, &pullRequest.Repository, &pullRequest.Number,
+			pq.Array(&pullRequest.Labels), &pullRequest.LinesAdded, &pullRequest.LinesRemoved,
+			&pullRequest.FilesChanged, &pullRequest.Priority,
+			&pullRequest.PullRequestName, &pullRequest.AuthorID,
+			&pullRequest.Status, &createdAt, &mergedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		pullRequest.CreatedAt = formatTime(createdAt)
+		pullRequest.MergedAt = formatTime(mergedAt)
+		reviews[reviewerID] = append(reviews[reviewerID], pullRequest)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return reviews, nil
+}
+
+func (db *DB) GetAssignments(ctx context.Context, prIds []string) (map[string][]core.Assignment, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT pr_id, reviewer_id, approved_at FROM pr_reviewers WHERE tenant = $1 AND pr_id = ANY($2) ORDER BY id`,
+		tenant(ctx), pq.Array(prIds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := make(map[string][]core.Assignment)
+
+	for rows.Next() {
+		var assignment core.Assignment
+		if err = rows.Scan(&assignment.PullRequestID, &assignment.ReviewerID, &assignment.ApprovedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		assignments[assignment.PullRequestID] = append(assignments[assignment.PullRequestID], assignment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assignments: %w", err)
+	}
+
+	return assignments, nil
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"github.com/lib/pq"
+	"review-assigner/core"
+	"strings"
+	"time"
+)
+
+func (db *DB) ListPRs(ctx context.Context, filter core.PRFilter) ([]core.PullRequest, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions = append(conditions, "pr.tenant = "+arg(tenant(ctx)))
+	if filter.ReviewerID != "" {
+		conditions = append(conditions,
+			"EXISTS (SELECT 1 FROM pr_reviewers r WHERE r.tenant = pr.tenant AND r.pr_id = pr.id AND r.reviewer_id = "+
+				arg(filter.ReviewerID)+")")
+	}
+	if filter.AuthorID != "" {
+		conditions = append(conditions, "pr.author_id = "+arg(filter.AuthorID))
+	}
+	if filter.Repository != "" {
+		conditions = append(conditions, "pr.repository = "+arg(filter.Repository))
+	}
+	if filter.TeamName != "" {
+		conditions = append(conditions, "u.team_name = "+arg(filter.TeamName))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "pr.state = "+arg(filter.Status))
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "pr.created_at >= "+arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "pr.created_at < "+arg(*filter.CreatedTo))
+	}
+
+	direction, compare := "ASC", ">"
+	if filter.Descending {
+		direction, compare = "DESC", "<"
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(pr.created_at, pr.id) %s (%s, %s)",
+			compare, arg(filter.After.CreatedAt), arg(filter.After.ID)))
+	}
+
+	query := `SELECT pr.id, COALESCE(pr.repository, ''), COALESCE(pr.number, 0),
+                pr.labels, pr.lines_added, pr.lines_removed, pr.files_changed, pr.priority,
+                pr.title, pr.author_id, pr.state, pr.created_at, pr.merged_at, pr.version
+         FROM pull_request pr
+         JOIN users u ON u.tenant = pr.tenant AND u.id = pr.author_id
+         WHERE ` + strings.Join(conditions, " AND ")
+	query += fmt.Sprintf("\n         ORDER BY pr.created_at %s, pr.id %s LIMIT %s", direction, direction, arg(filter.Limit))
+
+	rows, err := db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prs: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		pullRequests []core.PullRequest
+		prIds        []string
+	)
+
+	for rows.Next() {
+		var (
+			pullRequest         core.PullRequest
+			createdAt, mergedAt *time.Time
+		)
+		err = rows.Scan(&pullRequest.PullRequestID, &pullRequest.Repository, &pullRequest.Number,
+			pq.Array(&pullRequest.Labels), &pullRequest.LinesAdded, &pullRequest.LinesRemoved,
+			&pullRequest.FilesChanged, &pullRequest.Priority,
+			&pullRequest.PullRequestName, &pullRequest.AuthorID,
+			&pullRequest.Status, &createdAt, &mergedAt, &pullRequest.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pr: %w", err)
+		}
+		pullRequest.CreatedAt = formatTime(createdAt)
+		pullRequest.MergedAt = formatTime(mergedAt)
+		pullRequests = append(pullRequests, pullRequest)
+		prIds = append(prIds, pullRequest.PullRequestID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating prs: %w", err)
+	}
+	rows.Close()
+
+	assignments, err := db.GetAssignments(ctx, prIds)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pullRequests {
+		for _, assignment := range assignments[pullRequests[i].PullRequestID] {
+			pullRequests[i].AssignedReviewers = append(pullRequests[i].AssignedReviewers, assignment.ReviewerID)
+		}
+	}
+
+	return pullRequests, nil
+}
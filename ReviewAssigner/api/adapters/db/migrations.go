@@ -4,23 +4,34 @@ import (
 	"embed"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/pgx"
+	"github.com/golang-migrate/migrate/v4/source"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
 //go:embed migrations/*.sql
 var migrationFiles embed.FS
 
-func (db *DB) Migrate() error {
-	db.log.Debug("running migration")
-	files, err := iofs.New(migrationFiles, "migrations")
+func (db *DB) Migrations() (source.Driver, error) {
+	return iofs.New(migrationFiles, "migrations")
+}
+
+func (db *DB) Migrator() (*migrate.Migrate, error) {
+	files, err := db.Migrations()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	driver, err := pgx.WithInstance(db.conn.DB, &pgx.Config{})
+	driver, err := pgx.WithInstance(db.pool.DB, &pgx.Config{})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	m, err := migrate.NewWithInstance("myiofs", files, "mypg", driver)
+	return migrate.NewWithInstance("myiofs", files, "mypg", driver)
+}
+
+// Migrate applies pending migrations. A dirty schema is left for the migrate
+// command to fix and is reported as migrate.ErrDirty.
+func (db *DB) Migrate() error {
+	db.log.Debug("running migration")
+	m, err := db.Migrator()
 	if err != nil {
 		return err
 	}
@@ -38,30 +49,3 @@ func (db *DB) Migrate() error {
 	db.log.Debug("migration finished")
 	return nil
 }
-
-func (d *DB) CleanMigrations() error {
-	d.log.Debug("cleaning migrations")
-
-	driver, err := pgx.WithInstance(d.conn.DB, &pgx.Config{})
-	if err != nil {
-		return err
-	}
-
-	source, err := iofs.New(migrationFiles, "migrations")
-	if err != nil {
-		return err
-	}
-
-	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
-	if err != nil {
-		return err
-	}
-
-	err = m.Force(1)
-	if err != nil {
-		d.log.Warn("force version failed", "error", err)
-	}
-
-	d.log.Debug("migrations cleaned")
-	return nil
-}
@@ -1 +1 @@
-DROP TABLE IF EXISTS teams;
\ No newline at end of file
+DROP TABLE IF EXISTS teams;
@@ -1 +1 @@
-DROP TABLE IF EXISTS pull_requests;
\ No newline at end of file
+DROP TABLE IF EXISTS pull_request;
@@ -1 +1 @@
-DROP TABLE IF EXISTS pr_reviewers;
\ No newline at end of file
+DROP TABLE IF EXISTS pr_reviewers;
@@ -0,0 +1,3 @@
+ALTER TABLE pull_request
+    DROP COLUMN IF EXISTS merged_at,
+    DROP COLUMN IF EXISTS created_at;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE pull_request
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS merged_at TIMESTAMPTZ;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS out_of_office_until TIMESTAMPTZ;
\ No newline at end of file
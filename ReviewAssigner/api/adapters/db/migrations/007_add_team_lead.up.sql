@@ -0,0 +1,2 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS lead_id VARCHAR(100) REFERENCES users(id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS pr_history (
+    id SERIAL PRIMARY KEY,
+    pr_id VARCHAR(16) NOT NULL,
+    event VARCHAR(32) NOT NULL,
+    actor_id VARCHAR(100),
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (pr_id) REFERENCES pull_request(id) ON DELETE CASCADE
+    );
+
+CREATE INDEX IF NOT EXISTS pr_history_pr_id_idx ON pr_history (pr_id, created_at);
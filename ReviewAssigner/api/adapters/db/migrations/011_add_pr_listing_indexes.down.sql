@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS users_team_name_idx;
+DROP INDEX IF EXISTS pr_reviewers_reviewer_id_idx;
+DROP INDEX IF EXISTS pull_request_state_created_at_idx;
+DROP INDEX IF EXISTS pull_request_author_created_at_idx;
+DROP INDEX IF EXISTS pull_request_created_at_idx;
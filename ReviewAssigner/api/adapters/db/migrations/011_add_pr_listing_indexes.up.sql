@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS pull_request_created_at_idx ON pull_request (created_at, id);
+CREATE INDEX IF NOT EXISTS pull_request_author_created_at_idx ON pull_request (author_id, created_at, id);
+CREATE INDEX IF NOT EXISTS pull_request_state_created_at_idx ON pull_request (state, created_at, id);
+CREATE INDEX IF NOT EXISTS pr_reviewers_reviewer_id_idx ON pr_reviewers (reviewer_id, pr_id);
+CREATE INDEX IF NOT EXISTS users_team_name_idx ON users (team_name);
@@ -0,0 +1,2 @@
+ALTER TABLE pull_request
+    DROP COLUMN IF EXISTS version;
@@ -0,0 +1,2 @@
+ALTER TABLE pull_request
+    ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
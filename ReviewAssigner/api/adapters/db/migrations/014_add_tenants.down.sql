@@ -0,0 +1,61 @@
+-- Before tenants every id was global, so only the default tenant fits back
+-- into the old schema; the other tenants would collide on their ids.
+UPDATE teams SET lead_id = NULL WHERE tenant <> 'default';
+DELETE FROM api_tokens WHERE tenant <> 'default';
+DELETE FROM pr_history WHERE tenant <> 'default';
+DELETE FROM pr_reviewers WHERE tenant <> 'default';
+DELETE FROM pull_request WHERE tenant <> 'default';
+DELETE FROM users WHERE tenant <> 'default';
+DELETE FROM teams WHERE tenant <> 'default';
+DELETE FROM idempotency_keys WHERE scope LIKE '%/%';
+
+DROP INDEX IF EXISTS api_tokens_tenant_idx;
+DROP INDEX IF EXISTS pull_request_created_at_idx;
+DROP INDEX IF EXISTS pull_request_author_created_at_idx;
+DROP INDEX IF EXISTS pull_request_state_created_at_idx;
+DROP INDEX IF EXISTS pr_reviewers_reviewer_id_idx;
+DROP INDEX IF EXISTS users_team_name_idx;
+DROP INDEX IF EXISTS pr_history_pr_id_idx;
+
+ALTER TABLE teams DROP CONSTRAINT teams_lead_id_fkey;
+ALTER TABLE users DROP CONSTRAINT users_team_name_fkey;
+ALTER TABLE pull_request DROP CONSTRAINT pull_request_author_id_fkey;
+ALTER TABLE pr_reviewers DROP CONSTRAINT pr_reviewers_pr_id_fkey;
+ALTER TABLE pr_reviewers DROP CONSTRAINT pr_reviewers_reviewer_id_fkey;
+ALTER TABLE pr_history DROP CONSTRAINT pr_history_pr_id_fkey;
+ALTER TABLE api_tokens DROP CONSTRAINT api_tokens_user_id_fkey;
+
+ALTER TABLE teams DROP CONSTRAINT teams_tenant_name_key;
+ALTER TABLE users DROP CONSTRAINT users_pkey;
+ALTER TABLE pull_request DROP CONSTRAINT pull_request_pkey;
+ALTER TABLE pr_reviewers DROP CONSTRAINT pr_reviewers_tenant_pr_id_reviewer_id_key;
+
+ALTER TABLE teams ADD CONSTRAINT teams_name_key UNIQUE (name);
+ALTER TABLE users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+ALTER TABLE pull_request ADD CONSTRAINT pull_request_pkey PRIMARY KEY (id);
+ALTER TABLE pr_reviewers ADD CONSTRAINT pr_reviewers_pr_id_reviewer_id_key UNIQUE (pr_id, reviewer_id);
+
+ALTER TABLE teams ADD CONSTRAINT teams_lead_id_fkey FOREIGN KEY (lead_id) REFERENCES users (id);
+ALTER TABLE users ADD CONSTRAINT users_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams (name);
+ALTER TABLE pull_request ADD CONSTRAINT pull_request_author_id_fkey FOREIGN KEY (author_id) REFERENCES users (id);
+ALTER TABLE pr_reviewers ADD CONSTRAINT pr_reviewers_pr_id_fkey
+    FOREIGN KEY (pr_id) REFERENCES pull_request (id) ON DELETE CASCADE;
+ALTER TABLE pr_reviewers ADD CONSTRAINT pr_reviewers_reviewer_id_fkey FOREIGN KEY (reviewer_id) REFERENCES users (id);
+ALTER TABLE pr_history ADD CONSTRAINT pr_history_pr_id_fkey
+    FOREIGN KEY (pr_id) REFERENCES pull_request (id) ON DELETE CASCADE;
+ALTER TABLE api_tokens ADD CONSTRAINT api_tokens_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS pull_request_created_at_idx ON pull_request (created_at, id);
+CREATE INDEX IF NOT EXISTS pull_request_author_created_at_idx ON pull_request (author_id, created_at, id);
+CREATE INDEX IF NOT EXISTS pull_request_state_created_at_idx ON pull_request (state, created_at, id);
+CREATE INDEX IF NOT EXISTS pr_reviewers_reviewer_id_idx ON pr_reviewers (reviewer_id, pr_id);
+CREATE INDEX IF NOT EXISTS users_team_name_idx ON users (team_name);
+CREATE INDEX IF NOT EXISTS pr_history_pr_id_idx ON pr_history (pr_id, created_at);
+
+ALTER TABLE api_tokens DROP COLUMN tenant;
+ALTER TABLE pr_history DROP COLUMN tenant;
+ALTER TABLE pr_reviewers DROP COLUMN tenant;
+ALTER TABLE pull_request DROP COLUMN tenant;
+ALTER TABLE users DROP COLUMN tenant;
+ALTER TABLE teams DROP COLUMN tenant;
@@ -0,0 +1,54 @@
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS tenant VARCHAR(100) NOT NULL DEFAULT 'default';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant VARCHAR(100) NOT NULL DEFAULT 'default';
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS tenant VARCHAR(100) NOT NULL DEFAULT 'default';
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS tenant VARCHAR(100) NOT NULL DEFAULT 'default';
+ALTER TABLE pr_history ADD COLUMN IF NOT EXISTS tenant VARCHAR(100) NOT NULL DEFAULT 'default';
+ALTER TABLE api_tokens ADD COLUMN IF NOT EXISTS tenant VARCHAR(100) NOT NULL DEFAULT 'default';
+
+ALTER TABLE teams DROP CONSTRAINT teams_lead_id_fkey;
+ALTER TABLE users DROP CONSTRAINT users_team_name_fkey;
+ALTER TABLE pull_request DROP CONSTRAINT pull_request_author_id_fkey;
+ALTER TABLE pr_reviewers DROP CONSTRAINT pr_reviewers_pr_id_fkey;
+ALTER TABLE pr_reviewers DROP CONSTRAINT pr_reviewers_reviewer_id_fkey;
+ALTER TABLE pr_history DROP CONSTRAINT pr_history_pr_id_fkey;
+ALTER TABLE api_tokens DROP CONSTRAINT api_tokens_user_id_fkey;
+
+ALTER TABLE teams DROP CONSTRAINT teams_name_key;
+ALTER TABLE users DROP CONSTRAINT users_pkey;
+ALTER TABLE pull_request DROP CONSTRAINT pull_request_pkey;
+ALTER TABLE pr_reviewers DROP CONSTRAINT pr_reviewers_pr_id_reviewer_id_key;
+
+ALTER TABLE teams ADD CONSTRAINT teams_tenant_name_key UNIQUE (tenant, name);
+ALTER TABLE users ADD CONSTRAINT users_pkey PRIMARY KEY (tenant, id);
+ALTER TABLE pull_request ADD CONSTRAINT pull_request_pkey PRIMARY KEY (tenant, id);
+ALTER TABLE pr_reviewers ADD CONSTRAINT pr_reviewers_tenant_pr_id_reviewer_id_key UNIQUE (tenant, pr_id, reviewer_id);
+
+ALTER TABLE teams ADD CONSTRAINT teams_lead_id_fkey
+    FOREIGN KEY (tenant, lead_id) REFERENCES users (tenant, id);
+ALTER TABLE users ADD CONSTRAINT users_team_name_fkey
+    FOREIGN KEY (tenant, team_name) REFERENCES teams (tenant, name);
+ALTER TABLE pull_request ADD CONSTRAINT pull_request_author_id_fkey
+    FOREIGN KEY (tenant, author_id) REFERENCES users (tenant, id);
+ALTER TABLE pr_reviewers ADD CONSTRAINT pr_reviewers_pr_id_fkey
+    FOREIGN KEY (tenant, pr_id) REFERENCES pull_request (tenant, id) ON DELETE CASCADE;
+ALTER TABLE pr_reviewers ADD CONSTRAINT pr_reviewers_reviewer_id_fkey
+    FOREIGN KEY (tenant, reviewer_id) REFERENCES users (tenant, id);
+ALTER TABLE pr_history ADD CONSTRAINT pr_history_pr_id_fkey
+    FOREIGN KEY (tenant, pr_id) REFERENCES pull_request (tenant, id) ON DELETE CASCADE;
+ALTER TABLE api_tokens ADD CONSTRAINT api_tokens_user_id_fkey
+    FOREIGN KEY (tenant, user_id) REFERENCES users (tenant, id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS pull_request_created_at_idx;
+DROP INDEX IF EXISTS pull_request_author_created_at_idx;
+DROP INDEX IF EXISTS pull_request_state_created_at_idx;
+DROP INDEX IF EXISTS pr_reviewers_reviewer_id_idx;
+DROP INDEX IF EXISTS users_team_name_idx;
+DROP INDEX IF EXISTS pr_history_pr_id_idx;
+
+CREATE INDEX IF NOT EXISTS pull_request_created_at_idx ON pull_request (tenant, created_at, id);
+CREATE INDEX IF NOT EXISTS pull_request_author_created_at_idx ON pull_request (tenant, author_id, created_at, id);
+CREATE INDEX IF NOT EXISTS pull_request_state_created_at_idx ON pull_request (tenant, state, created_at, id);
+CREATE INDEX IF NOT EXISTS pr_reviewers_reviewer_id_idx ON pr_reviewers (tenant, reviewer_id, pr_id);
+CREATE INDEX IF NOT EXISTS users_team_name_idx ON users (tenant, team_name);
+CREATE INDEX IF NOT EXISTS pr_history_pr_id_idx ON pr_history (tenant, pr_id, created_at);
+CREATE INDEX IF NOT EXISTS api_tokens_tenant_idx ON api_tokens (tenant, id);
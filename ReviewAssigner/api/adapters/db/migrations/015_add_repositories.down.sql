@@ -0,0 +1,9 @@
+ALTER TABLE pull_request DROP CONSTRAINT IF EXISTS pull_request_number_check;
+ALTER TABLE pull_request DROP CONSTRAINT IF EXISTS pull_request_tenant_repository_number_key;
+ALTER TABLE pull_request DROP CONSTRAINT IF EXISTS pull_request_repository_fkey;
+
+ALTER TABLE pull_request DROP COLUMN IF EXISTS number;
+ALTER TABLE pull_request DROP COLUMN IF EXISTS repository;
+
+DROP TABLE IF EXISTS repository_teams;
+DROP TABLE IF EXISTS repositories;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS repositories (
+    tenant VARCHAR(100) NOT NULL DEFAULT 'default',
+    name VARCHAR(100) NOT NULL,
+    PRIMARY KEY (tenant, name)
+);
+
+CREATE TABLE IF NOT EXISTS repository_teams (
+    tenant VARCHAR(100) NOT NULL DEFAULT 'default',
+    repository VARCHAR(100) NOT NULL,
+    team_name VARCHAR(100) NOT NULL,
+    PRIMARY KEY (tenant, repository, team_name),
+    FOREIGN KEY (tenant, repository) REFERENCES repositories (tenant, name) ON DELETE CASCADE,
+    FOREIGN KEY (tenant, team_name) REFERENCES teams (tenant, name) ON DELETE CASCADE
+);
+
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS repository VARCHAR(100);
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS number BIGINT;
+
+ALTER TABLE pull_request ADD CONSTRAINT pull_request_repository_fkey
+    FOREIGN KEY (tenant, repository) REFERENCES repositories (tenant, name);
+ALTER TABLE pull_request ADD CONSTRAINT pull_request_tenant_repository_number_key
+    UNIQUE (tenant, repository, number);
+ALTER TABLE pull_request ADD CONSTRAINT pull_request_number_check
+    CHECK ((repository IS NULL) = (number IS NULL) AND (number IS NULL OR number > 0));
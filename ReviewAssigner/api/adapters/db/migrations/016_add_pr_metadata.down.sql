@@ -0,0 +1,8 @@
+ALTER TABLE pull_request DROP CONSTRAINT IF EXISTS pull_request_priority_check;
+ALTER TABLE pull_request DROP CONSTRAINT IF EXISTS pull_request_size_check;
+
+ALTER TABLE pull_request DROP COLUMN IF EXISTS priority;
+ALTER TABLE pull_request DROP COLUMN IF EXISTS files_changed;
+ALTER TABLE pull_request DROP COLUMN IF EXISTS lines_removed;
+ALTER TABLE pull_request DROP COLUMN IF EXISTS lines_added;
+ALTER TABLE pull_request DROP COLUMN IF EXISTS labels;
@@ -0,0 +1,10 @@
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS lines_added INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS lines_removed INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS files_changed INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS priority VARCHAR(10) NOT NULL DEFAULT 'NORMAL';
+
+ALTER TABLE pull_request ADD CONSTRAINT pull_request_size_check
+    CHECK (lines_added >= 0 AND lines_removed >= 0 AND files_changed >= 0);
+ALTER TABLE pull_request ADD CONSTRAINT pull_request_priority_check
+    CHECK (priority IN ('LOW', 'NORMAL', 'HIGH', 'URGENT'));
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"review-assigner/core"
+	"testing"
+	"time"
+)
+
+// TestMigrationsRoundTrip rolls the schema at TEST_DB_ADDRESS back past
+// tenants with the same ids in two tenants, then migrates up again.
+func TestMigrationsRoundTrip(t *testing.T) {
+	address := os.Getenv("TEST_DB_ADDRESS")
+	if address == "" {
+		t.Skip("TEST_DB_ADDRESS is not set")
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	admin, err := New(log, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.pool.Close() })
+
+	schema := fmt.Sprintf("migrations_%d", time.Now().UnixNano())
+	if _, err := admin.pool.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.pool.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	db, err := New(log, withSearchPath(address, schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.pool.Close() })
+
+	m, err := db.Migrator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	ctx := context.Background()
+	for _, tenant := range []string{core.DefaultTenant, "acme"} {
+		team := core.Team{TeamName: "backend", LeadID: "u1", Members: []core.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+		}}
+		if err := db.AddTeam(core.WithTenant(ctx, tenant), team); err != nil {
+			t.Fatalf("add team to %s: %v", tenant, err)
+		}
+	}
+
+	if err := m.Migrate(13); err != nil {
+		t.Fatalf("down to 13: %v", err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("up again: %v", err)
+	}
+
+	team, err := db.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("default tenant lost its team: %v", err)
+	}
+	if team.LeadID != "u1" || len(team.Members) != 2 {
+		t.Errorf("got team %+v after the round trip", team)
+	}
+
+	if err := m.Down(); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("up from scratch: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"review-assigner/core"
+)
+
+func (db *DB) AddRepository(ctx context.Context, repository core.Repository) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO repositories (tenant, name) VALUES ($1, $2)`,
+			tenant(ctx), repository.Name)
+		if err != nil {
+			if isUniqueConstraintError(err) {
+				return core.ErrRepositoryExists
+			}
+			return fmt.Errorf("failed to add repository: %w", err)
+		}
+
+		return linkTeams(ctx, tx, repository)
+	})
+}
+
+func linkTeams(ctx context.Context, tx *sql.Tx, repository core.Repository) error {
+	for _, teamName := range repository.Teams {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO repository_teams (tenant, repository, team_name) VALUES ($1, $2, $3)
+         ON CONFLICT (tenant, repository, team_name) DO NOTHING`,
+			tenant(ctx), repository.Name, teamName)
+		if err != nil {
+			return fmt.Errorf("failed to link repository: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) LinkRepository(ctx context.Context, repository string, teamName string) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		return linkTeams(ctx, tx, core.Repository{Name: repository, Teams: []string{teamName}})
+	})
+}
+
+func (db *DB) GetRepository(ctx context.Context, name string) (core.Repository, error) {
+	var exists bool
+	err := db.conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM repositories WHERE tenant = $1 AND name = $2)`,
+		tenant(ctx), name).Scan(&exists)
+	if err != nil {
+		return core.Repository{}, fmt.Errorf("failed to get repository: %w", err)
+	}
+	if !exists {
+		return core.Repository{}, core.ErrRepositoryNotFound
+	}
+
+	repositories, err := db.getRepositories(ctx, name)
+	if err != nil {
+		return core.Repository{}, err
+	}
+
+	return repositories[0], nil
+}
+
+func (db *DB) GetRepositories(ctx context.Context) ([]core.Repository, error) {
+	return db.getRepositories(ctx, "")
+}
+
+// getRepositories lists the repositories of the tenant with their teams, or
+// only the named one.
+func (db *DB) getRepositories(ctx context.Context, name string) ([]core.Repository, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT r.name, rt.team_name
+         FROM repositories r
+         LEFT JOIN repository_teams rt ON rt.tenant = r.tenant AND rt.repository = r.name
+         WHERE r.tenant = $1 AND ($2 = '' OR r.name = $2)
+         ORDER BY r.name, rt.team_name`,
+		tenant(ctx), name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query repositories: %w", err)
+	}
+	defer rows.Close()
+
+	var repositories []core.Repository
+
+	for rows.Next() {
+		var (
+			repository string
+			teamName   sql.NullString
+		)
+		if err = rows.Scan(&repository, &teamName); err != nil {
+			return nil, fmt.Errorf("failed to scan repository: %w", err)
+		}
+		if len(repositories) == 0 || repositories[len(repositories)-1].Name != repository {
+			repositories = append(repositories, core.Repository{Name: repository})
+		}
+		if teamName.Valid {
+			last := &repositories[len(repositories)-1]
+			last.Teams = append(last.Teams, teamName.String)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating repositories: %w", err)
+	}
+
+	return repositories, nil
+}
+
+// ImportRepository creates the repository or replaces its teams.
+func (db *DB) ImportRepository(ctx context.Context, repository core.Repository) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO repositories (tenant, name) VALUES ($1, $2) ON CONFLICT (tenant, name) DO NOTHING`,
+			tenant(ctx), repository.Name)
+		if err != nil {
+			return fmt.Errorf("failed to import repository: %w", err)
+		}
+
+		if _, err = tx.ExecContext(ctx, `DELETE FROM repository_teams WHERE tenant = $1 AND repository = $2`,
+			tenant(ctx), repository.Name); err != nil {
+			return fmt.Errorf("failed to clear repository teams: %w", err)
+		}
+
+		return linkTeams(ctx, tx, repository)
+	})
+}
+
+func (db *DB) FindPR(ctx context.Context, repository string, number int64) (string, error) {
+	var prId string
+	err := db.conn.QueryRowContext(ctx,
+		`SELECT id FROM pull_request WHERE tenant = $1 AND repository = $2 AND number = $3`,
+		tenant(ctx), repository, number).Scan(&prId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", core.ErrPRNotFound
+		}
+		return "", fmt.Errorf("failed to find pr: %w", err)
+	}
+
+	return prId, nil
+}
@@ -3,17 +3,23 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"log/slog"
 	"review-assigner/core"
-	"strings"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
 )
 
 type DB struct {
 	log  *slog.Logger
-	conn *sqlx.DB
+	pool *sqlx.DB
+	conn executor
+	tx   *sql.Tx
 }
 
 func New(log *slog.Logger, address string) (*DB, error) {
@@ -26,129 +32,142 @@ func New(log *slog.Logger, address string) (*DB, error) {
 
 	return &DB{
 		log:  log,
+		pool: db,
 		conn: db,
 	}, nil
 }
 
-func isUniqueConstraintError(err error) bool {
-	if pgErr, ok := err.(*pq.Error); ok {
-		return pgErr.Code == "23505"
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
 	}
-	return false
+	formatted := t.UTC().Format(time.RFC3339Nano)
+	return &formatted
+}
+
+// tenant scopes every query to the organisation the request belongs to.
+func tenant(ctx context.Context) string {
+	return core.TenantFrom(ctx)
+}
+
+func isUniqueConstraintError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation
 }
 
 func (db *DB) AddUserTX(ctx context.Context, tx *sql.Tx, user core.User) error {
 
-	stmt, err := tx.Prepare("INSERT INTO users (id,name,team_name,active) VALUES ($1, $2,$3,$4)")
+	stmt, err := tx.Prepare("INSERT INTO users (tenant,id,name,team_name,active) VALUES ($1, $2,$3,$4,$5)")
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, user.UserID, user.Username, user.TeamName, user.IsActive)
+	_, err = stmt.ExecContext(ctx, tenant(ctx), user.UserID, user.Username, user.TeamName, user.IsActive)
 
 	return err
 }
 
 func (db *DB) AddTeam(ctx context.Context, team core.Team) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, "INSERT INTO teams (tenant, name) VALUES ($1, $2)")
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
 
-	tx, err := db.conn.Begin()
-	if err != nil {
-		return err
-	}
-
-	defer func() {
+		_, err = stmt.ExecContext(ctx, tenant(ctx), team.TeamName)
 		if err != nil {
-			tx.Rollback()
+			if isUniqueConstraintError(err) {
+				return core.ErrTeamAlreadyExists
+			}
+			return err
 		}
-	}()
 
-	stmt, err := tx.Prepare("INSERT INTO teams (name) VALUES ($1)")
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
+		for _, member := range team.Members {
 
-	_, err = stmt.ExecContext(ctx, team.TeamName)
-	if err != nil {
-		if strings.Contains(err.Error(), "23505") {
-			return core.ErrTeamAlreadyExists
+			user := core.User{
+				UserID:   member.UserID,
+				Username: member.Username,
+				TeamName: team.TeamName,
+				IsActive: member.IsActive,
+			}
+			err = db.AddUserTX(ctx, tx, user)
+			if err != nil {
+				return err
+			}
 		}
-		return err
-	}
-
-	for _, member := range team.Members {
 
-		user := core.User{member.UserID, member.Username, team.TeamName, member.IsActive}
-		err = db.AddUserTX(ctx, tx, user)
-		if err != nil {
-			return err
+		if team.LeadID != "" {
+			_, err = tx.ExecContext(ctx, "UPDATE teams SET lead_id = $1 WHERE tenant = $2 AND name = $3",
+				team.LeadID, tenant(ctx), team.TeamName)
+			if err != nil {
+				return err
+			}
 		}
-	}
 
-	return tx.Commit()
+		return nil
+	})
 }
 
 func (db *DB) AddPR(ctx context.Context, pullRequest core.PullRequest) error {
-
-	tx, err := db.conn.Begin()
-	if err != nil {
-		return err
-	}
-
-	defer func() {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		prstmt, err := tx.PrepareContext(ctx,
+			`INSERT INTO pull_request (tenant, id, repository, number, title, author_id, state,
+                                   labels, lines_added, lines_removed, files_changed, priority)
+         VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, 0), $5, $6, $7,
+                 COALESCE($8::TEXT[], '{}'), $9, $10, $11, COALESCE(NULLIF($12, ''), 'NORMAL'))`)
 		if err != nil {
-			tx.Rollback()
+			return err
 		}
-	}()
+		defer prstmt.Close()
 
-	prstmt, err := tx.Prepare("INSERT INTO pull_request (id,title,author_id,state) VALUES ($1, $2,$3,$4)")
-	if err != nil {
-		return err
-	}
-	defer prstmt.Close()
-
-	_, err = prstmt.ExecContext(ctx, pullRequest.PullRequestID, pullRequest.PullRequestName, pullRequest.AuthorID, "OPEN")
-	if err != nil {
-		if strings.Contains(err.Error(), "23505") {
-			return core.ErrPRAAlreadyExists
+		_, err = prstmt.ExecContext(ctx, tenant(ctx), pullRequest.PullRequestID, pullRequest.Repository, pullRequest.Number,
+			pullRequest.PullRequestName, pullRequest.AuthorID, "OPEN", pq.Array(pullRequest.Labels),
+			pullRequest.LinesAdded, pullRequest.LinesRemoved, pullRequest.FilesChanged, pullRequest.Priority)
+		if err != nil {
+			if isUniqueConstraintError(err) {
+				return core.ErrPRAAlreadyExists
+			}
+			return err
 		}
-	}
 
-	reviewerStmt, err := tx.Prepare("INSERT INTO pr_reviewers (pr_id, reviewer_id) VALUES ($1, $2)")
-	if err != nil {
-		return fmt.Errorf("prepare reviewer statement: %w", err)
-	}
-	defer reviewerStmt.Close()
-
-	for _, reviewer := range pullRequest.AssignedReviewers {
-		_, err = reviewerStmt.ExecContext(ctx, pullRequest.PullRequestID, reviewer)
+		reviewerStmt, err := tx.PrepareContext(ctx, "INSERT INTO pr_reviewers (tenant, pr_id, reviewer_id) VALUES ($1, $2, $3)")
 		if err != nil {
-			return err
+			return fmt.Errorf("prepare reviewer statement: %w", err)
 		}
-	}
+		defer reviewerStmt.Close()
 
-	return tx.Commit()
+		for _, reviewer := range pullRequest.AssignedReviewers {
+			_, err = reviewerStmt.ExecContext(ctx, tenant(ctx), pullRequest.PullRequestID, reviewer)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
 func (db *DB) GetTeam(ctx context.Context, teamName string) (core.Team, error) {
 
 	var (
-		exists bool
+		leadID sql.NullString
 		team   core.Team
 	)
 
-	err := db.conn.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM teams WHERE name = $1)", teamName).Scan(&exists)
+	err := db.conn.QueryRowContext(ctx, "SELECT lead_id FROM teams WHERE tenant = $1 AND name = $2", tenant(ctx), teamName).Scan(&leadID)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return core.Team{}, core.ErrTeamNotFound
+		}
 		return core.Team{}, err
 	}
-	if !exists {
-		return core.Team{}, core.ErrTeamNotFound
-	}
 	team.TeamName = teamName
+	team.LeadID = leadID.String
 
 	rows, err := db.conn.QueryContext(ctx,
-		"SELECT id,name,active FROM users WHERE team_name = $1", teamName)
+		"SELECT id,name,active FROM users WHERE tenant = $1 AND team_name = $2", tenant(ctx), teamName)
 	if err != nil {
 		return core.Team{}, err
 	}
@@ -171,13 +190,31 @@ func (db *DB) GetTeam(ctx context.Context, teamName string) (core.Team, error) {
 
 }
 
+func (db *DB) SetTeamLead(ctx context.Context, teamName string, userId string) error {
+	result, err := db.conn.ExecContext(ctx, "UPDATE teams SET lead_id = NULLIF($1, '') WHERE tenant = $2 AND name = $3",
+		userId, tenant(ctx), teamName)
+	if err != nil {
+		return fmt.Errorf("failed to set team lead: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return core.ErrTeamNotFound
+	}
+
+	return nil
+}
+
 func (db *DB) GetUser(ctx context.Context, userId string) (core.User, error) {
 	var user core.User
 
 	err := db.conn.QueryRowContext(ctx,
-		"SELECT id, name, team_name, active FROM users WHERE id = $1",
-		userId,
-	).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive)
+		"SELECT id, name, team_name, active, out_of_office_until FROM users WHERE tenant = $1 AND id = $2",
+		tenant(ctx), userId,
+	).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.OutOfOfficeUntil)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -195,10 +232,31 @@ func (db *DB) IsActive(ctx context.Context, userId string, status bool) (core.Us
 	err := db.conn.QueryRowContext(
 		ctx,
 		`UPDATE users SET active = $1
-         WHERE id = $2 
-         RETURNING id, name, team_name, active`,
-		status, userId,
-	).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive)
+         WHERE tenant = $2 AND id = $3
+         RETURNING id, name, team_name, active, out_of_office_until`,
+		status, tenant(ctx), userId,
+	).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.OutOfOfficeUntil)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return core.User{}, core.ErrUserNotFound
+		}
+		return core.User{}, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (db *DB) SetOutOfOffice(ctx context.Context, userId string, until *time.Time) (core.User, error) {
+	var user core.User
+
+	err := db.conn.QueryRowContext(
+		ctx,
+		`UPDATE users SET out_of_office_until = $1
+         WHERE tenant = $2 AND id = $3
+         RETURNING id, name, team_name, active, out_of_office_until`,
+		until, tenant(ctx), userId,
+	).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.OutOfOfficeUntil)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -210,23 +268,34 @@ func (db *DB) IsActive(ctx context.Context, userId string, status bool) (core.Us
 	return user, nil
 }
 
-func (db *DB) Merged(ctx context.Context, prId string) (core.PullRequest, error) {
-	var pullRequest core.PullRequest
+func (db *DB) Merged(ctx context.Context, prId string, version int64) (core.PullRequest, error) {
+	var (
+		pullRequest         core.PullRequest
+		createdAt, mergedAt *time.Time
+	)
 
 	err := db.conn.QueryRowContext(
 		ctx,
-		`UPDATE pull_request SET state = 'MERGED'
-         WHERE id = $1 and state='OPEN'
-         RETURNING id, title, author_id, state`,
-		prId,
-	).Scan(&pullRequest.PullRequestID, &pullRequest.PullRequestName, &pullRequest.AuthorID, &pullRequest.Status)
+		`UPDATE pull_request SET state = 'MERGED', merged_at = now(), version = version + 1
+         WHERE tenant = $1 AND id = $2 AND state = 'OPEN' AND version = $3
+         RETURNING id, COALESCE(repository, ''), COALESCE(number, 0),
+                   labels, lines_added, lines_removed, files_changed, priority,
+                   title, author_id, state, created_at, merged_at, version`,
+		tenant(ctx), prId, version,
+	).Scan(&pullRequest.PullRequestID, &pullRequest.Repository, &pullRequest.Number,
+		pq.Array(&pullRequest.Labels), &pullRequest.LinesAdded, &pullRequest.LinesRemoved,
+		&pullRequest.FilesChanged, &pullRequest.Priority,
+		&pullRequest.PullRequestName, &pullRequest.AuthorID, &pullRequest.Status,
+		&createdAt, &mergedAt, &pullRequest.Version)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return core.PullRequest{}, fmt.Errorf("pr %s not found", prId)
+			return core.PullRequest{}, prConflict(ctx, db.conn, prId)
 		}
 		return core.PullRequest{}, fmt.Errorf("failed to update pr: %w", err)
 	}
+	pullRequest.CreatedAt = formatTime(createdAt)
+	pullRequest.MergedAt = formatTime(mergedAt)
 
 	pullRequest.AssignedReviewers, err = db.getReviewers(ctx, prId)
 	if err != nil {
@@ -237,15 +306,24 @@ func (db *DB) Merged(ctx context.Context, prId string) (core.PullRequest, error)
 }
 
 func (db *DB) GetPRDetailsWithReviewers(ctx context.Context, prId string) (core.PullRequest, error) {
-	var pullRequest core.PullRequest
+	var (
+		pullRequest         core.PullRequest
+		createdAt, mergedAt *time.Time
+	)
 
 	err := db.conn.QueryRowContext(
 		ctx,
-		`SELECT id, title, author_id, state 
+		`SELECT id, COALESCE(repository, ''), COALESCE(number, 0),
+                labels, lines_added, lines_removed, files_changed, priority,
+                title, author_id, state, created_at, merged_at, version
          FROM pull_request
-         WHERE id = $1`,
-		prId,
-	).Scan(&pullRequest.PullRequestID, &pullRequest.PullRequestName, &pullRequest.AuthorID, &pullRequest.Status)
+         WHERE tenant = $1 AND id = $2`,
+		tenant(ctx), prId,
+	).Scan(&pullRequest.PullRequestID, &pullRequest.Repository, &pullRequest.Number,
+		pq.Array(&pullRequest.Labels), &pullRequest.LinesAdded, &pullRequest.LinesRemoved,
+		&pullRequest.FilesChanged, &pullRequest.Priority,
+		&pullRequest.PullRequestName, &pullRequest.AuthorID, &pullRequest.Status,
+		&createdAt, &mergedAt, &pullRequest.Version)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -253,6 +331,8 @@ func (db *DB) GetPRDetailsWithReviewers(ctx context.Context, prId string) (core.
 		}
 		return core.PullRequest{}, fmt.Errorf("failed to get pr: %w", err)
 	}
+	pullRequest.CreatedAt = formatTime(createdAt)
+	pullRequest.MergedAt = formatTime(mergedAt)
 
 	reviewers, err := db.getReviewers(ctx, prId)
 	if err != nil {
@@ -268,8 +348,8 @@ func (db *DB) getReviewers(ctx context.Context, prId string) ([]string, error) {
 
 	rows, err := db.conn.QueryContext(
 		ctx,
-		`SELECT reviewer_id FROM pr_reviewers WHERE pr_id = $1`,
-		prId,
+		`SELECT reviewer_id FROM pr_reviewers WHERE tenant = $1 AND pr_id = $2 ORDER BY id`,
+		tenant(ctx), prId,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get reviewers: %w", err)
@@ -291,33 +371,222 @@ func (db *DB) getReviewers(ctx context.Context, prId string) ([]string, error) {
 	return reviewers, nil
 }
 
-func (db *DB) Reassign(ctx context.Context, oldReviewer core.ReassignReviewer, newReviewer string) error {
-
-	result, err := db.conn.ExecContext(
-		ctx,
-		`UPDATE pr_reviewers SET reviewer_id = $1
-         WHERE pr_id = $2 AND reviewer_id = $3`,
-		newReviewer, oldReviewer.PRId, oldReviewer.UserID,
-	)
+// bumpPRVersion locks an open PR for the rest of the transaction and advances
+// its version. A zero version skips the comparison.
+func bumpPRVersion(ctx context.Context, tx *sql.Tx, prId string, version int64) error {
+	result, err := tx.ExecContext(ctx,
+		`UPDATE pull_request SET version = version + 1
+         WHERE tenant = $1 AND id = $2 AND state = 'OPEN' AND ($3::BIGINT = 0 OR version = $3)`,
+		tenant(ctx), prId, version)
+	if err != nil {
+		return fmt.Errorf("failed to update pr version: %w", err)
+	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("pr %s not found or reviewer not assigned", oldReviewer.PRId)
+		return prConflict(ctx, tx, prId)
 	}
 
 	return nil
 }
 
+// prConflict tells a PR that does not exist from one that was changed since
+// it was read.
+func prConflict(ctx context.Context, conn executor, prId string) error {
+	var exists bool
+	err := conn.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM pull_request WHERE tenant = $1 AND id = $2)",
+		tenant(ctx), prId).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check pr: %w", err)
+	}
+	if !exists {
+		return core.ErrPRNotFound
+	}
+	return core.ErrPRConflict
+}
+
+func (db *DB) Reassign(ctx context.Context, oldReviewer core.ReassignReviewer, newReviewer string, version int64) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		if err := bumpPRVersion(ctx, tx, oldReviewer.PRId, version); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(
+			ctx,
+			`UPDATE pr_reviewers SET reviewer_id = $1, approved_at = NULL
+         WHERE tenant = $2 AND pr_id = $3 AND reviewer_id = $4`,
+			newReviewer, tenant(ctx), oldReviewer.PRId, oldReviewer.UserID,
+		)
+		if err != nil {
+			if isUniqueConstraintError(err) {
+				return core.ErrPRConflict
+			}
+			return fmt.Errorf("failed to reassign reviewer: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return core.ErrReviewerNotAssigned
+		}
+
+		return nil
+	})
+}
+
+func (db *DB) AddReviewer(ctx context.Context, prId string, reviewerId string) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		if err := bumpPRVersion(ctx, tx, prId, 0); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO pr_reviewers (tenant, pr_id, reviewer_id) VALUES ($1, $2, $3)
+         ON CONFLICT (tenant, pr_id, reviewer_id) DO NOTHING`,
+			tenant(ctx), prId, reviewerId)
+		if err != nil {
+			return fmt.Errorf("failed to add reviewer: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (db *DB) Approve(ctx context.Context, prId string, reviewerId string, version int64) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		if err := bumpPRVersion(ctx, tx, prId, version); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx,
+			`UPDATE pr_reviewers SET approved_at = COALESCE(approved_at, now())
+         WHERE tenant = $1 AND pr_id = $2 AND reviewer_id = $3`,
+			tenant(ctx), prId, reviewerId)
+		if err != nil {
+			return fmt.Errorf("failed to approve pr: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return core.ErrReviewerNotAssigned
+		}
+
+		return nil
+	})
+}
+
+func (db *DB) AddPRHistory(ctx context.Context, entry core.PRHistoryEntry) error {
+	_, err := db.conn.ExecContext(ctx,
+		`INSERT INTO pr_history (tenant, pr_id, event, actor_id, details) VALUES ($1, $2, $3, $4, $5)`,
+		tenant(ctx), entry.PullRequestID, string(entry.Event), sql.NullString{String: entry.ActorID, Valid: entry.ActorID != ""}, entry.Details)
+	if err != nil {
+		return fmt.Errorf("failed to add pr history: %w", err)
+	}
+
+	return nil
+}
+
+func (db *DB) GetPRHistory(ctx context.Context, prId string) ([]core.PRHistoryEntry, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT pr_id, event, actor_id, details, created_at FROM pr_history WHERE tenant = $1 AND pr_id = $2 ORDER BY created_at, id`,
+		tenant(ctx), prId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pr history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []core.PRHistoryEntry
+
+	for rows.Next() {
+		var (
+			entry   core.PRHistoryEntry
+			actorID sql.NullString
+		)
+		if err = rows.Scan(&entry.PullRequestID, &entry.Event, &actorID, &entry.Details, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan pr history: %w", err)
+		}
+		entry.ActorID = actorID.String
+		history = append(history, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pr history: %w", err)
+	}
+
+	return history, nil
+}
+
+func (db *DB) GetStalePRs(ctx context.Context, createdBefore time.Time) ([]core.PullRequest, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT pr.id, COALESCE(pr.repository, ''), COALESCE(pr.number, 0),
+                pr.labels, pr.lines_added, pr.lines_removed, pr.files_changed, pr.priority,
+                pr.title, pr.author_id, pr.state, pr.created_at
+         FROM pull_request pr
+         WHERE pr.tenant = $1 AND pr.state = 'OPEN' AND pr.created_at < $2
+           AND NOT EXISTS (SELECT 1 FROM pr_reviewers r
+                           WHERE r.tenant = pr.tenant AND r.pr_id = pr.id AND r.approved_at IS NOT NULL)
+           AND NOT EXISTS (SELECT 1 FROM pr_history h
+                           WHERE h.tenant = pr.tenant AND h.pr_id = pr.id AND h.event = $3)
+         ORDER BY pr.created_at, pr.id`,
+		tenant(ctx), createdBefore, string(core.HistoryEscalated))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stale prs: %w", err)
+	}
+	defer rows.Close()
+
+	var pullRequests []core.PullRequest
+
+	for rows.Next() {
+		var (
+			pullRequest core.PullRequest
+			createdAt   *time.Time
+		)
+
+		err = rows.Scan(&pullRequest.PullRequestID, &pullRequest.Repository, &pullRequest.Number,
+			pq.Array(&pullRequest.Labels), &pullRequest.LinesAdded, &pullRequest.LinesRemoved,
+			&pullRequest.FilesChanged, &pullRequest.Priority,
+			&pullRequest.PullRequestName, &pullRequest.AuthorID,
+			&pullRequest.Status, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		pullRequest.CreatedAt = formatTime(createdAt)
+		pullRequests = append(pullRequests, pullRequest)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	rows.Close()
+
+	for i := range pullRequests {
+		pullRequests[i].AssignedReviewers, err = db.getReviewers(ctx, pullRequests[i].PullRequestID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pullRequests, nil
+}
+
 func (db *DB) GetReview(ctx context.Context, userId string) (core.UserPullRequest, error) {
 	rows, err := db.conn.QueryContext(ctx,
-		`SELECT pr.id, pr.title, pr.author_id, pr.state, pr_reviewers.reviewer_id 
+		`SELECT pr.id, COALESCE(pr.repository, ''), COALESCE(pr.number, 0),
+                pr.labels, pr.lines_added, pr.lines_removed, pr.files_changed, pr.priority,
+                pr.title, pr.author_id, pr.state, pr.created_at, pr.merged_at
          FROM pull_request pr 
-         JOIN pr_reviewers ON pr.id = pr_reviewers.pr_id 
-         WHERE pr_reviewers.reviewer_id = $1`,
-		userId)
+         JOIN pr_reviewers ON pr_reviewers.tenant = pr.tenant AND pr.id = pr_reviewers.pr_id 
+         WHERE pr.tenant = $1 AND pr_reviewers.reviewer_id = $2
+         ORDER BY pr.created_at, pr.id`,
+		tenant(ctx), userId)
 	if err != nil {
 		return core.UserPullRequest{}, fmt.Errorf("failed to query reviews: %w", err)
 	}
@@ -326,35 +595,29 @@ func (db *DB) GetReview(ctx context.Context, userId string) (core.UserPullReques
 	var userPullRequest core.UserPullRequest
 	userPullRequest.UserID = userId
 
-	prMap := make(map[string]*core.PullRequest)
-
 	for rows.Next() {
-		var prID, title, authorID, state, reviewerID string
-
-		err = rows.Scan(&prID, &title, &authorID, &state, &reviewerID)
+		var (
+			pullRequest         core.PullRequest
+			createdAt, mergedAt *time.Time
+		)
+
+		err = rows.Scan(&pullRequest.PullRequestID, &pullRequest.Repository, &pullRequest.Number,
+			pq.Array(&pullRequest.Labels), &pullRequest.LinesAdded, &pullRequest.LinesRemoved,
+			&pullRequest.FilesChanged, &pullRequest.Priority,
+			&pullRequest.PullRequestName, &pullRequest.AuthorID,
+			&pullRequest.Status, &createdAt, &mergedAt)
 		if err != nil {
 			return core.UserPullRequest{}, fmt.Errorf("failed to scan row: %w", err)
 		}
-
-		if _, exists := prMap[prID]; !exists {
-			prMap[prID] = &core.PullRequest{
-				PullRequestID:   prID,
-				PullRequestName: title,
-				AuthorID:        authorID,
-				Status:          state,
-			}
-		}
-
+		pullRequest.CreatedAt = formatTime(createdAt)
+		pullRequest.MergedAt = formatTime(mergedAt)
+		userPullRequest.PullRequest = append(userPullRequest.PullRequest, pullRequest)
 	}
 
 	if err = rows.Err(); err != nil {
 		return core.UserPullRequest{}, fmt.Errorf("error iterating rows: %w", err)
 	}
 
-	for _, pr := range prMap {
-		userPullRequest.PullRequest = append(userPullRequest.PullRequest, *pr)
-	}
-
 	return userPullRequest, nil
 }
 
@@ -364,9 +627,10 @@ func (db *DB) GetUserReviewStats(ctx context.Context) (map[string]int, error) {
 	rows, err := db.conn.QueryContext(ctx, `
         SELECT reviewer_id, COUNT(*) as assignment_count 
         FROM pr_reviewers 
+        WHERE tenant = $1
         GROUP BY reviewer_id
         ORDER BY assignment_count DESC
-    `)
+    `, tenant(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -390,9 +654,10 @@ func (db *DB) GetPRReviewerCountStats(ctx context.Context) (map[string]int, erro
 	rows, err := db.conn.QueryContext(ctx, `
         SELECT pr_id, COUNT(*) as reviewer_count 
         FROM pr_reviewers 
+        WHERE tenant = $1
         GROUP BY pr_id
         ORDER BY reviewer_count DESC
-    `)
+    `, tenant(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -409,3 +674,55 @@ func (db *DB) GetPRReviewerCountStats(ctx context.Context) (map[string]int, erro
 
 	return stats, nil
 }
+
+func (db *DB) GetPendingReviews(ctx context.Context, now time.Time) ([]core.PendingReviews, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT u.id, u.name, u.team_name, u.active, u.out_of_office_until,
+                pr.id, COALESCE(pr.repository, ''), COALESCE(pr.number, 0),
+                pr.labels, pr.lines_added, pr.lines_removed, pr.files_changed, pr.priority,
+                pr.title, pr.author_id, pr.state, pr.created_at
+         FROM users u
+         JOIN pr_reviewers ON pr_reviewers.tenant = u.tenant AND pr_reviewers.reviewer_id = u.id
+         JOIN pull_request pr ON pr.tenant = pr_reviewers.tenant AND pr.id = pr_reviewers.pr_id
+         WHERE u.tenant = $1 AND u.active AND pr.state = 'OPEN' AND pr_reviewers.approved_at IS NULL
+           AND (u.out_of_office_until IS NULL OR u.out_of_office_until <= $2)
+         ORDER BY u.id, pr.created_at, pr.id`,
+		tenant(ctx), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []core.PendingReviews
+
+	for rows.Next() {
+		var (
+			user        core.User
+			pullRequest core.PullRequest
+			createdAt   *time.Time
+		)
+
+		err = rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.OutOfOfficeUntil,
+			&pullRequest.PullRequestID, &pullRequest.Repository, &pullRequest.Number,
+			pq.Array(&pullRequest.Labels), &pullRequest.LinesAdded, &pullRequest.LinesRemoved,
+			&pullRequest.FilesChanged, &pullRequest.Priority,
+			&pullRequest.PullRequestName, &pullRequest.AuthorID, &pullRequest.Status,
+			&createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		pullRequest.CreatedAt = formatTime(createdAt)
+
+		if len(pending) == 0 || pending[len(pending)-1].User.UserID != user.UserID {
+			pending = append(pending, core.PendingReviews{User: user})
+		}
+		last := &pending[len(pending)-1]
+		last.PullRequests = append(last.PullRequests, pullRequest)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return pending, nil
+}
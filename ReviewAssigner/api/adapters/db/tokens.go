@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"review-assigner/core"
+	"strings"
+	"time"
+)
+
+func joinScopes(scopes []core.Scope) string {
+	parts := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		parts = append(parts, string(scope))
+	}
+	return strings.Join(parts, " ")
+}
+
+func splitScopes(scopes string) []core.Scope {
+	var result []core.Scope
+	for _, scope := range strings.Fields(scopes) {
+		result = append(result, core.Scope(scope))
+	}
+	return result
+}
+
+type tokenScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanToken(row tokenScanner) (core.Token, error) {
+	var (
+		token  core.Token
+		userID sql.NullString
+		scopes string
+	)
+
+	err := row.Scan(&token.ID, &token.Tenant, &token.Name, &userID, &scopes, &token.CreatedAt, &token.LastUsedAt)
+	if err != nil {
+		return core.Token{}, err
+	}
+	token.UserID = userID.String
+	token.Scopes = splitScopes(scopes)
+
+	return token, nil
+}
+
+func (db *DB) AddToken(ctx context.Context, token core.Token, hash string) (core.Token, error) {
+	row := db.conn.QueryRowContext(ctx,
+		`INSERT INTO api_tokens (tenant, name, user_id, scopes, token_hash) VALUES ($1, $2, $3, $4, $5)
+         RETURNING id, tenant, name, user_id, scopes, created_at, last_used_at`,
+		tenant(ctx), token.Name, sql.NullString{String: token.UserID, Valid: token.UserID != ""}, joinScopes(token.Scopes), hash)
+
+	created, err := scanToken(row)
+	if err != nil {
+		return core.Token{}, fmt.Errorf("failed to add token: %w", err)
+	}
+
+	return created, nil
+}
+
+func (db *DB) GetTokenByHash(ctx context.Context, hash string) (core.Token, error) {
+	row := db.conn.QueryRowContext(ctx,
+		`SELECT id, tenant, name, user_id, scopes, created_at, last_used_at FROM api_tokens WHERE token_hash = $1`,
+		hash)
+
+	token, err := scanToken(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return core.Token{}, core.ErrTokenNotFound
+		}
+		return core.Token{}, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (db *DB) GetTokens(ctx context.Context) ([]core.Token, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT id, tenant, name, user_id, scopes, created_at, last_used_at FROM api_tokens WHERE tenant = $1 ORDER BY id`,
+		tenant(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []core.Token
+
+	for rows.Next() {
+		token, err := scanToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (db *DB) TouchToken(ctx context.Context, id int64, usedAt time.Time) error {
+	_, err := db.conn.ExecContext(ctx, `UPDATE api_tokens SET last_used_at = $1 WHERE id = $2`, usedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update token: %w", err)
+	}
+
+	return nil
+}
+
+func (db *DB) DeleteToken(ctx context.Context, id int64) error {
+	result, err := db.conn.ExecContext(ctx, `DELETE FROM api_tokens WHERE tenant = $1 AND id = $2`, tenant(ctx), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return core.ErrTokenNotFound
+	}
+
+	return nil
+}
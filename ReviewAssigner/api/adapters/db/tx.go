@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"review-assigner/core"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+)
+
+const txAttempts = 3
+
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// WithTx runs fn against a copy of the storage bound to a serializable
+// transaction, retrying it when Postgres aborts the transaction because of
+// a concurrent update. fn must therefore be safe to run more than once.
+// When the retries run out the update is reported as core.ErrPRConflict.
+func (db *DB) WithTx(ctx context.Context, fn func(core.DB) error) error {
+	if db.tx != nil {
+		return fn(db)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := db.runTx(ctx, fn)
+		if err == nil || !isSerializationFailure(err) {
+			return err
+		}
+		if attempt == txAttempts {
+			return fmt.Errorf("%w: %v", core.ErrPRConflict, err)
+		}
+		db.log.Debug("retrying transaction after serialization failure", "attempt", attempt, "error", err)
+	}
+}
+
+func (db *DB) runTx(ctx context.Context, fn func(core.DB) error) error {
+	tx, err := db.pool.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = fn(&DB{log: db.log, pool: db.pool, conn: tx, tx: tx}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// inTx runs fn in the unit of work the storage is bound to, or in a
+// transaction of its own outside of WithTx.
+func (db *DB) inTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	if db.tx != nil {
+		return fn(db.tx)
+	}
+
+	tx, err := db.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) &&
+		(pgErr.Code == pgerrcode.SerializationFailure || pgErr.Code == pgerrcode.DeadlockDetected)
+}
+
+// BeginTx starts a transaction on the pool for callers of AddUserTX, which
+// writes into a transaction owned by the caller.
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return db.pool.BeginTx(ctx, opts)
+}
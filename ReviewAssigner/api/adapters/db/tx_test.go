@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"review-assigner/core"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+)
+
+func TestIsSerializationFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "serialization failure", err: &pgconn.PgError{Code: pgerrcode.SerializationFailure}, want: true},
+		{name: "deadlock", err: &pgconn.PgError{Code: pgerrcode.DeadlockDetected}, want: true},
+		{name: "wrapped", err: fmt.Errorf("failed to update pr: %w", &pgconn.PgError{Code: pgerrcode.SerializationFailure}), want: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: pgerrcode.UniqueViolation}},
+		{name: "code in the message only", err: errors.New("user 40001 not found")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSerializationFailure(tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTxReportsConflictAfterRetries(t *testing.T) {
+	address := os.Getenv("TEST_DB_ADDRESS")
+	if address == "" {
+		t.Skip("TEST_DB_ADDRESS is not set")
+	}
+
+	db, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.pool.Close() })
+
+	attempts := 0
+	err = db.WithTx(context.Background(), func(core.DB) error {
+		attempts++
+		return &pgconn.PgError{Code: pgerrcode.SerializationFailure}
+	})
+	if !errors.Is(err, core.ErrPRConflict) {
+		t.Errorf("got error %v, want %v", err, core.ErrPRConflict)
+	}
+	if attempts != txAttempts {
+		t.Errorf("ran %d times, want %d", attempts, txAttempts)
+	}
+}
@@ -0,0 +1,81 @@
+package graphql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"review-assigner/core"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Limits on a single query. The deepest dashboard query, team → members →
+// reviews → reviewers → team, is six levels deep.
+const (
+	maxQueryDepth   = 8
+	maxQueryLength  = 8 << 10
+	maxRequestBytes = 64 << 10
+)
+
+type Handler struct {
+	service *core.Service
+	schema  *gql.Schema
+	log     *slog.Logger
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func NewHandler(service *core.Service, log *slog.Logger) http.Handler {
+	schema := gql.MustParseSchema(schemaSDL, &rootResolver{service: service},
+		gql.MaxDepth(maxQueryDepth),
+		gql.MaxQueryLength(maxQueryLength))
+
+	return &Handler{
+		service: service,
+		schema:  schema,
+		log:     log,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				http.Error(w, "invalid variables", http.StatusBadRequest)
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON", http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(h.service))
+	response := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	if len(response.Errors) > 0 {
+		h.log.Debug("graphql query returned errors", "errors", response.Errors)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
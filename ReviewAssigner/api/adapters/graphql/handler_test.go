@@ -0,0 +1,180 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"review-assigner/adapters/rest"
+	"review-assigner/adapters/sqlite"
+	"review-assigner/config"
+	"review-assigner/core"
+	"strings"
+	"testing"
+)
+
+type testServer struct {
+	*httptest.Server
+	service *core.Service
+}
+
+// newTestServer serves GraphQL behind the same token check as main, backed
+// by an in-memory SQLite database with one team led by u1 and one PR by u1
+// reviewed by u2 and u3.
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	db, err := sqlite.New(log, "sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	service, err := core.NewService(log, db, nil, core.EscalationPolicy{}, core.AssignmentPolicy{})
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+
+	auth, err := rest.NewAuthenticator(service, log, config.AuthConfig{Enabled: true, AdminTokens: []string{"test-admin-token"}})
+	if err != nil {
+		t.Fatalf("create authenticator: %v", err)
+	}
+
+	ctx := context.Background()
+	_, err = service.CreateTeam(ctx, core.Team{TeamName: "backend", LeadID: "u1", Members: []core.TeamMember{
+		{UserID: "u1", Username: "Alice", IsActive: true},
+		{UserID: "u2", Username: "Bob", IsActive: true},
+		{UserID: "u3", Username: "Carol", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	if _, err := service.CreatePR(ctx, core.PullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"}); err != nil {
+		t.Fatalf("create pr: %v", err)
+	}
+
+	server := httptest.NewServer(auth.RequireScope(core.ScopePRsRead)(NewHandler(service, log)))
+	t.Cleanup(server.Close)
+
+	return &testServer{Server: server, service: service}
+}
+
+func (s *testServer) token(t *testing.T, userId string, scopes ...core.Scope) string {
+	t.Helper()
+
+	_, secret, err := s.service.CreateToken(context.Background(), core.Token{Name: "test", UserID: userId, Scopes: scopes})
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	return secret
+}
+
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (s *testServer) query(t *testing.T, token, query string) response {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req, err := http.NewRequest(http.MethodPost, s.URL, strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d", resp.StatusCode)
+	}
+
+	var result response
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return result
+}
+
+func (r response) errorContains(text string) bool {
+	for _, err := range r.Errors {
+		if strings.Contains(err.Message, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAuthorization(t *testing.T) {
+	server := newTestServer(t)
+
+	member := server.token(t, "u2", core.ScopePRsRead)
+	statsReader := server.token(t, "u2", core.ScopePRsRead, core.ScopeStatsRead)
+	bot := server.token(t, "", core.ScopePRsRead, core.ScopeStatsRead)
+	lead := server.token(t, "u1", core.ScopePRsRead)
+
+	tests := []struct {
+		name      string
+		token     string
+		query     string
+		forbidden bool
+	}{
+		{name: "member reads own reviews", token: member, query: `{ user(id: "u2") { reviews { id } } }`},
+		{name: "member reads another user's reviews", token: member,
+			query: `{ user(id: "u3") { reviews { id } } }`, forbidden: true},
+		{name: "member reads reviews of the whole team", token: member,
+			query: `{ team(name: "backend") { members { reviews { id } } } }`, forbidden: true},
+		{name: "lead reads reviews of the team", token: lead,
+			query: `{ team(name: "backend") { members { reviews { id } } } }`},
+		{name: "stats without stats:read", token: member, query: `{ stats { totalAssignments } }`, forbidden: true},
+		{name: "member stats with stats:read", token: statsReader, query: `{ stats { totalAssignments } }`, forbidden: true},
+		{name: "bot stats with stats:read", token: bot, query: `{ stats { totalAssignments } }`},
+		{name: "member reads a PR they review", token: member, query: `{ pullRequest(id: "pr-1") { reviewers { id } } }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := server.query(t, tt.token, tt.query)
+			if forbidden := resp.errorContains(core.ErrForbidden.Error()); forbidden != tt.forbidden {
+				t.Errorf("forbidden: got %v, want %v (errors %+v)", forbidden, tt.forbidden, resp.Errors)
+			}
+			if !tt.forbidden && len(resp.Errors) > 0 {
+				t.Errorf("unexpected errors %+v", resp.Errors)
+			}
+		})
+	}
+}
+
+func TestQueryLimits(t *testing.T) {
+	server := newTestServer(t)
+	token := server.token(t, "", core.ScopePRsRead)
+
+	dashboard := server.query(t, token, `{ team(name: "backend") { members { reviews { reviewers { team { name } } } } } }`)
+	if len(dashboard.Errors) > 0 {
+		t.Fatalf("dashboard query failed: %+v", dashboard.Errors)
+	}
+
+	deep := server.query(t, token, `{ team(name: "backend") { members { team { members { team { members { team { members { id } } } } } } } } }`)
+	if !deep.errorContains("exceeds max depth") {
+		t.Errorf("deep query was not rejected: %+v", deep.Errors)
+	}
+
+	long := server.query(t, token, "{ stats { totalAssignments } }"+strings.Repeat(" ", maxQueryLength))
+	if len(long.Errors) == 0 {
+		t.Error("overlong query was not rejected")
+	}
+}
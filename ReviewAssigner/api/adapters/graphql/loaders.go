@@ -0,0 +1,90 @@
+package graphql
+
+import (
+	"context"
+	"review-assigner/core"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+type loadersKey struct{}
+
+type loaders struct {
+	teams        *dataloader.Loader[string, core.Team]
+	users        *dataloader.Loader[string, core.User]
+	pullRequests *dataloader.Loader[string, core.PullRequest]
+	reviews      *dataloader.Loader[string, []core.PullRequest]
+	assignments  *dataloader.Loader[string, []core.Assignment]
+}
+
+func newLoaders(service *core.Service) *loaders {
+	return &loaders{
+		teams: dataloader.NewBatchedLoader(byKey(service.GetTeams,
+			func(team core.Team) string { return team.TeamName }, core.ErrTeamNotFound)),
+		users: dataloader.NewBatchedLoader(byKey(service.GetUsers,
+			func(user core.User) string { return user.UserID }, core.ErrUserNotFound)),
+		pullRequests: dataloader.NewBatchedLoader(byKey(service.GetPRs,
+			func(pr core.PullRequest) string { return pr.PullRequestID }, core.ErrPRNotFound)),
+		reviews:     dataloader.NewBatchedLoader(grouped(service.GetReviewsByUsers)),
+		assignments: dataloader.NewBatchedLoader(grouped(service.GetAssignments)),
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+func byKey[V any](
+	fetch func(context.Context, []string) ([]V, error),
+	key func(V) string,
+	notFound error,
+) dataloader.BatchFunc[string, V] {
+	return func(ctx context.Context, keys []string) []*dataloader.Result[V] {
+		results := make([]*dataloader.Result[V], len(keys))
+
+		values, err := fetch(ctx, keys)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[V]{Error: err}
+			}
+			return results
+		}
+
+		found := make(map[string]V, len(values))
+		for _, value := range values {
+			found[key(value)] = value
+		}
+
+		for i, k := range keys {
+			value, ok := found[k]
+			if !ok {
+				results[i] = &dataloader.Result[V]{Error: notFound}
+				continue
+			}
+			results[i] = &dataloader.Result[V]{Data: value}
+		}
+
+		return results
+	}
+}
+
+func grouped[V any](fetch func(context.Context, []string) (map[string][]V, error)) dataloader.BatchFunc[string, []V] {
+	return func(ctx context.Context, keys []string) []*dataloader.Result[[]V] {
+		results := make([]*dataloader.Result[[]V], len(keys))
+
+		groups, err := fetch(ctx, keys)
+		for i, k := range keys {
+			if err != nil {
+				results[i] = &dataloader.Result[[]V]{Error: err}
+				continue
+			}
+			results[i] = &dataloader.Result[[]V]{Data: groups[k]}
+		}
+
+		return results
+	}
+}
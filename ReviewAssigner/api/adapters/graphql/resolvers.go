@@ -0,0 +1,303 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"review-assigner/core"
+	"sort"
+	"time"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+type rootResolver struct {
+	service *core.Service
+}
+
+func (r *rootResolver) Team(ctx context.Context, args struct{ Name string }) (*teamResolver, error) {
+	team, err := loadersFrom(ctx).teams.Load(ctx, args.Name)()
+	if errors.Is(err, core.ErrTeamNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &teamResolver{team: team}, nil
+}
+
+func (r *rootResolver) User(ctx context.Context, args struct{ ID gql.ID }) (*userResolver, error) {
+	user, err := loadUser(ctx, string(args.ID))
+	if errors.Is(err, core.ErrUserNotFound) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *rootResolver) PullRequest(ctx context.Context, args struct{ ID gql.ID }) (*pullRequestResolver, error) {
+	pr, err := loadPullRequest(ctx, string(args.ID))
+	if errors.Is(err, core.ErrPRNotFound) {
+		return nil, nil
+	}
+	return pr, err
+}
+
+func (r *rootResolver) Stats(ctx context.Context) (*statsResolver, error) {
+	stats, err := r.service.GetStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &statsResolver{stats: stats}, nil
+}
+
+type teamResolver struct {
+	team core.Team
+}
+
+func (t *teamResolver) Name() string {
+	return t.team.TeamName
+}
+
+func (t *teamResolver) Lead(ctx context.Context) (*userResolver, error) {
+	if t.team.LeadID == "" {
+		return nil, nil
+	}
+	return loadUser(ctx, t.team.LeadID)
+}
+
+func (t *teamResolver) Members(ctx context.Context) ([]*userResolver, error) {
+	ids := make([]string, 0, len(t.team.Members))
+	for _, member := range t.team.Members {
+		ids = append(ids, member.UserID)
+	}
+	return loadUsers(ctx, ids)
+}
+
+type userResolver struct {
+	user core.User
+}
+
+func loadUser(ctx context.Context, userID string) (*userResolver, error) {
+	user, err := loadersFrom(ctx).users.Load(ctx, userID)()
+	if err != nil {
+		return nil, err
+	}
+	return &userResolver{user: user}, nil
+}
+
+func loadUsers(ctx context.Context, userIDs []string) ([]*userResolver, error) {
+	users, errs := loadersFrom(ctx).users.LoadMany(ctx, userIDs)()
+	resolvers := make([]*userResolver, 0, len(users))
+	for i, user := range users {
+		if errs != nil && errs[i] != nil {
+			return nil, errs[i]
+		}
+		resolvers = append(resolvers, &userResolver{user: user})
+	}
+	return resolvers, nil
+}
+
+func (u *userResolver) ID() gql.ID {
+	return gql.ID(u.user.UserID)
+}
+
+func (u *userResolver) Username() string {
+	return u.user.Username
+}
+
+func (u *userResolver) IsActive() bool {
+	return u.user.IsActive
+}
+
+func (u *userResolver) OutOfOfficeUntil() *string {
+	if u.user.OutOfOfficeUntil == nil {
+		return nil
+	}
+	formatted := u.user.OutOfOfficeUntil.UTC().Format(time.RFC3339)
+	return &formatted
+}
+
+func (u *userResolver) Team(ctx context.Context) (*teamResolver, error) {
+	team, err := loadersFrom(ctx).teams.Load(ctx, u.user.TeamName)()
+	if err != nil {
+		return nil, err
+	}
+	return &teamResolver{team: team}, nil
+}
+
+func (u *userResolver) Reviews(ctx context.Context, args struct{ Status *string }) ([]*pullRequestResolver, error) {
+	reviews, err := loadersFrom(ctx).reviews.Load(ctx, u.user.UserID)()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*pullRequestResolver, 0, len(reviews))
+	for _, pr := range reviews {
+		if args.Status != nil && pr.Status != *args.Status {
+			continue
+		}
+		resolvers = append(resolvers, &pullRequestResolver{pr: pr})
+	}
+	return resolvers, nil
+}
+
+type pullRequestResolver struct {
+	pr core.PullRequest
+}
+
+func loadPullRequest(ctx context.Context, prID string) (*pullRequestResolver, error) {
+	pr, err := loadersFrom(ctx).pullRequests.Load(ctx, prID)()
+	if err != nil {
+		return nil, err
+	}
+	return &pullRequestResolver{pr: pr}, nil
+}
+
+func (p *pullRequestResolver) ID() gql.ID {
+	return gql.ID(p.pr.PullRequestID)
+}
+
+func (p *pullRequestResolver) Name() string {
+	return p.pr.PullRequestName
+}
+
+func (p *pullRequestResolver) Status() string {
+	return p.pr.Status
+}
+
+func (p *pullRequestResolver) CreatedAt() *string {
+	return p.pr.CreatedAt
+}
+
+func (p *pullRequestResolver) MergedAt() *string {
+	return p.pr.MergedAt
+}
+
+func (p *pullRequestResolver) Author(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, p.pr.AuthorID)
+}
+
+func (p *pullRequestResolver) Reviewers(ctx context.Context) ([]*userResolver, error) {
+	assignments, err := loadersFrom(ctx).assignments.Load(ctx, p.pr.PullRequestID)()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(assignments))
+	for _, assignment := range assignments {
+		ids = append(ids, assignment.ReviewerID)
+	}
+	return loadUsers(ctx, ids)
+}
+
+func (p *pullRequestResolver) Assignments(ctx context.Context) ([]*assignmentResolver, error) {
+	assignments, err := loadersFrom(ctx).assignments.Load(ctx, p.pr.PullRequestID)()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*assignmentResolver, 0, len(assignments))
+	for _, assignment := range assignments {
+		resolvers = append(resolvers, &assignmentResolver{assignment: assignment})
+	}
+	return resolvers, nil
+}
+
+type assignmentResolver struct {
+	assignment core.Assignment
+}
+
+func (a *assignmentResolver) Reviewer(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, a.assignment.ReviewerID)
+}
+
+func (a *assignmentResolver) PullRequest(ctx context.Context) (*pullRequestResolver, error) {
+	return loadPullRequest(ctx, a.assignment.PullRequestID)
+}
+
+func (a *assignmentResolver) ApprovedAt() *string {
+	if a.assignment.ApprovedAt == nil {
+		return nil
+	}
+	formatted := a.assignment.ApprovedAt.UTC().Format(time.RFC3339)
+	return &formatted
+}
+
+type statsResolver struct {
+	stats core.Stats
+}
+
+func (s *statsResolver) count(key string) int32 {
+	value, _ := s.stats[key].(int)
+	return int32(value)
+}
+
+func (s *statsResolver) counts(key string) ([]string, map[string]int) {
+	counts, _ := s.stats[key].(map[string]int)
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys, counts
+}
+
+func (s *statsResolver) TotalAssignments() int32 {
+	return s.count("total_assignments")
+}
+
+func (s *statsResolver) UniqueUsersWithAssignments() int32 {
+	return s.count("unique_users_with_assignments")
+}
+
+func (s *statsResolver) UniquePullRequestsWithReviewers() int32 {
+	return s.count("unique_prs_with_reviewers")
+}
+
+func (s *statsResolver) UserAssignments() []*userAssignmentCountResolver {
+	keys, counts := s.counts("user_assignments")
+	resolvers := make([]*userAssignmentCountResolver, 0, len(keys))
+	for _, userID := range keys {
+		resolvers = append(resolvers, &userAssignmentCountResolver{userID: userID, count: int32(counts[userID])})
+	}
+	return resolvers
+}
+
+func (s *statsResolver) PullRequestReviewerCounts() []*pullRequestReviewerCountResolver {
+	keys, counts := s.counts("pr_reviewer_counts")
+	resolvers := make([]*pullRequestReviewerCountResolver, 0, len(keys))
+	for _, prID := range keys {
+		resolvers = append(resolvers, &pullRequestReviewerCountResolver{prID: prID, count: int32(counts[prID])})
+	}
+	return resolvers
+}
+
+type userAssignmentCountResolver struct {
+	userID string
+	count  int32
+}
+
+func (c *userAssignmentCountResolver) User(ctx context.Context) (*userResolver, error) {
+	return loadUser(ctx, c.userID)
+}
+
+func (c *userAssignmentCountResolver) Count() int32 {
+	return c.count
+}
+
+type pullRequestReviewerCountResolver struct {
+	prID  string
+	count int32
+}
+
+func (c *pullRequestReviewerCountResolver) PullRequest(ctx context.Context) (*pullRequestResolver, error) {
+	return loadPullRequest(ctx, c.prID)
+}
+
+func (c *pullRequestReviewerCountResolver) Count() int32 {
+	return c.count
+}
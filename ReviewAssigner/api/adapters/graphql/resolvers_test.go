@@ -0,0 +1,116 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"review-assigner/core"
+	"slices"
+	"testing"
+)
+
+func TestDashboardQuery(t *testing.T) {
+	server := newTestServer(t)
+
+	ctx := context.Background()
+	if _, err := server.service.Approve(ctx, "pr-1", "u2"); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if _, err := server.service.CreatePR(ctx, core.PullRequest{PullRequestID: "pr-2", PullRequestName: "Fix search", AuthorID: "u2"}); err != nil {
+		t.Fatalf("create pr: %v", err)
+	}
+	if _, err := server.service.Merged(ctx, "pr-2"); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	resp := server.query(t, "test-admin-token", `{
+		team(name: "backend") { name lead { id } members { id open: reviews(status: "OPEN") { id } all: reviews { id } } }
+		pullRequest(id: "pr-1") { name status author { username } reviewers { id } assignments { reviewer { id } approvedAt } }
+		missing: team(name: "frontend") { name }
+		nobody: user(id: "u9") { id }
+		stats { totalAssignments userAssignments { user { id } count } }
+	}`)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("query failed: %+v", resp.Errors)
+	}
+
+	var team struct {
+		Name    string
+		Lead    struct{ ID string }
+		Members []struct {
+			ID   string
+			Open []struct{ ID string }
+			All  []struct{ ID string }
+		}
+	}
+	decodeField(t, resp, "team", &team)
+	if team.Name != "backend" || team.Lead.ID != "u1" || len(team.Members) != 3 {
+		t.Fatalf("got team %+v", team)
+	}
+	reviews := make(map[string][2]int)
+	for _, member := range team.Members {
+		reviews[member.ID] = [2]int{len(member.Open), len(member.All)}
+	}
+	// u1 and u3 review the merged pr-2, u2 and u3 the open pr-1.
+	want := map[string][2]int{"u1": {0, 1}, "u2": {1, 1}, "u3": {1, 2}}
+	for id, counts := range want {
+		if reviews[id] != counts {
+			t.Errorf("%s: got %v open and all reviews, want %v", id, reviews[id], counts)
+		}
+	}
+
+	var pullRequest struct {
+		Name        string
+		Status      string
+		Author      struct{ Username string }
+		Reviewers   []struct{ ID string }
+		Assignments []struct {
+			Reviewer   struct{ ID string }
+			ApprovedAt *string
+		}
+	}
+	decodeField(t, resp, "pullRequest", &pullRequest)
+	if pullRequest.Name != "Add search" || pullRequest.Status != "OPEN" || pullRequest.Author.Username != "Alice" {
+		t.Errorf("got pull request %+v", pullRequest)
+	}
+	var reviewerIds, approved []string
+	for _, reviewer := range pullRequest.Reviewers {
+		reviewerIds = append(reviewerIds, reviewer.ID)
+	}
+	for _, assignment := range pullRequest.Assignments {
+		if assignment.ApprovedAt != nil {
+			approved = append(approved, assignment.Reviewer.ID)
+		}
+	}
+	if !slices.Equal(reviewerIds, []string{"u2", "u3"}) || !slices.Equal(approved, []string{"u2"}) {
+		t.Errorf("got reviewers %v with approvals from %v", reviewerIds, approved)
+	}
+
+	var stats struct {
+		TotalAssignments int
+		UserAssignments  []struct {
+			User  struct{ ID string }
+			Count int
+		}
+	}
+	decodeField(t, resp, "stats", &stats)
+	counts := make(map[string]int)
+	for _, assignment := range stats.UserAssignments {
+		counts[assignment.User.ID] = assignment.Count
+	}
+	if stats.TotalAssignments != 4 || counts["u3"] != 2 || counts["u1"] != 1 {
+		t.Errorf("got stats %+v", stats)
+	}
+
+	for _, field := range []string{"missing", "nobody"} {
+		if string(resp.Data[field]) != "null" {
+			t.Errorf("%s: got %s, want null", field, resp.Data[field])
+		}
+	}
+}
+
+func decodeField(t *testing.T, resp response, field string, v any) {
+	t.Helper()
+	if err := json.Unmarshal(resp.Data[field], v); err != nil {
+		t.Fatalf("decode %s: %v", field, err)
+	}
+}
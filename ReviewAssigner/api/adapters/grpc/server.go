@@ -0,0 +1,387 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"review-assigner/core"
+	pb "review-assigner/proto/reviewassigner/v1"
+	"time"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Authenticator checks the credentials sent in the authorization metadata,
+// the same bearer tokens the HTTP API accepts.
+type Authenticator interface {
+	Authenticate(ctx context.Context, authorization, tenant string, scope core.Scope) (context.Context, error)
+}
+
+// scopes are the token scopes each method requires, as the policies of the
+// matching HTTP routes do.
+var scopes = map[string]core.Scope{
+	pb.ReviewAssigner_CreateTeam_FullMethodName:         core.ScopeTeamsWrite,
+	pb.ReviewAssigner_GetTeam_FullMethodName:            core.ScopeTeamsRead,
+	pb.ReviewAssigner_SetTeamLead_FullMethodName:        core.ScopeTeamsWrite,
+	pb.ReviewAssigner_SetUserActive_FullMethodName:      core.ScopeUsersWrite,
+	pb.ReviewAssigner_SetOutOfOffice_FullMethodName:     core.ScopeUsersWrite,
+	pb.ReviewAssigner_CreatePullRequest_FullMethodName:  core.ScopePRsWrite,
+	pb.ReviewAssigner_ApprovePullRequest_FullMethodName: core.ScopePRsWrite,
+	pb.ReviewAssigner_MergePullRequest_FullMethodName:   core.ScopePRsWrite,
+	pb.ReviewAssigner_ReassignReviewer_FullMethodName:   core.ScopePRsWrite,
+	pb.ReviewAssigner_GetReviews_FullMethodName:         core.ScopeUsersRead,
+	pb.ReviewAssigner_GetStats_FullMethodName:           core.ScopeStatsRead,
+}
+
+type Server struct {
+	pb.UnimplementedReviewAssignerServer
+	service *core.Service
+	log     *slog.Logger
+	auth    Authenticator
+}
+
+func NewServer(service *core.Service, log *slog.Logger, auth Authenticator) *grpclib.Server {
+	s := &Server{
+		service: service,
+		log:     log,
+		auth:    auth,
+	}
+
+	server := grpclib.NewServer(grpclib.ChainUnaryInterceptor(s.authenticate, s.logErrors))
+	pb.RegisterReviewAssignerServer(server, s)
+
+	return server
+}
+
+func (s *Server) logErrors(ctx context.Context, req any, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if status.Code(err) == codes.Internal {
+		s.log.Error("grpc request failed", "method", info.FullMethod, "error", err)
+	}
+	return resp, err
+}
+
+func (s *Server) authenticate(ctx context.Context, req any, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (any, error) {
+	scope, ok := scopes[info.FullMethod]
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "method has no auth policy")
+	}
+
+	var authorization, tenant string
+	if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+		authorization = values[0]
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, "x-tenant"); len(values) > 0 {
+		if !core.ValidTenant(values[0]) {
+			return nil, status.Error(codes.InvalidArgument, "invalid x-tenant metadata")
+		}
+		tenant = values[0]
+	}
+
+	ctx, err := s.auth.Authenticate(ctx, authorization, tenant, scope)
+	switch {
+	case errors.Is(err, core.ErrTokenNotFound):
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+	case errors.Is(err, core.ErrForbidden):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		s.log.Error("failed to authenticate grpc request", "method", info.FullMethod, "error", err)
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return handler(ctx, req)
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, core.ErrTeamNotFound),
+		errors.Is(err, core.ErrUserNotFound),
+		errors.Is(err, core.ErrPRNotFound),
+		errors.Is(err, core.ErrRepositoryNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, core.ErrTeamAlreadyExists),
+		errors.Is(err, core.ErrPRAAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, core.ErrNotEnoughReviewers),
+		errors.Is(err, core.ErrPRAlreadyMerged),
+		errors.Is(err, core.ErrReviewerNotAssigned),
+		errors.Is(err, core.ErrNoReplacementCandidate),
+		errors.Is(err, core.ErrPreconditionFailed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, core.ErrPRConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, core.ErrLeadNotMember),
+		errors.Is(err, core.ErrInvalidRepository),
+		errors.Is(err, core.ErrInvalidPRMetadata):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, core.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func toTimestamp(value *string) *timestamppb.Timestamp {
+	if value == nil {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toTeam(team core.Team) *pb.Team {
+	response := &pb.Team{
+		TeamName: team.TeamName,
+		LeadId:   team.LeadID,
+	}
+
+	for _, member := range team.Members {
+		response.Members = append(response.Members, &pb.TeamMember{
+			UserId:   member.UserID,
+			Username: member.Username,
+			IsActive: member.IsActive,
+		})
+	}
+
+	return response
+}
+
+func toUser(user core.User) *pb.User {
+	response := &pb.User{
+		UserId:   user.UserID,
+		Username: user.Username,
+		TeamName: user.TeamName,
+		IsActive: user.IsActive,
+	}
+	if user.OutOfOfficeUntil != nil {
+		response.OutOfOfficeUntil = timestamppb.New(*user.OutOfOfficeUntil)
+	}
+	return response
+}
+
+func toPullRequest(pr core.PullRequest) *pb.PullRequest {
+	return &pb.PullRequest{
+		PullRequestId:     pr.PullRequestID,
+		PullRequestName:   pr.PullRequestName,
+		AuthorId:          pr.AuthorID,
+		Status:            pr.Status,
+		AssignedReviewers: pr.AssignedReviewers,
+		CreatedAt:         toTimestamp(pr.CreatedAt),
+		MergedAt:          toTimestamp(pr.MergedAt),
+		Repository:        pr.Repository,
+		PullRequestNumber: pr.Number,
+		Labels:            pr.Labels,
+		LinesAdded:        int32(pr.LinesAdded),
+		LinesRemoved:      int32(pr.LinesRemoved),
+		FilesChanged:      int32(pr.FilesChanged),
+		Priority:          string(pr.Priority),
+	}
+}
+
+func (s *Server) CreateTeam(ctx context.Context, req *pb.CreateTeamRequest) (*pb.CreateTeamResponse, error) {
+	if req.GetTeam().GetTeamName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "team.team_name is required")
+	}
+
+	team := core.Team{
+		TeamName: req.Team.TeamName,
+		LeadID:   req.Team.LeadId,
+	}
+	for _, member := range req.Team.Members {
+		team.Members = append(team.Members, core.TeamMember{
+			UserID:   member.UserId,
+			Username: member.Username,
+			IsActive: member.IsActive,
+		})
+	}
+
+	createdTeam, err := s.service.CreateTeam(ctx, team)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.CreateTeamResponse{Team: toTeam(createdTeam)}, nil
+}
+
+func (s *Server) GetTeam(ctx context.Context, req *pb.GetTeamRequest) (*pb.GetTeamResponse, error) {
+	if req.TeamName == "" {
+		return nil, status.Error(codes.InvalidArgument, "team_name is required")
+	}
+
+	team, err := s.service.GetTeam(ctx, req.TeamName)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.GetTeamResponse{Team: toTeam(team)}, nil
+}
+
+func (s *Server) SetTeamLead(ctx context.Context, req *pb.SetTeamLeadRequest) (*pb.SetTeamLeadResponse, error) {
+	if req.TeamName == "" || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "team_name and user_id are required")
+	}
+
+	team, err := s.service.SetTeamLead(ctx, req.TeamName, req.UserId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.SetTeamLeadResponse{Team: toTeam(team)}, nil
+}
+
+func (s *Server) SetUserActive(ctx context.Context, req *pb.SetUserActiveRequest) (*pb.SetUserActiveResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	user, err := s.service.IsActive(ctx, req.UserId, req.IsActive)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.SetUserActiveResponse{User: toUser(user)}, nil
+}
+
+func (s *Server) SetOutOfOffice(ctx context.Context, req *pb.SetOutOfOfficeRequest) (*pb.SetOutOfOfficeResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	var until *time.Time
+	if req.Until != nil {
+		t := req.Until.AsTime()
+		until = &t
+	}
+
+	user, err := s.service.SetOutOfOffice(ctx, req.UserId, until)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.SetOutOfOfficeResponse{User: toUser(user)}, nil
+}
+
+func (s *Server) CreatePullRequest(ctx context.Context, req *pb.CreatePullRequestRequest) (*pb.CreatePullRequestResponse, error) {
+	if (req.PullRequestId == "" && req.Repository == "") || req.PullRequestName == "" || req.AuthorId == "" {
+		return nil, status.Error(codes.InvalidArgument,
+			"pull_request_id or repository, pull_request_name and author_id are required")
+	}
+
+	pr, err := s.service.CreatePR(ctx, core.PullRequest{
+		PullRequestID:   req.PullRequestId,
+		Repository:      req.Repository,
+		Number:          req.PullRequestNumber,
+		PullRequestName: req.PullRequestName,
+		AuthorID:        req.AuthorId,
+		Labels:          req.Labels,
+		LinesAdded:      int(req.LinesAdded),
+		LinesRemoved:    int(req.LinesRemoved),
+		FilesChanged:    int(req.FilesChanged),
+		Priority:        core.Priority(req.Priority),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.CreatePullRequestResponse{Pr: toPullRequest(pr)}, nil
+}
+
+func (s *Server) ApprovePullRequest(ctx context.Context, req *pb.ApprovePullRequestRequest) (*pb.ApprovePullRequestResponse, error) {
+	if req.PullRequestId == "" || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "pull_request_id and user_id are required")
+	}
+
+	pr, err := s.service.Approve(ctx, req.PullRequestId, req.UserId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.ApprovePullRequestResponse{Pr: toPullRequest(pr)}, nil
+}
+
+func (s *Server) MergePullRequest(ctx context.Context, req *pb.MergePullRequestRequest) (*pb.MergePullRequestResponse, error) {
+	if req.PullRequestId == "" {
+		return nil, status.Error(codes.InvalidArgument, "pull_request_id is required")
+	}
+
+	pr, err := s.service.Merged(ctx, req.PullRequestId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.MergePullRequestResponse{Pr: toPullRequest(pr)}, nil
+}
+
+func (s *Server) ReassignReviewer(ctx context.Context, req *pb.ReassignReviewerRequest) (*pb.ReassignReviewerResponse, error) {
+	if req.PullRequestId == "" || req.OldUserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "pull_request_id and old_user_id are required")
+	}
+
+	pr, newReviewer, err := s.service.Reassign(ctx, core.ReassignReviewer{
+		PRId:   req.PullRequestId,
+		UserID: req.OldUserId,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.ReassignReviewerResponse{Pr: toPullRequest(pr), ReplacedBy: newReviewer}, nil
+}
+
+func (s *Server) GetReviews(ctx context.Context, req *pb.GetReviewsRequest) (*pb.GetReviewsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	result, err := s.service.GetReview(ctx, req.UserId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &pb.GetReviewsResponse{UserId: result.UserID}
+	for _, pr := range result.PullRequest {
+		response.PullRequests = append(response.PullRequests, toPullRequest(pr))
+	}
+
+	return response, nil
+}
+
+func (s *Server) GetStats(ctx context.Context, _ *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
+	stats, err := s.service.GetStats(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.GetStatsResponse{
+		UserAssignments:            toCounts(stats["user_assignments"]),
+		PrReviewerCounts:           toCounts(stats["pr_reviewer_counts"]),
+		TotalAssignments:           toCount(stats["total_assignments"]),
+		UniqueUsersWithAssignments: toCount(stats["unique_users_with_assignments"]),
+		UniquePrsWithReviewers:     toCount(stats["unique_prs_with_reviewers"]),
+	}, nil
+}
+
+func toCounts(value interface{}) map[string]int64 {
+	counts, _ := value.(map[string]int)
+	result := make(map[string]int64, len(counts))
+	for key, count := range counts {
+		result[key] = int64(count)
+	}
+	return result
+}
+
+func toCount(value interface{}) int64 {
+	count, _ := value.(int)
+	return int64(count)
+}
@@ -0,0 +1,338 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"review-assigner/adapters/rest"
+	"review-assigner/adapters/sqlite"
+	"review-assigner/config"
+	"review-assigner/core"
+	pb "review-assigner/proto/reviewassigner/v1"
+	"slices"
+	"testing"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const adminToken = "test-admin-token"
+
+// newTestClient serves the gRPC API over an in-memory listener, backed by an
+// in-memory SQLite database and authentication with one admin token.
+func newTestClient(t *testing.T) (pb.ReviewAssignerClient, *core.Service) {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	db, err := sqlite.New(log, "sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	service, err := core.NewService(log, db, nil, core.EscalationPolicy{}, core.AssignmentPolicy{})
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+
+	auth, err := rest.NewAuthenticator(service, log, config.AuthConfig{Enabled: true, AdminTokens: []string{adminToken}})
+	if err != nil {
+		t.Fatalf("create authenticator: %v", err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(service, log, auth)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpclib.NewClient("passthrough:///bufnet",
+		grpclib.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpclib.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewReviewAssignerClient(conn), service
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestScopesCoverMethods(t *testing.T) {
+	for _, method := range pb.ReviewAssigner_ServiceDesc.Methods {
+		fullMethod := "/" + pb.ReviewAssigner_ServiceDesc.ServiceName + "/" + method.MethodName
+		if _, ok := scopes[fullMethod]; !ok {
+			t.Errorf("method without auth policy %s", fullMethod)
+		}
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	client, service := newTestClient(t)
+
+	_, teamsReader, err := service.CreateToken(context.Background(), core.Token{
+		Name:   "reader",
+		Scopes: []core.Scope{core.ScopeTeamsRead},
+	})
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+
+	team := &pb.Team{TeamName: "backend", Members: []*pb.TeamMember{{UserId: "u1", Username: "Alice", IsActive: true}}}
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{
+			name: "no credentials",
+			call: func() error {
+				_, err := client.CreateTeam(context.Background(), &pb.CreateTeamRequest{Team: team})
+				return err
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name: "unknown token",
+			call: func() error {
+				_, err := client.GetStats(withToken("ra_unknown"), &pb.GetStatsRequest{})
+				return err
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name: "missing scope",
+			call: func() error {
+				_, err := client.CreateTeam(withToken(teamsReader), &pb.CreateTeamRequest{Team: team})
+				return err
+			},
+			code: codes.PermissionDenied,
+		},
+		{
+			name: "admin token",
+			call: func() error {
+				_, err := client.CreateTeam(withToken(adminToken), &pb.CreateTeamRequest{Team: team})
+				return err
+			},
+			code: codes.OK,
+		},
+		{
+			name: "scoped token",
+			call: func() error {
+				_, err := client.GetTeam(withToken(teamsReader), &pb.GetTeamRequest{TeamName: "backend"})
+				return err
+			},
+			code: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != tt.code {
+				t.Errorf("got %s, want %s", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestTenantFromToken(t *testing.T) {
+	client, service := newTestClient(t)
+
+	_, acmeReader, err := service.CreateToken(core.WithTenant(context.Background(), "acme"), core.Token{
+		Name:   "reader",
+		Scopes: []core.Scope{core.ScopeTeamsRead},
+	})
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+
+	team := &pb.Team{TeamName: "backend", Members: []*pb.TeamMember{{UserId: "u1", Username: "Alice", IsActive: true}}}
+	if _, err := client.CreateTeam(withToken(adminToken), &pb.CreateTeamRequest{Team: team}); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+
+	withTenant := func(token, tenant string) context.Context {
+		return metadata.AppendToOutgoingContext(withToken(token), "x-tenant", tenant)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{name: "admin token in default tenant", ctx: withToken(adminToken), code: codes.OK},
+		{name: "admin token selecting acme", ctx: withTenant(adminToken, "acme"), code: codes.NotFound},
+		{name: "acme token", ctx: withToken(acmeReader), code: codes.NotFound},
+		{name: "acme token repeating its tenant", ctx: withTenant(acmeReader, "acme"), code: codes.NotFound},
+		{name: "acme token selecting default", ctx: withTenant(acmeReader, "default"), code: codes.PermissionDenied},
+		{name: "invalid tenant", ctx: withTenant(adminToken, "acme/evil"), code: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetTeam(tt.ctx, &pb.GetTeamRequest{TeamName: "backend"})
+			if code := status.Code(err); code != tt.code {
+				t.Errorf("got %s, want %s", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestMemberAccess(t *testing.T) {
+	client, service := newTestClient(t)
+
+	ctx := context.Background()
+	_, err := service.CreateTeam(ctx, core.Team{TeamName: "backend", LeadID: "u1", Members: []core.TeamMember{
+		{UserID: "u1", Username: "Alice", IsActive: true},
+		{UserID: "u2", Username: "Bob", IsActive: true},
+		{UserID: "u3", Username: "Carol", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+
+	token := func(userId string) string {
+		_, secret, err := service.CreateToken(ctx, core.Token{Name: userId, UserID: userId,
+			Scopes: []core.Scope{core.ScopeUsersWrite, core.ScopeStatsRead}})
+		if err != nil {
+			t.Fatalf("create token: %v", err)
+		}
+		return secret
+	}
+	lead, member := token("u1"), token("u2")
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{
+			name: "member deactivates a teammate",
+			call: func() error {
+				_, err := client.SetUserActive(withToken(member), &pb.SetUserActiveRequest{UserId: "u3"})
+				return err
+			},
+			code: codes.PermissionDenied,
+		},
+		{
+			name: "member reads stats",
+			call: func() error {
+				_, err := client.GetStats(withToken(member), &pb.GetStatsRequest{})
+				return err
+			},
+			code: codes.PermissionDenied,
+		},
+		{
+			name: "lead deactivates a member",
+			call: func() error {
+				_, err := client.SetUserActive(withToken(lead), &pb.SetUserActiveRequest{UserId: "u3"})
+				return err
+			},
+			code: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(tt.call()); code != tt.code {
+				t.Errorf("got %s, want %s", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestCreatePullRequestInRepository(t *testing.T) {
+	client, service := newTestClient(t)
+
+	ctx := context.Background()
+	_, err := service.CreateTeam(ctx, core.Team{TeamName: "backend", Members: []core.TeamMember{
+		{UserID: "u1", Username: "Alice", IsActive: true},
+		{UserID: "u2", Username: "Bob", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	if _, err := service.CreateRepository(ctx, core.Repository{Name: "acme/api", Teams: []string{"backend"}}); err != nil {
+		t.Fatalf("create repository: %v", err)
+	}
+
+	resp, err := client.CreatePullRequest(withToken(adminToken), &pb.CreatePullRequestRequest{
+		PullRequestName: "Add search", AuthorId: "u1", Repository: "acme/api", PullRequestNumber: 7,
+	})
+	if err != nil {
+		t.Fatalf("create pull request: %v", err)
+	}
+	if pr := resp.Pr; pr.PullRequestId == "" || pr.Repository != "acme/api" || pr.PullRequestNumber != 7 {
+		t.Errorf("got pull request %v, want a generated id and acme/api#7", pr)
+	}
+
+	tests := []struct {
+		name string
+		req  *pb.CreatePullRequestRequest
+		code codes.Code
+	}{
+		{name: "number taken", code: codes.AlreadyExists, req: &pb.CreatePullRequestRequest{
+			PullRequestName: "Fix search", AuthorId: "u1", Repository: "acme/api", PullRequestNumber: 7}},
+		{name: "unknown repository", code: codes.NotFound, req: &pb.CreatePullRequestRequest{
+			PullRequestName: "Fix search", AuthorId: "u1", Repository: "acme/web", PullRequestNumber: 1}},
+		{name: "repository without number", code: codes.InvalidArgument, req: &pb.CreatePullRequestRequest{
+			PullRequestName: "Fix search", AuthorId: "u1", Repository: "acme/api"}},
+		{name: "neither id nor repository", code: codes.InvalidArgument, req: &pb.CreatePullRequestRequest{
+			PullRequestName: "Fix search", AuthorId: "u1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreatePullRequest(withToken(adminToken), tt.req)
+			if code := status.Code(err); code != tt.code {
+				t.Errorf("got %s (%v), want %s", code, err, tt.code)
+			}
+		})
+	}
+}
+
+func TestCreatePullRequestMetadata(t *testing.T) {
+	client, service := newTestClient(t)
+
+	_, err := service.CreateTeam(context.Background(), core.Team{TeamName: "backend", Members: []core.TeamMember{
+		{UserID: "u1", Username: "Alice", IsActive: true},
+		{UserID: "u2", Username: "Bob", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+
+	resp, err := client.CreatePullRequest(withToken(adminToken), &pb.CreatePullRequestRequest{
+		PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1",
+		Labels: []string{"backend", "search"}, LinesAdded: 120, LinesRemoved: 30, FilesChanged: 4, Priority: "URGENT",
+	})
+	if err != nil {
+		t.Fatalf("create pull request: %v", err)
+	}
+	pr := resp.Pr
+	if !slices.Equal(pr.Labels, []string{"backend", "search"}) || pr.LinesAdded != 120 || pr.LinesRemoved != 30 ||
+		pr.FilesChanged != 4 || pr.Priority != "URGENT" {
+		t.Errorf("got pull request %v, want the metadata back", pr)
+	}
+
+	_, err = client.CreatePullRequest(withToken(adminToken), &pb.CreatePullRequestRequest{
+		PullRequestId: "pr-2", PullRequestName: "Fix search", AuthorId: "u1", Priority: "SOMEDAY",
+	})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("unknown priority: got %s, want %s", code, codes.InvalidArgument)
+	}
+}
@@ -0,0 +1,86 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"review-assigner/core"
+	"sync"
+)
+
+var (
+	ErrQueueFull   = errors.New("notification queue is full")
+	ErrQueueClosed = errors.New("notification queue is closed")
+)
+
+type queued struct {
+	ctx          context.Context
+	notification core.Notification
+}
+
+// Async hands notifications over to a background worker, so that a slow
+// webhook or mail server does not hold up the request that caused them.
+type Async struct {
+	log   *slog.Logger
+	next  core.Notifier
+	queue chan queued
+	done  chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewAsync(log *slog.Logger, next core.Notifier, size int) *Async {
+	a := &Async{
+		log:   log,
+		next:  next,
+		queue: make(chan queued, size),
+		done:  make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// Notify queues the notification, or drops it when the worker is too far
+// behind or already closed.
+func (a *Async) Notify(ctx context.Context, notification core.Notification) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case a.queue <- queued{ctx: context.WithoutCancel(ctx), notification: notification}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (a *Async) run() {
+	defer close(a.done)
+
+	for item := range a.queue {
+		if err := a.next.Notify(item.ctx, item.notification); err != nil {
+			a.log.Warn("failed to deliver notification",
+				"kind", item.notification.Kind,
+				"pr_id", item.notification.PullRequest.PullRequestID,
+				"error", err)
+		}
+	}
+}
+
+// Close delivers the notifications still queued and stops the worker.
+// Notifications sent after Close are rejected with ErrQueueClosed.
+func (a *Async) Close() {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+
+	<-a.done
+}
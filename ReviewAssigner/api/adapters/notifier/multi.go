@@ -0,0 +1,29 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"review-assigner/core"
+)
+
+type Multi []core.Notifier
+
+func (m Multi) Notify(ctx context.Context, notification core.Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// settingKey names a user or team in the notifier configuration. Outside the
+// default tenant names are qualified as "tenant/name", so that equal ids of
+// different organisations never share a channel or an address.
+func settingKey(tenant, name string) string {
+	if tenant == "" || tenant == core.DefaultTenant {
+		return name
+	}
+	return tenant + "/" + name
+}
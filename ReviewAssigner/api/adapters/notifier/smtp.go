@@ -0,0 +1,260 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"review-assigner/config"
+	"review-assigner/core"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const digestTemplate = "digest"
+
+var defaultMailTemplates = map[string]string{
+	string(core.NotificationAssigned): `Subject: Review requested: {{.PullRequest.PullRequestName}}
+
+You have been assigned to review {{.PullRequest.PullRequestID}} "{{.PullRequest.PullRequestName}}" by {{.PullRequest.AuthorID}}.
+`,
+	string(core.NotificationReassigned): `Subject: Review requested: {{.PullRequest.PullRequestName}}
+
+You have been assigned to review {{.PullRequest.PullRequestID}} "{{.PullRequest.PullRequestName}}" by {{.PullRequest.AuthorID}}, replacing {{.OldReviewer}}.
+`,
+	string(core.NotificationReminder): `Subject: Reminder: {{len .PullRequests}} pull request(s) awaiting your review
+
+These pull requests are still waiting for your review, oldest first:
+{{range .PullRequests}}
+  - {{.PullRequestID}} "{{.PullRequestName}}" by {{.AuthorID}}{{if .CreatedAt}}, opened {{.CreatedAt}}{{end}}{{end}}
+`,
+	string(core.NotificationEscalated): `Subject: Escalation: {{.PullRequest.PullRequestName}}
+
+Pull request {{.PullRequest.PullRequestID}} "{{.PullRequest.PullRequestName}}" by {{.PullRequest.AuthorID}} needs your attention: {{.Reason}}.
+`,
+	digestTemplate: `Subject: {{len .PullRequests}} pull request(s) awaiting your review
+
+Hello {{.UserID}}, these pull requests are still waiting for your review:
+{{range .PullRequests}}
+  - {{.PullRequestID}} "{{.PullRequestName}}" by {{.AuthorID}}{{end}}
+`,
+}
+
+type ReviewSource interface {
+	GetTenants(context.Context) ([]string, error)
+	GetPendingReviews(context.Context, time.Time) ([]core.PendingReviews, error)
+}
+
+type Digest struct {
+	UserID       string
+	PullRequests []core.PullRequest
+}
+
+// mailTemplate keeps the subject apart from the body, so that it can be
+// encoded as a header whatever the pull request names contain.
+type mailTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+func parseMailTemplate(name, text string) (mailTemplate, error) {
+	var subject string
+	if line, rest, _ := strings.Cut(text, "\n"); strings.HasPrefix(line, "Subject:") {
+		subject = strings.TrimSpace(strings.TrimPrefix(line, "Subject:"))
+		text = strings.TrimPrefix(rest, "\n")
+	}
+
+	var (
+		tmpl mailTemplate
+		err  error
+	)
+	if tmpl.subject, err = template.New(name).Parse(subject); err != nil {
+		return mailTemplate{}, err
+	}
+	if tmpl.body, err = template.New(name).Parse(text); err != nil {
+		return mailTemplate{}, err
+	}
+	return tmpl, nil
+}
+
+var headerBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+type SMTP struct {
+	log        *slog.Logger
+	addr       string
+	auth       smtp.Auth
+	from       string
+	domain     string
+	recipients map[string]string
+	digestTime string
+	reviews    ReviewSource
+	templates  map[string]mailTemplate
+}
+
+func NewSMTP(log *slog.Logger, cfg config.SMTPConfig, reviews ReviewSource) (*SMTP, error) {
+	m := &SMTP{
+		log:        log,
+		addr:       net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		from:       cfg.From,
+		domain:     cfg.Domain,
+		recipients: cfg.Recipients,
+		digestTime: cfg.DigestTime,
+		reviews:    reviews,
+		templates:  make(map[string]mailTemplate),
+	}
+
+	if cfg.Username != "" {
+		m.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	for name, text := range defaultMailTemplates {
+		if custom, ok := cfg.Templates[name]; ok {
+			text = custom
+		}
+
+		tmpl, err := parseMailTemplate(name, text)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s template: %w", name, err)
+		}
+		m.templates[name] = tmpl
+	}
+
+	if _, err := nextDigest(time.Now(), cfg.DigestTime); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// address falls back to the mail domain only in the default tenant, where
+// user ids are known to be mailbox names.
+func (m *SMTP) address(tenant, userID string) string {
+	if address, ok := m.recipients[settingKey(tenant, userID)]; ok && address != "" {
+		return address
+	}
+	if m.domain != "" && (tenant == "" || tenant == core.DefaultTenant) {
+		return userID + "@" + m.domain
+	}
+	return ""
+}
+
+func (m *SMTP) send(name, to string, data any) error {
+	var subject, body bytes.Buffer
+	if err := m.templates[name].subject.Execute(&subject, data); err != nil {
+		return fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := m.templates[name].body.Execute(&body, data); err != nil {
+		return fmt.Errorf("render %s template: %w", name, err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	if subject.Len() > 0 {
+		fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", headerBreaks.Replace(subject.String())))
+	}
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{to}, msg.Bytes()); err != nil {
+		return fmt.Errorf("send mail to %s: %w", to, err)
+	}
+
+	return nil
+}
+
+func (m *SMTP) Notify(ctx context.Context, notification core.Notification) error {
+	if _, ok := m.templates[string(notification.Kind)]; !ok {
+		return fmt.Errorf("no template for notification kind %q", notification.Kind)
+	}
+
+	var errs []error
+	for _, reviewer := range notification.Reviewers {
+		to := m.address(notification.Tenant, reviewer)
+		if to == "" {
+			m.log.Debug("no email address for reviewer", "user_id", reviewer)
+			continue
+		}
+
+		if err := m.send(string(notification.Kind), to, notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SendDigests mails every active reviewer with an address the pull requests
+// still waiting for them.
+func (m *SMTP) SendDigests(ctx context.Context) error {
+	tenants, err := m.reviews.GetTenants(ctx)
+	if err != nil {
+		return fmt.Errorf("get tenants: %w", err)
+	}
+
+	var errs []error
+	for _, tenant := range tenants {
+		pending, err := m.reviews.GetPendingReviews(core.WithTenant(ctx, tenant), time.Now())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("get pending reviews of tenant %s: %w", tenant, err))
+			continue
+		}
+
+		for _, reviews := range pending {
+			to := m.address(tenant, reviews.User.UserID)
+			if to == "" {
+				m.log.Debug("no email address for reviewer", "tenant", tenant, "user_id", reviews.User.UserID)
+				continue
+			}
+
+			digest := Digest{UserID: reviews.User.UserID, PullRequests: reviews.PullRequests}
+			if err := m.send(digestTemplate, to, digest); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *SMTP) RunDigest(ctx context.Context) {
+	for {
+		next, _ := nextDigest(time.Now(), m.digestTime)
+		m.log.Debug("next email digest scheduled", "at", next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := m.SendDigests(ctx); err != nil {
+			m.log.Warn("failed to send email digest", "error", err)
+		}
+	}
+}
+
+func nextDigest(now time.Time, at string) (time.Time, error) {
+	clock, err := time.Parse("15:04", at)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid digest time %q: %w", at, err)
+	}
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next, nil
+}
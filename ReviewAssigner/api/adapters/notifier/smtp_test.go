@@ -0,0 +1,334 @@
+package notifier
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"review-assigner/config"
+	"review-assigner/core"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sentMail struct {
+	from string
+	to   []string
+	msg  *mail.Message
+	body string
+}
+
+// fakeSMTP speaks just enough SMTP for net/smtp.SendMail and hands every
+// accepted message over to the test.
+type fakeSMTP struct {
+	t     *testing.T
+	host  string
+	port  int
+	mails chan sentMail
+}
+
+func startFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	addr := listener.Addr().(*net.TCPAddr)
+	server := &fakeSMTP{t: t, host: addr.IP.String(), port: addr.Port, mails: make(chan sentMail, 10)}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (f *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP")
+
+	var sent sentMail
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			text.PrintfLine("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			sent = sentMail{from: strings.Trim(line[len("MAIL FROM:"):], "<>")}
+			text.PrintfLine("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			sent.to = append(sent.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			text.PrintfLine("250 OK")
+		case command == "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			sent.msg, err = mail.ReadMessage(strings.NewReader(string(data)))
+			if err != nil {
+				f.t.Errorf("parse message: %v", err)
+				text.PrintfLine("554 malformed")
+				continue
+			}
+			body, _ := io.ReadAll(sent.msg.Body)
+			sent.body = string(body)
+			f.mails <- sent
+			text.PrintfLine("250 OK")
+		case command == "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("250 OK")
+		}
+	}
+}
+
+func (f *fakeSMTP) received(t *testing.T, count int) []sentMail {
+	t.Helper()
+
+	var mails []sentMail
+	for range count {
+		select {
+		case sent := <-f.mails:
+			mails = append(mails, sent)
+		case <-time.After(time.Second):
+			t.Fatalf("got %d mails, want %d", len(mails), count)
+		}
+	}
+	select {
+	case sent := <-f.mails:
+		t.Fatalf("unexpected mail to %v", sent.to)
+	default:
+	}
+
+	slices.SortFunc(mails, func(a, b sentMail) int { return strings.Compare(a.to[0], b.to[0]) })
+	return mails
+}
+
+type fakeReviews map[string][]core.PendingReviews
+
+func (f fakeReviews) GetTenants(context.Context) ([]string, error) {
+	var tenants []string
+	for tenant := range f {
+		tenants = append(tenants, tenant)
+	}
+	return tenants, nil
+}
+
+func (f fakeReviews) GetPendingReviews(ctx context.Context, _ time.Time) ([]core.PendingReviews, error) {
+	return f[core.TenantFrom(ctx)], nil
+}
+
+func newTestSMTP(t *testing.T, server *fakeSMTP, reviews ReviewSource) *SMTP {
+	t.Helper()
+
+	mailer, err := NewSMTP(slog.New(slog.NewTextHandler(io.Discard, nil)), config.SMTPConfig{
+		Host:       server.host,
+		Port:       server.port,
+		From:       "review-assigner@example.com",
+		Domain:     "example.com",
+		Recipients: map[string]string{"u2": "bob@mail.example.com", "acme/u2": "bob@acme.example.com"},
+		DigestTime: "09:00",
+	}, reviews)
+	if err != nil {
+		t.Fatalf("NewSMTP: %v", err)
+	}
+	return mailer
+}
+
+func subject(t *testing.T, sent sentMail) string {
+	t.Helper()
+
+	decoded, err := new(mime.WordDecoder).DecodeHeader(sent.msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+	return decoded
+}
+
+func TestSMTPNotify(t *testing.T) {
+	server := startFakeSMTP(t)
+	mailer := newTestSMTP(t, server, fakeReviews{})
+
+	err := mailer.Notify(context.Background(), core.Notification{
+		Kind:        core.NotificationAssigned,
+		PullRequest: core.PullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"},
+		Reviewers:   []string{"u2", "u3"},
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	mails := server.received(t, 2)
+	for i, to := range []string{"bob@mail.example.com", "u3@example.com"} {
+		if mails[i].from != "review-assigner@example.com" || !slices.Equal(mails[i].to, []string{to}) ||
+			mails[i].msg.Header.Get("To") != to {
+			t.Errorf("mail %d went from %s to %v, want %s", i, mails[i].from, mails[i].to, to)
+		}
+		if got := subject(t, mails[i]); got != "Review requested: Add search" {
+			t.Errorf("subject %q", got)
+		}
+		if !strings.Contains(mails[i].body, `You have been assigned to review pr-1 "Add search" by u1.`) {
+			t.Errorf("body %q", mails[i].body)
+		}
+	}
+}
+
+func TestSMTPNotifyOtherTenant(t *testing.T) {
+	server := startFakeSMTP(t)
+	mailer := newTestSMTP(t, server, fakeReviews{})
+
+	err := mailer.Notify(context.Background(), core.Notification{
+		Kind:        core.NotificationEscalated,
+		Tenant:      "acme",
+		PullRequest: core.PullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"},
+		Reviewers:   []string{"u2", "u3"},
+		Reason:      "no approvals",
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	mails := server.received(t, 1)
+	if !slices.Equal(mails[0].to, []string{"bob@acme.example.com"}) {
+		t.Errorf("mail went to %v", mails[0].to)
+	}
+}
+
+func TestSMTPSubjectHeaderInjection(t *testing.T) {
+	server := startFakeSMTP(t)
+	mailer := newTestSMTP(t, server, fakeReviews{})
+
+	err := mailer.Notify(context.Background(), core.Notification{
+		Kind: core.NotificationAssigned,
+		PullRequest: core.PullRequest{PullRequestID: "pr-1", AuthorID: "u1",
+			PullRequestName: "Fix\r\nBcc: eve@evil.example.com\nX-Priority: 1 — срочно"},
+		Reviewers: []string{"u2"},
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	sent := server.received(t, 1)[0]
+	for _, header := range []string{"Bcc", "X-Priority"} {
+		if value := sent.msg.Header.Get(header); value != "" {
+			t.Errorf("injected header %s: %s", header, value)
+		}
+	}
+	if got := subject(t, sent); got != "Review requested: Fix Bcc: eve@evil.example.com X-Priority: 1 — срочно" {
+		t.Errorf("subject %q", got)
+	}
+}
+
+func TestSMTPSendDigests(t *testing.T) {
+	server := startFakeSMTP(t)
+	openPR := func(id string) core.PullRequest {
+		return core.PullRequest{PullRequestID: id, PullRequestName: "Change " + id, AuthorID: "u1", Status: "OPEN"}
+	}
+	mailer := newTestSMTP(t, server, fakeReviews{
+		core.DefaultTenant: {
+			{User: core.User{UserID: "u2"}, PullRequests: []core.PullRequest{openPR("pr-1"), openPR("pr-2")}},
+			{User: core.User{UserID: "u3"}, PullRequests: []core.PullRequest{openPR("pr-3")}},
+		},
+		"acme": {
+			{User: core.User{UserID: "u2"}, PullRequests: []core.PullRequest{openPR("pr-4")}},
+			{User: core.User{UserID: "u5"}, PullRequests: []core.PullRequest{openPR("pr-5")}},
+		},
+	})
+
+	if err := mailer.SendDigests(context.Background()); err != nil {
+		t.Fatalf("SendDigests: %v", err)
+	}
+
+	mails := server.received(t, 3)
+	want := []struct {
+		to       string
+		subject  string
+		included []string
+	}{
+		{to: "bob@acme.example.com", subject: "1 pull request(s) awaiting your review", included: []string{"pr-4"}},
+		{to: "bob@mail.example.com", subject: "2 pull request(s) awaiting your review", included: []string{"pr-1", "pr-2"}},
+		{to: "u3@example.com", subject: "1 pull request(s) awaiting your review", included: []string{"pr-3"}},
+	}
+	for i, w := range want {
+		if !slices.Equal(mails[i].to, []string{w.to}) {
+			t.Errorf("mail %d went to %v, want %s", i, mails[i].to, w.to)
+		}
+		if got := subject(t, mails[i]); got != w.subject {
+			t.Errorf("mail to %s has subject %q, want %q", w.to, got, w.subject)
+		}
+		for _, id := range w.included {
+			if !strings.Contains(mails[i].body, strconv.Quote("Change "+id)) {
+				t.Errorf("digest to %s misses %s: %q", w.to, id, mails[i].body)
+			}
+		}
+	}
+}
+
+func TestSMTPRunDigestStopsWithContext(t *testing.T) {
+	mailer := newTestSMTP(t, startFakeSMTP(t), fakeReviews{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		mailer.RunDigest(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunDigest kept waiting for the next digest after the context was cancelled")
+	}
+}
+
+func TestNextDigest(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, time.March, 10, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		now  time.Time
+		at   string
+		want time.Time
+	}{
+		{now: at(8, 30), at: "09:00", want: at(9, 0)},
+		{now: at(9, 0), at: "09:00", want: at(9, 0).AddDate(0, 0, 1)},
+		{now: at(17, 45), at: "09:00", want: at(9, 0).AddDate(0, 0, 1)},
+		{now: at(23, 59), at: "00:00", want: at(0, 0).AddDate(0, 0, 1)},
+	}
+	for _, tt := range tests {
+		got, err := nextDigest(tt.now, tt.at)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("nextDigest(%s, %s) = %v, %v, want %v", tt.now.Format("15:04"), tt.at, got, err, tt.want)
+		}
+	}
+
+	if _, err := nextDigest(at(8, 0), "9am"); err == nil {
+		t.Error("invalid digest time was accepted")
+	}
+}
@@ -0,0 +1,194 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"review-assigner/config"
+	"review-assigner/core"
+	"strings"
+	"text/template"
+)
+
+var defaultWebhookTemplates = map[core.NotificationKind]string{
+	core.NotificationAssigned: `{{mention .PullRequest.AuthorID}} opened *{{.PullRequest.PullRequestName}}* ` +
+		`({{.PullRequest.PullRequestID}}), review requested from {{mentions .Reviewers}}`,
+	core.NotificationReassigned: `Review of *{{.PullRequest.PullRequestName}}* ({{.PullRequest.PullRequestID}}) ` +
+		`moved from {{mention .OldReviewer}} to {{mentions .Reviewers}}`,
+	core.NotificationReminder: `{{mentions .Reviewers}}, {{len .PullRequests}} pull request(s) are waiting for your review:` +
+		`{{range .PullRequests}}` + "\n" + `• *{{.PullRequestName}}* ({{.PullRequestID}}) by {{mention .AuthorID}}{{end}}`,
+	core.NotificationEscalated: `:rotating_light: {{mentions .Reviewers}}, *{{.PullRequest.PullRequestName}}* ` +
+		`({{.PullRequest.PullRequestID}}) by {{mention .PullRequest.AuthorID}} needs attention: {{.Reason}}`,
+}
+
+type WebhookPayload struct {
+	Channel   string `json:"channel,omitempty"`
+	Username  string `json:"username,omitempty"`
+	IconEmoji string `json:"icon_emoji,omitempty"`
+	Text      string `json:"text"`
+}
+
+type Webhook struct {
+	log            *slog.Logger
+	client         *http.Client
+	url            string
+	username       string
+	iconEmoji      string
+	defaultChannel string
+	channels       map[string]string
+	handles        map[string]string
+	templates      map[core.NotificationKind]*template.Template
+}
+
+func NewWebhook(log *slog.Logger, cfg config.WebhookConfig) (*Webhook, error) {
+	w := &Webhook{
+		log:            log,
+		client:         &http.Client{Timeout: cfg.Timeout},
+		url:            cfg.URL,
+		username:       cfg.Username,
+		iconEmoji:      cfg.IconEmoji,
+		defaultChannel: cfg.DefaultChannel,
+		channels:       cfg.Channels,
+		handles:        cfg.Handles,
+		templates:      make(map[core.NotificationKind]*template.Template),
+	}
+
+	funcs := w.funcs(core.DefaultTenant)
+
+	for kind, text := range defaultWebhookTemplates {
+		if custom, ok := cfg.Templates[string(kind)]; ok {
+			text = custom
+		}
+
+		tmpl, err := template.New(string(kind)).Funcs(funcs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s template: %w", kind, err)
+		}
+		w.templates[kind] = tmpl
+	}
+
+	return w, nil
+}
+
+func (w *Webhook) funcs(tenant string) template.FuncMap {
+	return template.FuncMap{
+		"mention": func(userID string) string {
+			return w.mention(tenant, userID)
+		},
+		"mentions": func(userIDs []string) string {
+			return w.mentions(tenant, userIDs)
+		},
+	}
+}
+
+func (w *Webhook) mention(tenant, userID string) string {
+	if handle, ok := w.handles[settingKey(tenant, userID)]; ok && handle != "" {
+		return handle
+	}
+	return mrkdwnEscaper.Replace(userID)
+}
+
+func (w *Webhook) mentions(tenant string, userIDs []string) string {
+	handles := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		handles = append(handles, w.mention(tenant, userID))
+	}
+	return strings.Join(handles, ", ")
+}
+
+func (w *Webhook) channel(tenant, teamName string) string {
+	if channel, ok := w.channels[settingKey(tenant, teamName)]; ok && channel != "" {
+		return channel
+	}
+	return w.defaultChannel
+}
+
+// mrkdwnEscaper escapes the characters Slack and Mattermost treat as control
+// sequences, so that a PR title like "<!channel>" is shown rather than acted on.
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// escaped copies the free text of a notification with mrkdwn escaped. User
+// ids are left alone: they are looked up as handles and escaped by mention.
+func escaped(notification core.Notification) core.Notification {
+	escapePR := func(pullRequest core.PullRequest) core.PullRequest {
+		pullRequest.PullRequestID = mrkdwnEscaper.Replace(pullRequest.PullRequestID)
+		pullRequest.PullRequestName = mrkdwnEscaper.Replace(pullRequest.PullRequestName)
+		pullRequest.Repository = mrkdwnEscaper.Replace(pullRequest.Repository)
+		return pullRequest
+	}
+
+	notification.TeamName = mrkdwnEscaper.Replace(notification.TeamName)
+	notification.Reason = mrkdwnEscaper.Replace(notification.Reason)
+	notification.PullRequest = escapePR(notification.PullRequest)
+	pullRequests := make([]core.PullRequest, 0, len(notification.PullRequests))
+	for _, pullRequest := range notification.PullRequests {
+		pullRequests = append(pullRequests, escapePR(pullRequest))
+	}
+	notification.PullRequests = pullRequests
+	return notification
+}
+
+func (w *Webhook) Render(notification core.Notification) (WebhookPayload, error) {
+	tmpl, ok := w.templates[notification.Kind]
+	if !ok {
+		return WebhookPayload{}, fmt.Errorf("no template for notification kind %q", notification.Kind)
+	}
+
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return WebhookPayload{}, err
+	}
+	tmpl.Funcs(w.funcs(notification.Tenant))
+
+	var text bytes.Buffer
+	if err := tmpl.Execute(&text, escaped(notification)); err != nil {
+		return WebhookPayload{}, fmt.Errorf("render %s template: %w", notification.Kind, err)
+	}
+
+	return WebhookPayload{
+		Channel:   w.channel(notification.Tenant, notification.TeamName),
+		Username:  w.username,
+		IconEmoji: w.iconEmoji,
+		Text:      text.String(),
+	}, nil
+}
+
+func (w *Webhook) Notify(ctx context.Context, notification core.Notification) error {
+	payload, err := w.Render(notification)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(payload); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	w.log.Debug("webhook notification sent",
+		"kind", notification.Kind,
+		"channel", payload.Channel,
+		"pr_id", notification.PullRequest.PullRequestID)
+
+	return nil
+}
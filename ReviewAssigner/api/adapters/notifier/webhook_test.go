@@ -0,0 +1,177 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"review-assigner/config"
+	"review-assigner/core"
+	"testing"
+	"time"
+)
+
+func newTestWebhook(t *testing.T, url string) *Webhook {
+	t.Helper()
+
+	webhook, err := NewWebhook(slog.New(slog.NewTextHandler(io.Discard, nil)), config.WebhookConfig{
+		URL:            url,
+		Timeout:        time.Second,
+		Username:       "review-assigner",
+		DefaultChannel: "#code-review",
+		Channels:       map[string]string{"backend": "#backend", "acme/backend": "#acme-backend"},
+		Handles:        map[string]string{"u1": "<@U01>", "u2": "<@U02>"},
+	})
+	if err != nil {
+		t.Fatalf("NewWebhook: %v", err)
+	}
+	return webhook
+}
+
+func receiver(t *testing.T, status int) (*httptest.Server, <-chan WebhookPayload) {
+	t.Helper()
+
+	payloads := make(chan WebhookPayload, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+
+		var payload WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		payloads <- payload
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, payloads
+}
+
+func TestWebhookRender(t *testing.T) {
+	webhook := newTestWebhook(t, "http://localhost")
+	pullRequest := core.PullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"}
+
+	tests := []struct {
+		name         string
+		notification core.Notification
+		channel      string
+		text         string
+	}{
+		{
+			name: "assigned",
+			notification: core.Notification{Kind: core.NotificationAssigned, TeamName: "backend",
+				PullRequest: pullRequest, Reviewers: []string{"u2", "u3"}},
+			channel: "#backend",
+			text:    "<@U01> opened *Add search* (pr-1), review requested from <@U02>, u3",
+		},
+		{
+			name: "reassigned in another tenant",
+			notification: core.Notification{Kind: core.NotificationReassigned, Tenant: "acme", TeamName: "backend",
+				PullRequest: pullRequest, Reviewers: []string{"u3"}, OldReviewer: "u2"},
+			channel: "#acme-backend",
+			text:    "Review of *Add search* (pr-1) moved from u2 to u3",
+		},
+		{
+			name: "escalated to the default channel",
+			notification: core.Notification{Kind: core.NotificationEscalated, TeamName: "frontend",
+				PullRequest: pullRequest, Reviewers: []string{"u2"}, Reason: "no approvals"},
+			channel: "#code-review",
+			text:    ":rotating_light: <@U02>, *Add search* (pr-1) by <@U01> needs attention: no approvals",
+		},
+		{
+			name: "control sequences are escaped",
+			notification: core.Notification{Kind: core.NotificationEscalated, TeamName: "backend",
+				PullRequest: core.PullRequest{PullRequestID: "pr-1", PullRequestName: "<!channel> Fix a&b", AuthorID: "u<9>"},
+				Reviewers:   []string{"u1"}, Reason: "<https://evil.example.com|click>"},
+			channel: "#backend",
+			text: ":rotating_light: <@U01>, *&lt;!channel&gt; Fix a&amp;b* (pr-1) by u&lt;9&gt; needs attention: " +
+				"&lt;https://evil.example.com|click&gt;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := webhook.Render(tt.notification)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if payload.Channel != tt.channel || payload.Text != tt.text || payload.Username != "review-assigner" {
+				t.Errorf("got %+v, want channel %q and text %q", payload, tt.channel, tt.text)
+			}
+		})
+	}
+}
+
+func TestWebhookNotify(t *testing.T) {
+	server, payloads := receiver(t, http.StatusOK)
+	webhook := newTestWebhook(t, server.URL)
+
+	err := webhook.Notify(context.Background(), core.Notification{
+		Kind:         core.NotificationReminder,
+		TeamName:     "backend",
+		PullRequests: []core.PullRequest{{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"}},
+		Reviewers:    []string{"u2"},
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	payload := <-payloads
+	want := "<@U02>, 1 pull request(s) are waiting for your review:\n• *Add search* (pr-1) by <@U01>"
+	if payload.Channel != "#backend" || payload.Text != want {
+		t.Errorf("got %+v, want text %q", payload, want)
+	}
+}
+
+func TestWebhookNotifyRejected(t *testing.T) {
+	server, _ := receiver(t, http.StatusInternalServerError)
+	webhook := newTestWebhook(t, server.URL)
+
+	err := webhook.Notify(context.Background(), core.Notification{Kind: core.NotificationAssigned})
+	if err == nil {
+		t.Fatal("expected an error for a failing receiver")
+	}
+}
+
+func TestAsyncDelivers(t *testing.T) {
+	server, payloads := receiver(t, http.StatusOK)
+	async := NewAsync(slog.New(slog.NewTextHandler(io.Discard, nil)), newTestWebhook(t, server.URL), 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := async.Notify(ctx, core.Notification{
+		Kind:        core.NotificationAssigned,
+		PullRequest: core.PullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"},
+		Reviewers:   []string{"u2"},
+	})
+	cancel()
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	async.Close()
+
+	select {
+	case payload := <-payloads:
+		if payload.Text != "<@U01> opened *Add search* (pr-1), review requested from <@U02>" {
+			t.Errorf("unexpected text %q", payload.Text)
+		}
+	default:
+		t.Fatal("notification was not delivered before Close returned")
+	}
+}
+
+func TestAsyncNotifyAfterClose(t *testing.T) {
+	server, _ := receiver(t, http.StatusOK)
+	async := NewAsync(slog.New(slog.NewTextHandler(io.Discard, nil)), newTestWebhook(t, server.URL), 10)
+	async.Close()
+	async.Close()
+
+	err := async.Notify(context.Background(), core.Notification{Kind: core.NotificationAssigned})
+	if !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("got %v, want ErrQueueClosed", err)
+	}
+}
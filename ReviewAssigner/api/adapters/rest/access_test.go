@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"net/http"
+	"review-assigner/core"
+	"testing"
+)
+
+func TestMemberAccess(t *testing.T) {
+	api := newTestAPI(t)
+	api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/team/add", token: testAdminToken,
+		body: `{"team_name":"backend","members":[` +
+			`{"user_id":"u1","username":"Alice","is_active":true},` +
+			`{"user_id":"u2","username":"Bob","is_active":true},` +
+			`{"user_id":"u3","username":"Carol","is_active":true}]}`})
+	api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/team/add", token: testAdminToken,
+		body: `{"team_name":"frontend","members":[{"user_id":"u4","username":"Dave","is_active":true}]}`})
+	api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/pullRequest/create", token: testAdminToken,
+		body: `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`})
+
+	scopes := []core.Scope{core.ScopePRsRead, core.ScopeUsersWrite, core.ScopeStatsRead}
+	author := api.token(t, "", core.Token{Name: "author", UserID: "u1", Scopes: scopes})
+	outsider := api.token(t, "", core.Token{Name: "outsider", UserID: "u4", Scopes: scopes})
+
+	tests := []struct {
+		name   string
+		req    testRequest
+		status int
+	}{
+		{name: "author reads the PR", status: http.StatusOK,
+			req: testRequest{method: "GET", path: "/pullRequest/get?pull_request_id=pr-1", token: author}},
+		{name: "outsider reads the PR", status: http.StatusForbidden,
+			req: testRequest{method: "GET", path: "/pullRequest/get?pull_request_id=pr-1", token: outsider}},
+		{name: "outsider reads the PR in v2", status: http.StatusForbidden,
+			req: testRequest{method: "GET", path: "/v2/pull-requests/pr-1", token: outsider}},
+		{name: "author lists own PRs", status: http.StatusOK,
+			req: testRequest{method: "GET", path: "/pullRequest/list?author_id=u1", token: author}},
+		{name: "member lists every PR", status: http.StatusForbidden,
+			req: testRequest{method: "GET", path: "/pullRequest/list", token: author}},
+		{name: "member reads stats", status: http.StatusForbidden,
+			req: testRequest{method: "GET", path: "/stats", token: author}},
+		{name: "outsider deactivates a member", status: http.StatusForbidden,
+			req: testRequest{method: "POST", path: "/users/setIsActive", token: outsider,
+				body: `{"user_id":"u2","is_active":false}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api.must(t, tt.status, tt.req)
+		})
+	}
+}
@@ -1,35 +1,65 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"log/slog"
 	"net/http"
 	"review-assigner/core"
+	"strconv"
 )
 
 type Handler struct {
-	service *core.Service
-	log     *slog.Logger
+	service     *core.Service
+	log         *slog.Logger
+	auth        *Authenticator
+	idempotency *Idempotency
 }
 
-func NewHandler(service *core.Service, log *slog.Logger) http.Handler {
+func NewHandler(service *core.Service, log *slog.Logger, auth *Authenticator, idempotency *Idempotency) http.Handler {
 	h := &Handler{
-		service: service,
-		log:     log,
+		service:     service,
+		log:         log,
+		auth:        auth,
+		idempotency: idempotency,
 	}
 
+	router := h.routes()
+	router.Use(h.authenticate)
+	router.Use(h.idempotency.middleware)
+	router.Use(validateRequests(mustLoadSpec()))
+
+	return router
+}
+
+func (h *Handler) routes() *mux.Router {
 	router := mux.NewRouter()
 	router.HandleFunc("/team/add", h.CreateTeam).Methods("POST")
 	router.HandleFunc("/team/get", h.GetTeam).Methods("GET")
+	router.HandleFunc("/team/setLead", h.SetTeamLead).Methods("POST")
 	router.HandleFunc("/users/setIsActive", h.SetUserActive).Methods("POST")
+	router.HandleFunc("/users/setOutOfOffice", h.SetUserOutOfOffice).Methods("POST")
 	router.HandleFunc("/users/getReview", h.GetUserReviews).Methods("GET")
+	router.HandleFunc("/pullRequest/list", h.ListPullRequests).Methods("GET")
+	router.HandleFunc("/pullRequest/get", h.GetPullRequest).Methods("GET")
 	router.HandleFunc("/pullRequest/create", h.CreatePullRequest).Methods("POST")
+	router.HandleFunc("/pullRequest/approve", h.ApprovePullRequest).Methods("POST")
 	router.HandleFunc("/pullRequest/merge", h.MergePullRequest).Methods("POST")
 	router.HandleFunc("/pullRequest/reassign", h.ReassignPullRequest).Methods("POST")
+	router.HandleFunc("/repository/add", h.CreateRepository).Methods("POST")
+	router.HandleFunc("/repository/get", h.GetRepository).Methods("GET")
+	router.HandleFunc("/repository/linkTeam", h.LinkRepository).Methods("POST")
 	router.HandleFunc("/stats", h.GetStats).Methods("GET")
+	router.HandleFunc("/openapi.json", h.GetOpenAPI).Methods("GET")
+	router.HandleFunc("/admin/tokens", h.CreateToken).Methods("POST")
+	router.HandleFunc("/admin/tokens", h.ListTokens).Methods("GET")
+	router.HandleFunc("/admin/tokens/{id}", h.RevokeToken).Methods("DELETE")
+	router.HandleFunc("/admin/export", h.Export).Methods("GET")
+	router.HandleFunc("/admin/import", h.Import).Methods("POST")
+	router.HandleFunc("/admin/users/import", h.ImportUsers).Methods("POST")
+	h.registerV2(router)
 
 	return router
 }
@@ -55,6 +85,7 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 
 	team := core.Team{
 		TeamName: req.TeamName,
+		LeadID:   req.LeadID,
 	}
 
 	for _, member := range req.Members {
@@ -71,7 +102,15 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusBadRequest, "TEAM_EXISTS", err.Error())
 			return
 		}
-		fmt.Printf("DEBUG ERROR: %v\n", err)
+		if errors.Is(err, core.ErrLeadNotMember) {
+			writeError(w, http.StatusBadRequest, "LEAD_NOT_MEMBER", err.Error())
+			return
+		}
+		if errors.Is(err, core.ErrForbidden) {
+			writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		h.log.Error("failed to create team", "team_name", team.TeamName, "error", err)
 		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to create team")
 		return
 	}
@@ -88,6 +127,7 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 func toTeamResponse(team core.Team) TeamResponse {
 	response := TeamResponse{
 		TeamName: team.TeamName,
+		LeadID:   team.LeadID,
 	}
 
 	for _, member := range team.Members {
@@ -127,6 +167,43 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+func (h *Handler) SetTeamLead(w http.ResponseWriter, r *http.Request) {
+	var req SetTeamLeadRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	if req.TeamName == "" || req.UserID == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELDS", "team_name and user_id are required")
+		return
+	}
+
+	team, err := h.service.SetTeamLead(r.Context(), req.TeamName, req.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, core.ErrTeamNotFound):
+			writeError(w, http.StatusNotFound, "TEAM_NOT_FOUND", err.Error())
+		case errors.Is(err, core.ErrLeadNotMember):
+			writeError(w, http.StatusBadRequest, "LEAD_NOT_MEMBER", err.Error())
+		case errors.Is(err, core.ErrForbidden):
+			writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := SetTeamLeadResponse{
+		Team: toTeamResponse(team),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
 func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	var req SetUserActiveRequest
 
@@ -146,6 +223,10 @@ func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, "USER_NOT_FOUND", err.Error())
 			return
 		}
+		if errors.Is(err, core.ErrForbidden) {
+			writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -159,12 +240,49 @@ func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+func (h *Handler) SetUserOutOfOffice(w http.ResponseWriter, r *http.Request) {
+	var req SetOutOfOfficeRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	if req.UserID == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "user_id is required")
+		return
+	}
+
+	user, err := h.service.SetOutOfOffice(r.Context(), req.UserID, req.Until)
+	if err != nil {
+		if errors.Is(err, core.ErrUserNotFound) {
+			writeError(w, http.StatusNotFound, "USER_NOT_FOUND", err.Error())
+			return
+		}
+		if errors.Is(err, core.ErrForbidden) {
+			writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := SetOutOfOfficeResponse{
+		User: toUserResponse(user),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
 func toUserResponse(user core.User) UserResponse {
 	return UserResponse{
-		UserID:   user.UserID,
-		Username: user.Username,
-		TeamName: user.TeamName,
-		IsActive: user.IsActive,
+		UserID:           user.UserID,
+		Username:         user.Username,
+		TeamName:         user.TeamName,
+		IsActive:         user.IsActive,
+		OutOfOfficeUntil: user.OutOfOfficeUntil,
 	}
 }
 
@@ -176,26 +294,37 @@ func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.PullRequestID == "" || req.PullRequestName == "" || req.AuthorID == "" {
-		writeError(w, http.StatusBadRequest, "MISSING_FIELDS", "pull_request_id, pull_request_name and author_id are required")
+	if (req.PullRequestID == "" && req.Repository == "") || req.PullRequestName == "" || req.AuthorID == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELDS",
+			"pull_request_id or repository, pull_request_name and author_id are required")
 		return
 	}
 
-	pr, err := h.service.CreatePR(r.Context(), core.PullRequest{
+	pr, err := h.service.CreatePR(r.Context(), withPRMetadata(core.PullRequest{
 		PullRequestID:   req.PullRequestID,
+		Repository:      req.Repository,
+		Number:          req.PullRequestNumber,
 		PullRequestName: req.PullRequestName,
 		AuthorID:        req.AuthorID,
-	})
+	}, req.PRMetadataDTO))
 	if err != nil {
 		switch {
 		case errors.Is(err, core.ErrPRAAlreadyExists):
 			writeError(w, http.StatusConflict, "PR_EXISTS", err.Error())
+		case errors.Is(err, core.ErrRepositoryNotFound):
+			writeError(w, http.StatusNotFound, "REPOSITORY_NOT_FOUND", err.Error())
+		case errors.Is(err, core.ErrInvalidRepository):
+			writeError(w, http.StatusBadRequest, "INVALID_REPOSITORY", err.Error())
+		case errors.Is(err, core.ErrInvalidPRMetadata):
+			writeError(w, http.StatusBadRequest, "INVALID_PR_METADATA", err.Error())
 		case errors.Is(err, core.ErrUserNotFound):
 			writeError(w, http.StatusNotFound, "AUTHOR_NOT_FOUND", err.Error())
 		case errors.Is(err, core.ErrTeamNotFound):
 			writeError(w, http.StatusNotFound, "TEAM_NOT_FOUND", err.Error())
 		case errors.Is(err, core.ErrNotEnoughReviewers):
 			writeError(w, http.StatusConflict, "NOT_ENOUGH_REVIEWERS", err.Error())
+		case errors.Is(err, core.ErrForbidden):
+			writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
 		default:
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		}
@@ -206,6 +335,7 @@ func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
 		PR: toPRResponse(pr),
 	}
 
+	setETag(w, pr)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
@@ -214,11 +344,164 @@ func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
 func toPRResponse(pr core.PullRequest) PRResponse {
 	return PRResponse{
 		PullRequestID:     pr.PullRequestID,
+		Repository:        pr.Repository,
+		PullRequestNumber: pr.Number,
 		PullRequestName:   pr.PullRequestName,
 		AuthorID:          pr.AuthorID,
 		Status:            string(pr.Status),
 		AssignedReviewers: pr.AssignedReviewers,
+		CreatedAt:         pr.CreatedAt,
+		MergedAt:          pr.MergedAt,
+		PRMetadataDTO:     toPRMetadataDTO(pr),
+	}
+}
+
+func toPRMetadataDTO(pr core.PullRequest) PRMetadataDTO {
+	return PRMetadataDTO{
+		Labels:       pr.Labels,
+		LinesAdded:   pr.LinesAdded,
+		LinesRemoved: pr.LinesRemoved,
+		FilesChanged: pr.FilesChanged,
+		Priority:     string(pr.Priority),
+	}
+}
+
+func withPRMetadata(pr core.PullRequest, metadata PRMetadataDTO) core.PullRequest {
+	pr.Labels = metadata.Labels
+	pr.LinesAdded = metadata.LinesAdded
+	pr.LinesRemoved = metadata.LinesRemoved
+	pr.FilesChanged = metadata.FilesChanged
+	pr.Priority = core.Priority(metadata.Priority)
+	return pr
+}
+
+func (h *Handler) GetPullRequest(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	prID := query.Get("pull_request_id")
+	if prID == "" && query.Get("repository") == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAMETER",
+			"pull_request_id or repository and pull_request_number parameters are required")
+		return
+	}
+
+	if prID == "" {
+		number, err := strconv.ParseInt(query.Get("pull_request_number"), 10, 64)
+		if err != nil {
+			writeServiceError(w, core.ErrInvalidRepository)
+			return
+		}
+		var ok bool
+		if prID, ok = h.resolvePR(w, r, "", query.Get("repository"), number); !ok {
+			return
+		}
+	}
+
+	details, err := h.service.GetPRDetails(r.Context(), prID)
+	if err != nil {
+		if errors.Is(err, core.ErrPRNotFound) {
+			writeError(w, http.StatusNotFound, "PR_NOT_FOUND", err.Error())
+			return
+		}
+		if errors.Is(err, core.ErrForbidden) {
+			writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+		return
+	}
+
+	response := GetPRResponse{
+		PR: toPRDetailsDTO(details),
+	}
+
+	setETag(w, details.PullRequest)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func toPRDetailsDTO(details core.PRDetails) PRDetailsDTO {
+	dto := PRDetailsDTO{
+		PRResponse: toPRResponse(details.PullRequest),
+		Reviewers:  make([]ReviewerDTO, 0, len(details.Reviewers)),
+		History:    make([]HistoryEntryDTO, 0, len(details.History)),
+	}
+
+	for _, reviewer := range details.Reviewers {
+		dto.Reviewers = append(dto.Reviewers, ReviewerDTO{
+			UserID:      reviewer.User.UserID,
+			Username:    reviewer.User.Username,
+			TeamName:    reviewer.User.TeamName,
+			IsActive:    reviewer.User.IsActive,
+			ReviewState: string(reviewer.State),
+			ApprovedAt:  reviewer.ApprovedAt,
+		})
+	}
+
+	for _, entry := range details.History {
+		dto.History = append(dto.History, HistoryEntryDTO{
+			Event:     string(entry.Event),
+			ActorID:   entry.ActorID,
+			Details:   entry.Details,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+
+	return dto
+}
+
+func (h *Handler) ApprovePullRequest(w http.ResponseWriter, r *http.Request) {
+	var req ApprovePRRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	if (req.PullRequestID == "" && req.Repository == "") || req.UserID == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELDS", "pull_request_id or repository and user_id are required")
+		return
+	}
+
+	prID, ok := h.resolvePR(w, r, req.PullRequestID, req.Repository, req.PullRequestNumber)
+	if !ok {
+		return
+	}
+
+	r, ok = ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	pr, err := h.service.Approve(r.Context(), prID, req.UserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, core.ErrPRNotFound):
+			writeError(w, http.StatusNotFound, "PR_NOT_FOUND", err.Error())
+		case errors.Is(err, core.ErrPRAlreadyMerged):
+			writeError(w, http.StatusConflict, "PR_MERGED", err.Error())
+		case errors.Is(err, core.ErrReviewerNotAssigned):
+			writeError(w, http.StatusConflict, "NOT_ASSIGNED", err.Error())
+		case errors.Is(err, core.ErrForbidden):
+			writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+		case errors.Is(err, core.ErrPreconditionFailed):
+			writeError(w, http.StatusPreconditionFailed, "PRECONDITION_FAILED", err.Error())
+		case errors.Is(err, core.ErrPRConflict):
+			writeError(w, http.StatusConflict, "PR_CONFLICT", err.Error())
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
 	}
+
+	response := ApprovePRResponse{
+		PR: toPRResponse(pr),
+	}
+
+	setETag(w, pr)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
 }
 
 func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
@@ -229,17 +512,43 @@ func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.PullRequestID == "" {
-		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "pull_request_id is required")
+	if req.PullRequestID == "" && req.Repository == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELD", "pull_request_id or repository is required")
+		return
+	}
+
+	prID, ok := h.resolvePR(w, r, req.PullRequestID, req.Repository, req.PullRequestNumber)
+	if !ok {
 		return
 	}
 
-	pr, err := h.service.Merged(r.Context(), req.PullRequestID)
+	r, ok = ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	pr, err := h.service.Merged(r.Context(), prID)
 	if err != nil {
 		if errors.Is(err, core.ErrPRNotFound) {
 			writeError(w, http.StatusNotFound, "PR_NOT_FOUND", err.Error())
 			return
 		}
+		if errors.Is(err, core.ErrPRAlreadyMerged) {
+			writeError(w, http.StatusConflict, "PR_MERGED", err.Error())
+			return
+		}
+		if errors.Is(err, core.ErrForbidden) {
+			writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		if errors.Is(err, core.ErrPreconditionFailed) {
+			writeError(w, http.StatusPreconditionFailed, "PRECONDITION_FAILED", err.Error())
+			return
+		}
+		if errors.Is(err, core.ErrPRConflict) {
+			writeError(w, http.StatusConflict, "PR_CONFLICT", err.Error())
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -248,6 +557,7 @@ func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
 		PR: toPRResponse(pr),
 	}
 
+	setETag(w, pr)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
@@ -260,16 +570,26 @@ func (h *Handler) ReassignPullRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.PullRequestID == "" || req.OldUserID == "" {
-		writeError(w, http.StatusBadRequest, "MISSING_FIELDS", "pull_request_id and old_user_id are required")
+	if (req.PullRequestID == "" && req.Repository == "") || req.OldUserID == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELDS", "pull_request_id or repository and old_user_id are required")
+		return
+	}
+
+	prID, ok := h.resolvePR(w, r, req.PullRequestID, req.Repository, req.PullRequestNumber)
+	if !ok {
 		return
 	}
 
 	coreReq := core.ReassignReviewer{
-		PRId:   req.PullRequestID,
+		PRId:   prID,
 		UserID: req.OldUserID,
 	}
 
+	r, ok = ifMatch(w, r)
+	if !ok {
+		return
+	}
+
 	result, newReviewer, err := h.service.Reassign(r.Context(), coreReq)
 	if err != nil {
 		switch {
@@ -283,6 +603,12 @@ func (h *Handler) ReassignPullRequest(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusConflict, "NOT_ASSIGNED", "reviewer is not assigned to this PR ")
 		case errors.Is(err, core.ErrNoReplacementCandidate):
 			writeError(w, http.StatusConflict, "NO_CANDIDATE", "no active replacement candidate in team")
+		case errors.Is(err, core.ErrForbidden):
+			writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+		case errors.Is(err, core.ErrPreconditionFailed):
+			writeError(w, http.StatusPreconditionFailed, "PRECONDITION_FAILED", err.Error())
+		case errors.Is(err, core.ErrPRConflict):
+			writeError(w, http.StatusConflict, "PR_CONFLICT", err.Error())
 		default:
 			// Используем единообразную функцию для ошибок
 			writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
@@ -295,6 +621,7 @@ func (h *Handler) ReassignPullRequest(w http.ResponseWriter, r *http.Request) {
 		ReplacedBy: newReviewer,
 	}
 
+	setETag(w, result)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
@@ -307,19 +634,38 @@ func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.service.GetReview(r.Context(), userID)
+	filter, err := parsePRFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PARAMETER", err.Error())
+		return
+	}
+	filter.ReviewerID = userID
+
+	// The endpoint returned every review before it took pages, so it still
+	// does unless the client asks for a page.
+	list := h.service.ListPRs
+	if !r.URL.Query().Has("limit") && !r.URL.Query().Has("cursor") {
+		list = h.listAllPRs
+	}
+
+	page, err := list(r.Context(), filter)
 	if err != nil {
 		if errors.Is(err, core.ErrUserNotFound) {
 			writeError(w, http.StatusNotFound, "USER_NOT_FOUND", err.Error())
 			return
 		}
+		if errors.Is(err, core.ErrForbidden) {
+			writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	response := GetUserReviewsResponse{
-		UserID:       result.UserID,
-		PullRequests: toPRShortResponses(result.PullRequest),
+		UserID:       userID,
+		PullRequests: toPRShortResponses(page.PullRequests),
+		NextCursor:   page.NextCursor,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -327,14 +673,41 @@ func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// listAllPRs follows the cursors to the last page.
+func (h *Handler) listAllPRs(ctx context.Context, filter core.PRFilter) (core.PRPage, error) {
+	filter.Limit = core.MaxPageSize
+
+	var all core.PRPage
+	for {
+		page, err := h.service.ListPRs(ctx, filter)
+		if err != nil {
+			return core.PRPage{}, err
+		}
+		all.PullRequests = append(all.PullRequests, page.PullRequests...)
+
+		if page.NextCursor == "" {
+			return all, nil
+		}
+		cursor, err := core.DecodePRCursor(page.NextCursor)
+		if err != nil {
+			return core.PRPage{}, err
+		}
+		filter.After = &cursor
+	}
+}
+
 func toPRShortResponses(prs []core.PullRequest) []PRShortResponse {
 	responses := make([]PRShortResponse, 0, len(prs))
 	for _, pr := range prs {
 		responses = append(responses, PRShortResponse{
-			PullRequestID:   pr.PullRequestID,
-			PullRequestName: pr.PullRequestName,
-			AuthorID:        pr.AuthorID,
-			Status:          string(pr.Status),
+			PullRequestID:     pr.PullRequestID,
+			Repository:        pr.Repository,
+			PullRequestNumber: pr.Number,
+			PullRequestName:   pr.PullRequestName,
+			AuthorID:          pr.AuthorID,
+			Status:            string(pr.Status),
+			CreatedAt:         pr.CreatedAt,
+			PRMetadataDTO:     toPRMetadataDTO(pr),
 		})
 
 	}
@@ -343,6 +716,10 @@ func toPRShortResponses(prs []core.PullRequest) []PRShortResponse {
 
 func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
 	stats, err := h.service.GetStats(r.Context())
+	if errors.Is(err, core.ErrForbidden) {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+		return
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "STATS_ERROR", "Failed to get statistics")
 		return
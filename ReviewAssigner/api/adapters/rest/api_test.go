@@ -0,0 +1,152 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"review-assigner/adapters/sqlite"
+	"review-assigner/config"
+	"review-assigner/core"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testAdminToken = "test-admin-token"
+
+type testAPI struct {
+	*httptest.Server
+	service *core.Service
+}
+
+// newTestAPI serves the REST API backed by an in-memory SQLite database, with
+// authentication enabled and one config admin token.
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	return newTestAPIWith(t, func(db core.DB) core.DB { return db })
+}
+
+// newTestAPIWith is newTestAPI with the storage of the service wrapped.
+func newTestAPIWith(t *testing.T, wrap func(core.DB) core.DB) *testAPI {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	db, err := sqlite.New(log, "sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	service, err := core.NewService(log, wrap(db), nil, core.EscalationPolicy{}, core.AssignmentPolicy{})
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+
+	auth, err := NewAuthenticator(service, log, config.AuthConfig{Enabled: true, AdminTokens: []string{testAdminToken}})
+	if err != nil {
+		t.Fatalf("create authenticator: %v", err)
+	}
+
+	server := httptest.NewServer(NewHandler(service, log, auth, NewIdempotency(db, log, time.Hour)))
+	t.Cleanup(server.Close)
+
+	return &testAPI{Server: server, service: service}
+}
+
+type testRequest struct {
+	method  string
+	path    string
+	token   string
+	headers map[string]string
+	body    string
+}
+
+type testResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (api *testAPI) do(t *testing.T, req testRequest) testResponse {
+	t.Helper()
+
+	var body io.Reader
+	if req.body != "" {
+		body = strings.NewReader(req.body)
+	}
+	httpReq, err := http.NewRequest(req.method, api.URL+req.path, body)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if req.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+req.token)
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range req.headers {
+		httpReq.Header.Set(name, value)
+	}
+
+	resp, err := api.Client().Do(httpReq)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.method, req.path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+
+	return testResponse{status: resp.StatusCode, header: resp.Header, body: data}
+}
+
+// must sends the request and fails the test unless it gets the status.
+func (api *testAPI) must(t *testing.T, status int, req testRequest) testResponse {
+	t.Helper()
+
+	resp := api.do(t, req)
+	if resp.status != status {
+		t.Fatalf("%s %s: got %d %s, want %d", req.method, req.path, resp.status, resp.body, status)
+	}
+	return resp
+}
+
+func (api *testAPI) token(t *testing.T, tenant string, token core.Token) string {
+	t.Helper()
+
+	_, secret, err := api.service.CreateToken(core.WithTenant(context.Background(), tenant), token)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	return secret
+}
+
+func decode[T any](t *testing.T, resp testResponse) T {
+	t.Helper()
+
+	var v T
+	if err := json.Unmarshal(resp.body, &v); err != nil {
+		t.Fatalf("decode %s: %v", resp.body, err)
+	}
+	return v
+}
+
+// errorCode returns the code of an error response.
+func errorCode(t *testing.T, resp testResponse) string {
+	t.Helper()
+
+	return decode[struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}](t, resp).Error.Code
+}
@@ -0,0 +1,250 @@
+package rest
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"review-assigner/config"
+	"review-assigner/core"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+type policy struct {
+	public bool
+	scope  core.Scope
+}
+
+func public() policy {
+	return policy{public: true}
+}
+
+func scoped(scope core.Scope) policy {
+	return policy{scope: scope}
+}
+
+// Policies only check token scopes, who may act on which team, user or pull
+// request is decided by core.Service from the principal in the context.
+var policies = map[string]policy{
+	"GET /openapi.json":                    public(),
+	"POST /team/add":                       scoped(core.ScopeTeamsWrite),
+	"GET /team/get":                        scoped(core.ScopeTeamsRead),
+	"POST /team/setLead":                   scoped(core.ScopeTeamsWrite),
+	"POST /users/setIsActive":              scoped(core.ScopeUsersWrite),
+	"POST /users/setOutOfOffice":           scoped(core.ScopeUsersWrite),
+	"GET /users/getReview":                 scoped(core.ScopeUsersRead),
+	"GET /pullRequest/get":                 scoped(core.ScopePRsRead),
+	"GET /pullRequest/list":                scoped(core.ScopePRsRead),
+	"POST /pullRequest/create":             scoped(core.ScopePRsWrite),
+	"POST /pullRequest/approve":            scoped(core.ScopePRsWrite),
+	"POST /pullRequest/merge":              scoped(core.ScopePRsWrite),
+	"POST /pullRequest/reassign":           scoped(core.ScopePRsWrite),
+	"POST /repository/add":                 scoped(core.ScopeTeamsWrite),
+	"GET /repository/get":                  scoped(core.ScopeTeamsRead),
+	"POST /repository/linkTeam":            scoped(core.ScopeTeamsWrite),
+	"GET /stats":                           scoped(core.ScopeStatsRead),
+	"POST /admin/tokens":                   scoped(core.ScopeAdmin),
+	"GET /admin/tokens":                    scoped(core.ScopeAdmin),
+	"DELETE /admin/tokens/{id}":            scoped(core.ScopeAdmin),
+	"GET /admin/export":                    scoped(core.ScopeAdmin),
+	"POST /admin/import":                   scoped(core.ScopeAdmin),
+	"POST /admin/users/import":             scoped(core.ScopeAdmin),
+	"POST /v2/teams":                       scoped(core.ScopeTeamsWrite),
+	"GET /v2/teams/{name}":                 scoped(core.ScopeTeamsRead),
+	"PATCH /v2/teams/{name}":               scoped(core.ScopeTeamsWrite),
+	"GET /v2/users/{id}":                   scoped(core.ScopeUsersRead),
+	"PATCH /v2/users/{id}":                 scoped(core.ScopeUsersWrite),
+	"GET /v2/users/{id}/reviews":           scoped(core.ScopeUsersRead),
+	"POST /v2/pull-requests":               scoped(core.ScopePRsWrite),
+	"GET /v2/pull-requests/{id}":           scoped(core.ScopePRsRead),
+	"PATCH /v2/pull-requests/{id}":         scoped(core.ScopePRsWrite),
+	"GET /v2/pull-requests/{id}/reviewers": scoped(core.ScopePRsRead),
+	"POST /v2/pull-requests/{id}/reviewers/{user_id}/reassign": scoped(core.ScopePRsWrite),
+	"PUT /v2/pull-requests/{id}/reviewers/{user_id}/approval":  scoped(core.ScopePRsWrite),
+	"POST /v2/repositories":                         scoped(core.ScopeTeamsWrite),
+	"GET /v2/repositories/{name}":                   scoped(core.ScopeTeamsRead),
+	"PUT /v2/repositories/{name}/teams/{team_name}": scoped(core.ScopeTeamsWrite),
+	"GET /v2/stats":                                 scoped(core.ScopeStatsRead),
+
+	// The same pull request routes, addressed by repository and number.
+	"GET /v2/repositories/{repository}/pull-requests/{number}":                               scoped(core.ScopePRsRead),
+	"PATCH /v2/repositories/{repository}/pull-requests/{number}":                             scoped(core.ScopePRsWrite),
+	"GET /v2/repositories/{repository}/pull-requests/{number}/reviewers":                     scoped(core.ScopePRsRead),
+	"POST /v2/repositories/{repository}/pull-requests/{number}/reviewers/{user_id}/reassign": scoped(core.ScopePRsWrite),
+	"PUT /v2/repositories/{repository}/pull-requests/{number}/reviewers/{user_id}/approval":  scoped(core.ScopePRsWrite),
+}
+
+var errNoCredentials = errors.New("authentication is enabled but neither admin tokens nor a jwks are configured: " +
+	"set AUTH_ADMIN_TOKENS, configure auth.jwt or disable authentication with AUTH_ENABLED=false")
+
+type Authenticator struct {
+	service     *core.Service
+	log         *slog.Logger
+	enabled     bool
+	adminTokens []string
+	jwt         *jwtVerifier
+}
+
+func NewAuthenticator(service *core.Service, log *slog.Logger, cfg config.AuthConfig) (*Authenticator, error) {
+	auth := &Authenticator{service: service, log: log, enabled: cfg.Enabled}
+	for _, token := range cfg.AdminTokens {
+		if token != "" {
+			auth.adminTokens = append(auth.adminTokens, token)
+		}
+	}
+
+	if cfg.JWT.JWKSFile != "" || cfg.JWT.JWKSURL != "" {
+		verifier, err := newJWTVerifier(log, cfg.JWT)
+		if err != nil {
+			return nil, err
+		}
+		auth.jwt = verifier
+	}
+
+	// Without either nobody could call the API or issue the first token.
+	if cfg.Enabled && len(auth.adminTokens) == 0 && auth.jwt == nil {
+		return nil, errNoCredentials
+	}
+
+	return auth, nil
+}
+
+const tenantHeader = "X-Tenant"
+
+// requestTenant resolves the organisation a request acts in from the tenant
+// it asks for in X-Tenant. Stored tokens and JWTs are bound to a tenant and
+// may only repeat it. The header selects the tenant only for config admin
+// tokens, which carry none, and when authentication is disabled.
+func requestTenant(requested string, token core.Token) (string, error) {
+	if requested != "" && !core.ValidTenant(requested) {
+		return "", errInvalidTenant
+	}
+
+	switch {
+	case token.Tenant == "" && requested == "":
+		return core.DefaultTenant, nil
+	case token.Tenant == "":
+		return requested, nil
+	case requested == "" || requested == token.Tenant:
+		return token.Tenant, nil
+	default:
+		return "", core.ErrForbidden
+	}
+}
+
+var errInvalidTenant = errors.New("X-Tenant must be 1-100 letters, digits, '.', '_' or '-'")
+
+func (a *Authenticator) token(ctx context.Context, authorization string) (core.Token, error) {
+	scheme, secret, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || secret == "" {
+		return core.Token{}, core.ErrTokenNotFound
+	}
+
+	for _, admin := range a.adminTokens {
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(admin)) == 1 {
+			return core.Token{Name: "config", Scopes: []core.Scope{core.ScopeAdmin}}, nil
+		}
+	}
+
+	if a.jwt != nil && looksLikeJWT(secret) {
+		return a.jwt.verify(ctx, secret)
+	}
+
+	return a.service.Authenticate(ctx, secret)
+}
+
+// Authenticate checks the Authorization value of a call that does not come
+// over HTTP, such as a gRPC call, and puts its principal and the tenant it
+// acts in into the context. The requested tenant follows the X-Tenant rules.
+func (a *Authenticator) Authenticate(ctx context.Context, authorization, tenant string, scope core.Scope) (context.Context, error) {
+	var token core.Token
+	if a.enabled {
+		var err error
+		token, err = a.token(ctx, authorization)
+		if err != nil {
+			return nil, err
+		}
+
+		if !token.HasScope(scope) {
+			return nil, fmt.Errorf("%w: token lacks the %s scope", core.ErrForbidden, scope)
+		}
+		ctx = core.WithPrincipal(ctx, token.Principal())
+	}
+
+	tenant, err := requestTenant(tenant, token)
+	if errors.Is(err, core.ErrForbidden) {
+		return nil, fmt.Errorf("%w: token is bound to another tenant", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return core.WithTenant(ctx, tenant), nil
+}
+
+func (a *Authenticator) authorize(w http.ResponseWriter, r *http.Request, p policy) (*http.Request, bool) {
+	if !a.enabled || p.public {
+		return withTenant(w, r, core.Token{})
+	}
+
+	token, err := a.token(r.Context(), r.Header.Get("Authorization"))
+	if err != nil {
+		if !errors.Is(err, core.ErrTokenNotFound) {
+			a.log.Error("failed to authenticate request", "error", err)
+			writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+			return nil, false
+		}
+		w.Header().Set("WWW-Authenticate", `Bearer realm="review-assigner"`)
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "missing or invalid bearer token")
+		return nil, false
+	}
+
+	if !token.HasScope(p.scope) {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", fmt.Sprintf("token lacks the %s scope", p.scope))
+		return nil, false
+	}
+
+	return withTenant(w, r.WithContext(core.WithPrincipal(r.Context(), token.Principal())), token)
+}
+
+func withTenant(w http.ResponseWriter, r *http.Request, token core.Token) (*http.Request, bool) {
+	tenant, err := requestTenant(r.Header.Get(tenantHeader), token)
+	switch {
+	case errors.Is(err, errInvalidTenant):
+		writeError(w, http.StatusBadRequest, "INVALID_TENANT", err.Error())
+		return nil, false
+	case err != nil:
+		writeError(w, http.StatusForbidden, "FORBIDDEN", "token is bound to another tenant")
+		return nil, false
+	}
+
+	return r.WithContext(core.WithTenant(r.Context(), tenant)), true
+}
+
+func (h *Handler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p policy
+		if route := mux.CurrentRoute(r); route != nil {
+			path, _ := route.GetPathTemplate()
+			p = policies[r.Method+" "+path]
+		}
+
+		if r, ok := h.auth.authorize(w, r, p); ok {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func (a *Authenticator) RequireScope(scope core.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r, ok := a.authorize(w, r, scoped(scope)); ok {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
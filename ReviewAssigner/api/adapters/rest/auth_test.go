@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"io"
+	"log/slog"
+	"review-assigner/config"
+	"testing"
+)
+
+func TestPoliciesCoverRoutes(t *testing.T) {
+	for _, key := range routeKeys(t, (&Handler{}).routes()) {
+		if _, ok := policies[key]; !ok {
+			t.Errorf("route without auth policy %s", key)
+		}
+	}
+}
+
+func TestAuthenticatorRequiresCredentials(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name    string
+		cfg     config.AuthConfig
+		wantErr bool
+	}{
+		{name: "enabled without credentials", cfg: config.AuthConfig{Enabled: true, AdminTokens: []string{""}}, wantErr: true},
+		{name: "enabled with an admin token", cfg: config.AuthConfig{Enabled: true, AdminTokens: []string{testAdminToken}}},
+		{name: "disabled", cfg: config.AuthConfig{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAuthenticator(nil, log, tt.cfg); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
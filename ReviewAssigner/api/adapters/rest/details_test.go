@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestPullRequestDetails(t *testing.T) {
+	api := newTestAPI(t)
+	addBackendPR(t, api)
+
+	api.must(t, http.StatusOK, testRequest{method: "POST", path: "/pullRequest/approve", token: testAdminToken,
+		body: `{"pull_request_id":"pr-1","user_id":"u2"}`})
+	api.must(t, http.StatusOK, testRequest{method: "POST", path: "/pullRequest/reassign", token: testAdminToken,
+		body: `{"pull_request_id":"pr-1","old_user_id":"u3"}`})
+	api.must(t, http.StatusOK, testRequest{method: "POST", path: "/pullRequest/merge", token: testAdminToken,
+		body: `{"pull_request_id":"pr-1"}`})
+
+	resp := api.must(t, http.StatusOK, testRequest{method: "GET", path: "/pullRequest/get?pull_request_id=pr-1", token: testAdminToken})
+	if resp.header.Get("ETag") == "" {
+		t.Error("details come without an ETag")
+	}
+
+	details := decode[GetPRResponse](t, resp).PR
+	if details.Status != "MERGED" || details.AuthorID != "u1" || details.PullRequestName != "Add search" {
+		t.Errorf("got pull request %+v", details.PRResponse)
+	}
+
+	if len(details.Reviewers) != 2 {
+		t.Fatalf("got reviewers %+v, want two", details.Reviewers)
+	}
+	approved, pending := details.Reviewers[0], details.Reviewers[1]
+	if approved.UserID != "u2" || approved.ReviewState != "APPROVED" || approved.ApprovedAt == nil || approved.Username != "Bob" {
+		t.Errorf("got approved reviewer %+v", approved)
+	}
+	if pending.UserID == "u3" || pending.ReviewState != "PENDING" || pending.ApprovedAt != nil || pending.TeamName != "backend" {
+		t.Errorf("got replacement reviewer %+v", pending)
+	}
+
+	var events []string
+	for _, entry := range details.History {
+		events = append(events, entry.Event)
+	}
+	if want := []string{"CREATED", "APPROVED", "REASSIGNED", "MERGED"}; !slices.Equal(events, want) {
+		t.Errorf("got history %v, want %v", events, want)
+	}
+}
+
+func TestPullRequestDetailsNotFound(t *testing.T) {
+	api := newTestAPI(t)
+	addBackendPR(t, api)
+
+	resp := api.must(t, http.StatusNotFound, testRequest{method: "GET", path: "/pullRequest/get?pull_request_id=pr-9", token: testAdminToken})
+	if code := errorCode(t, resp); code != "PR_NOT_FOUND" {
+		t.Errorf("got error code %s, want PR_NOT_FOUND", code)
+	}
+}
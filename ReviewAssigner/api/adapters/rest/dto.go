@@ -1,7 +1,10 @@
 package rest
 
+import "time"
+
 type AddTeamRequest struct {
 	TeamName string          `json:"team_name"`
+	LeadID   string          `json:"lead_id"`
 	Members  []TeamMemberDTO `json:"members"`
 }
 
@@ -17,9 +20,19 @@ type AddTeamResponse struct {
 
 type TeamResponse struct {
 	TeamName string          `json:"team_name"`
+	LeadID   string          `json:"lead_id,omitempty"`
 	Members  []TeamMemberDTO `json:"members"`
 }
 
+type SetTeamLeadRequest struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+}
+
+type SetTeamLeadResponse struct {
+	Team TeamResponse `json:"team"`
+}
+
 type GetTeamRequest struct {
 	TeamName string `json:"team_name"`
 }
@@ -36,17 +49,39 @@ type SetUserActiveResponse struct {
 	User UserResponse `json:"user"`
 }
 
+type SetOutOfOfficeRequest struct {
+	UserID string     `json:"user_id"`
+	Until  *time.Time `json:"until"`
+}
+
+type SetOutOfOfficeResponse struct {
+	User UserResponse `json:"user"`
+}
+
 type UserResponse struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID           string     `json:"user_id"`
+	Username         string     `json:"username"`
+	TeamName         string     `json:"team_name"`
+	IsActive         bool       `json:"is_active"`
+	OutOfOfficeUntil *time.Time `json:"out_of_office_until,omitempty"`
 }
 
 type CreatePRRequest struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	PullRequestID     string `json:"pull_request_id,omitempty"`
+	Repository        string `json:"repository,omitempty"`
+	PullRequestNumber int64  `json:"pull_request_number,omitempty"`
+	PullRequestName   string `json:"pull_request_name"`
+	AuthorID          string `json:"author_id"`
+	PRMetadataDTO
+}
+
+// PRMetadataDTO carries the optional labels, size and priority of a pull request.
+type PRMetadataDTO struct {
+	Labels       []string `json:"labels,omitempty"`
+	LinesAdded   int      `json:"lines_added,omitempty"`
+	LinesRemoved int      `json:"lines_removed,omitempty"`
+	FilesChanged int      `json:"files_changed,omitempty"`
+	Priority     string   `json:"priority,omitempty"`
 }
 
 type CreatePRResponse struct {
@@ -55,14 +90,32 @@ type CreatePRResponse struct {
 
 type PRResponse struct {
 	PullRequestID     string   `json:"pull_request_id"`
+	Repository        string   `json:"repository,omitempty"`
+	PullRequestNumber int64    `json:"pull_request_number,omitempty"`
 	PullRequestName   string   `json:"pull_request_name"`
 	AuthorID          string   `json:"author_id"`
 	Status            string   `json:"status"`
 	AssignedReviewers []string `json:"assigned_reviewers"`
+	CreatedAt         *string  `json:"created_at,omitempty"`
+	MergedAt          *string  `json:"merged_at,omitempty"`
+	PRMetadataDTO
+}
+
+type ApprovePRRequest struct {
+	PullRequestID     string `json:"pull_request_id,omitempty"`
+	Repository        string `json:"repository,omitempty"`
+	PullRequestNumber int64  `json:"pull_request_number,omitempty"`
+	UserID            string `json:"user_id"`
+}
+
+type ApprovePRResponse struct {
+	PR PRResponse `json:"pr"`
 }
 
 type MergePRRequest struct {
-	PullRequestID string `json:"pull_request_id"`
+	PullRequestID     string `json:"pull_request_id,omitempty"`
+	Repository        string `json:"repository,omitempty"`
+	PullRequestNumber int64  `json:"pull_request_number,omitempty"`
 }
 
 type MergePRResponse struct {
@@ -70,8 +123,10 @@ type MergePRResponse struct {
 }
 
 type ReassignReviewer struct {
-	PullRequestID string `json:"pull_request_id"`
-	OldUserID     string `json:"old_user_id"`
+	PullRequestID     string `json:"pull_request_id,omitempty"`
+	Repository        string `json:"repository,omitempty"`
+	PullRequestNumber int64  `json:"pull_request_number,omitempty"`
+	OldUserID         string `json:"old_user_id"`
 }
 
 type ReassignPRResponse struct {
@@ -86,11 +141,172 @@ type GetUserReviewsRequest struct {
 type GetUserReviewsResponse struct {
 	UserID       string            `json:"user_id"`
 	PullRequests []PRShortResponse `json:"pull_requests"`
+	NextCursor   string            `json:"next_cursor,omitempty"`
+}
+
+type StatsResponse struct {
+	Stats StatsDTO `json:"stats"`
+}
+
+type StatsDTO struct {
+	UserAssignments            map[string]int `json:"user_assignments"`
+	PRReviewerCounts           map[string]int `json:"pr_reviewer_counts"`
+	TotalAssignments           int            `json:"total_assignments"`
+	UniqueUsersWithAssignments int            `json:"unique_users_with_assignments"`
+	UniquePRsWithReviewers     int            `json:"unique_prs_with_reviewers"`
 }
 
 type PRShortResponse struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
-	Status          string `json:"status"`
+	PullRequestID     string  `json:"pull_request_id"`
+	Repository        string  `json:"repository,omitempty"`
+	PullRequestNumber int64   `json:"pull_request_number,omitempty"`
+	PullRequestName   string  `json:"pull_request_name"`
+	AuthorID          string  `json:"author_id"`
+	Status            string  `json:"status"`
+	CreatedAt         *string `json:"created_at,omitempty"`
+	PRMetadataDTO
+}
+
+type RepositoryDTO struct {
+	Name      string   `json:"name"`
+	TeamNames []string `json:"team_names"`
+}
+
+type RepositoryResponse struct {
+	Repository RepositoryDTO `json:"repository"`
+}
+
+type LinkRepositoryRequest struct {
+	Repository string `json:"repository"`
+	TeamName   string `json:"team_name"`
+}
+
+// PatchTeamRequest sets the lead, or removes it when lead_id is null.
+type PatchTeamRequest struct {
+	LeadID *string `json:"lead_id"`
+}
+
+type PatchUserRequest struct {
+	IsActive         *bool      `json:"is_active"`
+	OutOfOfficeUntil *time.Time `json:"out_of_office_until"`
+}
+
+type PatchPRRequest struct {
+	Status string `json:"status"`
+}
+
+type CreateTokenRequest struct {
+	Name   string   `json:"name"`
+	UserID string   `json:"user_id,omitempty"`
+	Scopes []string `json:"scopes"`
+}
+
+type TokenResponse struct {
+	ID         int64      `json:"id"`
+	Tenant     string     `json:"tenant"`
+	Name       string     `json:"name"`
+	UserID     string     `json:"user_id,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+type CreateTokenResponse struct {
+	Token  TokenResponse `json:"token"`
+	Secret string        `json:"secret"`
+}
+
+type ListTokensResponse struct {
+	Tokens []TokenResponse `json:"tokens"`
+}
+
+type ListPRsResponse struct {
+	PullRequests []PRResponse `json:"pull_requests"`
+	NextCursor   string       `json:"next_cursor,omitempty"`
+}
+
+type GetPRResponse struct {
+	PR PRDetailsDTO `json:"pr"`
+}
+
+type PRDetailsDTO struct {
+	PRResponse
+	Reviewers []ReviewerDTO     `json:"reviewers"`
+	History   []HistoryEntryDTO `json:"history"`
+}
+
+type ReviewerDTO struct {
+	UserID      string     `json:"user_id"`
+	Username    string     `json:"username"`
+	TeamName    string     `json:"team_name"`
+	IsActive    bool       `json:"is_active"`
+	ReviewState string     `json:"review_state"`
+	ApprovedAt  *time.Time `json:"approved_at,omitempty"`
+}
+
+type HistoryEntryDTO struct {
+	Event     string    `json:"event"`
+	ActorID   string    `json:"actor_id,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SnapshotDTO struct {
+	Version      int               `json:"version"`
+	ExportedAt   time.Time         `json:"exported_at"`
+	Teams        []SnapshotTeamDTO `json:"teams"`
+	Users        []UserResponse    `json:"users"`
+	Repositories []RepositoryDTO   `json:"repositories,omitempty"`
+	PullRequests []SnapshotPRDTO   `json:"pull_requests"`
+}
+
+type SnapshotTeamDTO struct {
+	TeamName string `json:"team_name"`
+	LeadID   string `json:"lead_id,omitempty"`
+}
+
+type SnapshotPRDTO struct {
+	PullRequestID     string                `json:"pull_request_id"`
+	Repository        string                `json:"repository,omitempty"`
+	PullRequestNumber int64                 `json:"pull_request_number,omitempty"`
+	PullRequestName   string                `json:"pull_request_name"`
+	AuthorID          string                `json:"author_id"`
+	Status            string                `json:"status"`
+	CreatedAt         *string               `json:"created_at,omitempty"`
+	MergedAt          *string               `json:"merged_at,omitempty"`
+	Version           int64                 `json:"version"`
+	Reviewers         []SnapshotReviewerDTO `json:"reviewers"`
+	History           []HistoryEntryDTO     `json:"history"`
+	PRMetadataDTO
+}
+
+type SnapshotReviewerDTO struct {
+	UserID     string     `json:"user_id"`
+	ApprovedAt *time.Time `json:"approved_at,omitempty"`
+}
+
+type ImportCountDTO struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+type ImportResponse struct {
+	DryRun       bool           `json:"dry_run"`
+	Teams        ImportCountDTO `json:"teams"`
+	Users        ImportCountDTO `json:"users"`
+	Repositories ImportCountDTO `json:"repositories"`
+	PullRequests ImportCountDTO `json:"pull_requests"`
+}
+
+type UserImportResponse struct {
+	Rows         int                  `json:"rows"`
+	Users        ImportCountDTO       `json:"users"`
+	TeamsCreated int                  `json:"teams_created"`
+	Errors       []UserImportErrorDTO `json:"errors"`
+}
+
+type UserImportErrorDTO struct {
+	Line    int    `json:"line"`
+	UserID  string `json:"user_id,omitempty"`
+	Message string `json:"message"`
 }
@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"net/http"
+	"review-assigner/core"
+	"strconv"
+	"strings"
+)
+
+func setETag(w http.ResponseWriter, pr core.PullRequest) {
+	if pr.Version > 0 {
+		w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(pr.Version, 10)))
+	}
+}
+
+// ifMatch carries the If-Match header into the service. Only a single strong
+// ETag previously returned by the API can match, anything else fails with 412.
+func ifMatch(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return r, true
+	}
+
+	tag, err := strconv.Unquote(value)
+	if err == nil {
+		var version int64
+		version, err = strconv.ParseInt(tag, 10, 64)
+		if err == nil {
+			return r.WithContext(core.WithExpectedVersion(r.Context(), version)), true
+		}
+	}
+
+	writeError(w, http.StatusPreconditionFailed, "PRECONDITION_FAILED", core.ErrPreconditionFailed.Error())
+	return nil, false
+}
@@ -0,0 +1,109 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"review-assigner/core"
+	"sync"
+	"testing"
+)
+
+// staleReads returns every PR one version behind, as if another request
+// updated it between the read and the write.
+type staleReads struct {
+	core.DB
+}
+
+func (db staleReads) WithTx(ctx context.Context, fn func(core.DB) error) error {
+	return db.DB.WithTx(ctx, func(tx core.DB) error {
+		return fn(staleReads{tx})
+	})
+}
+
+func (db staleReads) GetPRDetailsWithReviewers(ctx context.Context, prId string) (core.PullRequest, error) {
+	pr, err := db.DB.GetPRDetailsWithReviewers(ctx, prId)
+	pr.Version--
+	return pr, err
+}
+
+func addBackendPR(t *testing.T, api *testAPI) {
+	t.Helper()
+
+	api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/team/add", token: testAdminToken,
+		body: `{"team_name":"backend","members":[` +
+			`{"user_id":"u1","username":"Alice","is_active":true},` +
+			`{"user_id":"u2","username":"Bob","is_active":true},` +
+			`{"user_id":"u3","username":"Carol","is_active":true},` +
+			`{"user_id":"u4","username":"Dave","is_active":true},` +
+			`{"user_id":"u5","username":"Eve","is_active":true}]}`})
+	api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/pullRequest/create", token: testAdminToken,
+		body: `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`})
+}
+
+func TestStaleIfMatch(t *testing.T) {
+	api := newTestAPI(t)
+	addBackendPR(t, api)
+
+	resp := api.must(t, http.StatusOK, testRequest{method: "GET", path: "/v2/pull-requests/pr-1", token: testAdminToken})
+	etag, reviewer := resp.header.Get("ETag"), decode[PRResponse](t, resp).AssignedReviewers[0]
+	api.must(t, http.StatusOK, testRequest{method: "POST", path: "/pullRequest/approve", token: testAdminToken,
+		body: `{"pull_request_id":"pr-1","user_id":"` + reviewer + `"}`})
+
+	api.must(t, http.StatusPreconditionFailed, testRequest{method: "PATCH", path: "/v2/pull-requests/pr-1", token: testAdminToken,
+		headers: map[string]string{"If-Match": etag}, body: `{"status":"MERGED"}`})
+
+	etag = api.must(t, http.StatusOK, testRequest{method: "GET", path: "/v2/pull-requests/pr-1", token: testAdminToken}).
+		header.Get("ETag")
+	api.must(t, http.StatusOK, testRequest{method: "PATCH", path: "/v2/pull-requests/pr-1", token: testAdminToken,
+		headers: map[string]string{"If-Match": etag}, body: `{"status":"MERGED"}`})
+}
+
+func TestConcurrentVersionBump(t *testing.T) {
+	api := newTestAPIWith(t, func(db core.DB) core.DB { return staleReads{db} })
+	addBackendPR(t, api)
+
+	resp := api.must(t, http.StatusConflict, testRequest{method: "POST", path: "/pullRequest/merge", token: testAdminToken,
+		body: `{"pull_request_id":"pr-1"}`})
+	if code := errorCode(t, resp); code != "PR_CONFLICT" {
+		t.Errorf("got error code %s, want PR_CONFLICT", code)
+	}
+}
+
+func TestConcurrentReassign(t *testing.T) {
+	api := newTestAPI(t)
+	addBackendPR(t, api)
+
+	reviewers := decode[PRResponse](t, api.must(t, http.StatusOK, testRequest{
+		method: "GET", path: "/v2/pull-requests/pr-1", token: testAdminToken})).AssignedReviewers
+
+	statuses := make([]int, len(reviewers))
+	var wg sync.WaitGroup
+	for i, reviewer := range reviewers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = api.do(t, testRequest{method: "POST", path: "/pullRequest/reassign", token: testAdminToken,
+				body: `{"pull_request_id":"pr-1","old_user_id":"` + reviewer + `"}`}).status
+		}()
+	}
+	wg.Wait()
+
+	for i, status := range statuses {
+		if status != http.StatusOK && status != http.StatusConflict {
+			t.Errorf("reassign %s: got status %d, want 200 or 409", reviewers[i], status)
+		}
+	}
+
+	pr := decode[PRResponse](t, api.must(t, http.StatusOK, testRequest{
+		method: "GET", path: "/v2/pull-requests/pr-1", token: testAdminToken}))
+	seen := map[string]bool{pr.AuthorID: true}
+	for _, reviewer := range pr.AssignedReviewers {
+		if seen[reviewer] {
+			t.Errorf("reviewer %s is assigned twice or is the author: %v", reviewer, pr.AssignedReviewers)
+		}
+		seen[reviewer] = true
+	}
+	if len(pr.AssignedReviewers) != len(reviewers) {
+		t.Errorf("got reviewers %v, want %d", pr.AssignedReviewers, len(reviewers))
+	}
+}
@@ -0,0 +1,210 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"log/slog"
+	"net/http"
+	"review-assigner/core"
+	"time"
+)
+
+const (
+	idempotencyKeyHeader   = "Idempotency-Key"
+	idempotencyMaxKeyLen   = 255
+	idempotencyReplayedHdr = "Idempotent-Replayed"
+)
+
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+type Idempotency struct {
+	store core.IdempotencyStore
+	log   *slog.Logger
+	ttl   time.Duration
+}
+
+func NewIdempotency(store core.IdempotencyStore, log *slog.Logger, ttl time.Duration) *Idempotency {
+	return &Idempotency{
+		store: store,
+		log:   log,
+		ttl:   ttl,
+	}
+}
+
+func (i *Idempotency) Cleanup(ctx context.Context) error {
+	deleted, err := i.store.DeleteExpiredIdempotencyKeys(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	i.log.Debug("expired idempotency keys deleted", "count", deleted)
+	return nil
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// hashingBody feeds the request body into the request hash as the handler
+// reads it, so that large imports are hashed without being held in memory.
+type hashingBody struct {
+	io.ReadCloser
+	hash hash.Hash
+	eof  bool
+}
+
+// Read keeps returning io.EOF once the body is consumed, even if the
+// request validation has closed it by then.
+func (b *hashingBody) Read(p []byte) (int, error) {
+	if b.eof {
+		return 0, io.EOF
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	b.eof = err == io.EOF
+	return n, err
+}
+
+func requestHasher(r *http.Request) hash.Hash {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+	return hash
+}
+
+// Keys are scoped per caller, and outside the default tenant per tenant too,
+// so that equal user ids of different organisations never share a key.
+func idempotencyScope(ctx context.Context) string {
+	var scope string
+	if tenant := core.TenantFrom(ctx); tenant != core.DefaultTenant {
+		scope = tenant + "/"
+	}
+
+	principal, ok := core.PrincipalFrom(ctx)
+	if !ok {
+		return scope
+	}
+	return scope + string(principal.Role) + ":" + principal.UserID
+}
+
+func (i *Idempotency) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if i == nil || r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(key) > idempotencyMaxKeyLen {
+			writeError(w, http.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY", "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		// The body is only known once the handler has streamed it, so the key
+		// is reserved with a hash of the route alone and the hash of the whole
+		// request is stored with the response.
+		body := &hashingBody{ReadCloser: r.Body, hash: requestHasher(r)}
+		r.Body = body
+
+		record, reserved, err := i.store.ReserveIdempotencyKey(r.Context(), core.IdempotencyRecord{
+			Scope:       idempotencyScope(r.Context()),
+			Key:         key,
+			RequestHash: hex.EncodeToString(requestHasher(r).Sum(nil)),
+			ExpiresAt:   time.Now().Add(i.ttl),
+		})
+		if err != nil {
+			i.log.Error("failed to reserve idempotency key", "error", err)
+			writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+			return
+		}
+
+		if !reserved {
+			i.replay(w, r, record)
+			return
+		}
+
+		// Keep the body readable after the response has started, for the
+		// rest of it still has to be hashed.
+		http.NewResponseController(w).EnableFullDuplex()
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		// The request outlives a client that gave up waiting, which is exactly
+		// the case retries are for, so the outcome must still be stored.
+		ctx := context.WithoutCancel(r.Context())
+
+		_, err = io.Copy(io.Discard, body)
+		if err != nil {
+			i.log.Warn("failed to hash request body", "key", key, "error", err)
+		}
+
+		if err != nil || recorder.status == 0 || recorder.status >= http.StatusInternalServerError {
+			if err := i.store.ReleaseIdempotencyKey(ctx, record.Scope, record.Key); err != nil {
+				i.log.Warn("failed to release idempotency key", "key", key, "error", err)
+			}
+			return
+		}
+
+		record.RequestHash = hex.EncodeToString(body.hash.Sum(nil))
+		record.Status = recorder.status
+		record.Body = recorder.body.Bytes()
+		record.Headers = make(map[string]string)
+		for _, name := range replayedHeaders {
+			if value := w.Header().Get(name); value != "" {
+				record.Headers[name] = value
+			}
+		}
+
+		if err := i.store.CompleteIdempotencyKey(ctx, record); err != nil {
+			i.log.Warn("failed to store idempotent response", "key", key, "error", err)
+		}
+	})
+}
+
+func (i *Idempotency) replay(w http.ResponseWriter, r *http.Request, record core.IdempotencyRecord) {
+	// Until the first request completes only its route is known.
+	hash := requestHasher(r)
+	if record.Completed() {
+		if _, err := io.Copy(hash, r.Body); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_BODY", "Failed to read request body")
+			return
+		}
+	}
+
+	switch {
+	case record.RequestHash != hex.EncodeToString(hash.Sum(nil)):
+		writeError(w, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED",
+			"Idempotency-Key was already used for a different request")
+	case !record.Completed():
+		writeError(w, http.StatusConflict, "IDEMPOTENCY_IN_PROGRESS",
+			"a request with this Idempotency-Key is still being processed")
+	default:
+		for name, value := range record.Headers {
+			w.Header().Set(name, value)
+		}
+		w.Header().Set(idempotencyReplayedHdr, "true")
+		w.WriteHeader(record.Status)
+		w.Write(record.Body)
+	}
+}
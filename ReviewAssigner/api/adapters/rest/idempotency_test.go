@@ -0,0 +1,129 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"review-assigner/core"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const backendTeam = `{"team_name":"backend","members":[` +
+	`{"user_id":"u1","username":"Alice","is_active":true},` +
+	`{"user_id":"u2","username":"Bob","is_active":true},` +
+	`{"user_id":"u3","username":"Carol","is_active":true}]}`
+
+func idempotent(key string, req testRequest) testRequest {
+	req.token = testAdminToken
+	req.headers = map[string]string{idempotencyKeyHeader: key}
+	return req
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	api := newTestAPI(t)
+	api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/team/add", token: testAdminToken, body: backendTeam})
+
+	create := idempotent("create-pr-1", testRequest{method: "POST", path: "/v2/pull-requests",
+		body: `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1"}`})
+
+	first := api.must(t, http.StatusCreated, create)
+	replayed := api.must(t, http.StatusCreated, create)
+
+	if string(replayed.body) != string(first.body) {
+		t.Errorf("replayed body %s, want %s", replayed.body, first.body)
+	}
+	if replayed.header.Get(idempotencyReplayedHdr) != "true" {
+		t.Error("replayed response is not marked as replayed")
+	}
+	for _, name := range []string{"Content-Type", "Location", "ETag"} {
+		if first.header.Get(name) == "" || replayed.header.Get(name) != first.header.Get(name) {
+			t.Errorf("%s: replayed %q, want %q", name, replayed.header.Get(name), first.header.Get(name))
+		}
+	}
+
+	reused := create
+	reused.body = `{"pull_request_id":"pr-2","pull_request_name":"Add search","author_id":"u1"}`
+	resp := api.must(t, http.StatusUnprocessableEntity, reused)
+	if !strings.Contains(string(resp.body), "IDEMPOTENCY_KEY_REUSED") {
+		t.Errorf("unexpected response %s", resp.body)
+	}
+
+	otherRoute := create
+	otherRoute.path = "/pullRequest/create"
+	api.must(t, http.StatusUnprocessableEntity, otherRoute)
+}
+
+func TestIdempotentStreamingImport(t *testing.T) {
+	api := newTestAPI(t)
+
+	var csv strings.Builder
+	csv.WriteString("user_id,username,team_name,is_active\n")
+	for i := range 500 {
+		fmt.Fprintf(&csv, "u%d,User %d,team-%d,true\n", i, i, i%10)
+	}
+
+	importUsers := idempotent("import-1", testRequest{method: "POST", path: "/admin/users/import", body: csv.String()})
+	importUsers.headers["Content-Type"] = "text/csv"
+
+	first := api.must(t, http.StatusOK, importUsers)
+	replayed := api.must(t, http.StatusOK, importUsers)
+	if replayed.header.Get(idempotencyReplayedHdr) != "true" || string(replayed.body) != string(first.body) {
+		t.Errorf("import was not replayed: %s", replayed.body)
+	}
+
+	changed := importUsers
+	changed.body = strings.Replace(csv.String(), "User 499", "User 500", 1)
+	api.must(t, http.StatusUnprocessableEntity, changed)
+}
+
+// failingTeams fails adding teams while fail is set, as if the database went
+// away in the middle of the request.
+type failingTeams struct {
+	core.DB
+	fail *atomic.Bool
+}
+
+func (db failingTeams) AddTeam(ctx context.Context, team core.Team) error {
+	if db.fail.Load() {
+		return errors.New("connection reset")
+	}
+	return db.DB.AddTeam(ctx, team)
+}
+
+func TestIdempotentServerErrorReleasesKey(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	api := newTestAPIWith(t, func(db core.DB) core.DB { return failingTeams{DB: db, fail: &fail} })
+
+	create := idempotent("create-backend", testRequest{method: "POST", path: "/team/add", body: backendTeam})
+
+	api.must(t, http.StatusInternalServerError, create)
+
+	fail.Store(false)
+	resp := api.must(t, http.StatusCreated, create)
+	if resp.header.Get(idempotencyReplayedHdr) != "" {
+		t.Error("retry after a server error was replayed instead of executed")
+	}
+}
+
+func TestIdempotentClientErrorReplay(t *testing.T) {
+	api := newTestAPI(t)
+	api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/team/add", token: testAdminToken, body: backendTeam})
+
+	create := idempotent("create-pr-1", testRequest{method: "POST", path: "/pullRequest/create",
+		body: `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u9"}`})
+
+	first := api.must(t, http.StatusNotFound, create)
+
+	// The author appearing later does not change the stored outcome.
+	api.must(t, http.StatusOK, testRequest{method: "POST", path: "/admin/users/import", token: testAdminToken,
+		headers: map[string]string{"Content-Type": "text/csv"}, body: "u9,Ivan,backend,true\n"})
+
+	replayed := api.must(t, http.StatusNotFound, create)
+	if replayed.header.Get(idempotencyReplayedHdr) != "true" || string(replayed.body) != string(first.body) {
+		t.Errorf("client error was not replayed: %s", replayed.body)
+	}
+}
@@ -0,0 +1,337 @@
+package rest
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/big"
+	"net/http"
+	"os"
+	"review-assigner/config"
+	"review-assigner/core"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Reloading the key set on an unknown kid is rate limited so that forged
+// tokens cannot make us hammer the identity provider.
+const jwksMinRefresh = time.Minute
+
+var jwtMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	decode := base64.RawURLEncoding.DecodeString
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode n: %w", err)
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode e: %w", err)
+		}
+		// Exponents are small in practice and crypto/rsa rejects ones over
+		// 2^31-1, so anything longer than four bytes is not a usable key.
+		if len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("unsupported exponent of %d bytes", len(e))
+		}
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		if exponent < 3 || exponent > math.MaxInt32 {
+			return nil, fmt.Errorf("unsupported exponent %d", exponent)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode x: %w", err)
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decode y: %w", err)
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("point is not on curve %s", k.Crv)
+		}
+		return key, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode x: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 key size %d", len(x))
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwks contains no signing keys")
+	}
+
+	return keys, nil
+}
+
+type jwtVerifier struct {
+	log    *slog.Logger
+	cfg    config.JWTConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	refreshed time.Time
+}
+
+func newJWTVerifier(log *slog.Logger, cfg config.JWTConfig) (*jwtVerifier, error) {
+	v := &jwtVerifier{
+		log:    log,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+
+	keys, err := v.load(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	v.keys = keys
+	v.refreshed = time.Now()
+
+	return v, nil
+}
+
+func (v *jwtVerifier) load(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if v.cfg.JWKSFile != "" {
+		data, err = os.ReadFile(v.cfg.JWKSFile)
+	} else {
+		data, err = v.fetch(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load jwks: %w", err)
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	v.log.Debug("jwks loaded", "keys", len(keys))
+
+	return keys, nil
+}
+
+func (v *jwtVerifier) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.cfg.JWKSURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// key looks up a signing key and reloads the key set from the URL when the
+// kid is unknown. The lock is never held during the fetch, so that requests
+// signed with known keys are not held up by a slow identity provider.
+func (v *jwtVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	key, ok := v.keys[kid]
+	refresh := !ok && v.cfg.JWKSURL != "" && time.Since(v.refreshed) > jwksMinRefresh
+	if refresh {
+		// Claiming the refresh up front keeps concurrent lookups from
+		// fetching the key set again.
+		v.refreshed = time.Now()
+	}
+	v.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+
+	if refresh {
+		keys, err := v.load(ctx)
+		if err != nil {
+			v.log.Warn("failed to refresh jwks", "error", err)
+		} else {
+			v.mu.Lock()
+			v.keys = keys
+			v.mu.Unlock()
+
+			if key, ok := keys[kid]; ok {
+				return key, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (v *jwtVerifier) verify(ctx context.Context, raw string) (core.Token, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(jwtMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(v.cfg.Leeway),
+	}
+	if v.cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(v.cfg.Issuer))
+	}
+	if v.cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(v.cfg.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.key(ctx, kid)
+	}, options...)
+	if err != nil {
+		v.log.Debug("rejected jwt", "error", err)
+		return core.Token{}, core.ErrTokenNotFound
+	}
+
+	userID, _ := claims[v.cfg.UserClaim].(string)
+	if userID == "" {
+		v.log.Debug("rejected jwt without user claim", "claim", v.cfg.UserClaim)
+		return core.Token{}, core.ErrTokenNotFound
+	}
+
+	tenant, _ := claims[v.cfg.TenantClaim].(string)
+	if tenant == "" {
+		tenant = core.DefaultTenant
+	}
+	if !core.ValidTenant(tenant) {
+		v.log.Debug("rejected jwt with invalid tenant claim", "claim", v.cfg.TenantClaim)
+		return core.Token{}, core.ErrTokenNotFound
+	}
+
+	token := core.Token{
+		Name:   "jwt",
+		Tenant: tenant,
+		UserID: userID,
+	}
+
+	for _, role := range stringsClaim(claims[v.cfg.RolesClaim]) {
+		for _, scope := range v.scopes(role) {
+			if !slices.Contains(token.Scopes, scope) {
+				token.Scopes = append(token.Scopes, scope)
+			}
+		}
+	}
+
+	return token, nil
+}
+
+func (v *jwtVerifier) scopes(role string) []core.Scope {
+	if mapped, ok := v.cfg.RoleScopes[role]; ok {
+		scopes := make([]core.Scope, 0, len(mapped))
+		for _, scope := range mapped {
+			scopes = append(scopes, core.Scope(scope))
+		}
+		return scopes
+	}
+	if slices.Contains(core.Scopes, core.Scope(role)) {
+		return []core.Scope{core.Scope(role)}
+	}
+	return nil
+}
+
+// Identity providers put roles either into a JSON array or into a single
+// space separated string, like the standard scope claim.
+func stringsClaim(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func looksLikeJWT(secret string) bool {
+	return strings.Count(secret, ".") == 2
+}
@@ -0,0 +1,284 @@
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"review-assigner/config"
+	"review-assigner/core"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type signingKey struct {
+	kid string
+	key *rsa.PrivateKey
+}
+
+func newSigningKey(t *testing.T, kid string) signingKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	return signingKey{kid: kid, key: key}
+}
+
+func (k signingKey) jwk() jwk {
+	encode := base64.RawURLEncoding.EncodeToString
+	return jwk{
+		Kty: "RSA",
+		Kid: k.kid,
+		Use: "sig",
+		N:   encode(k.key.N.Bytes()),
+		E:   encode(big.NewInt(int64(k.key.E)).Bytes()),
+	}
+}
+
+func (k signingKey) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = k.kid
+	signed, err := token.SignedString(k.key)
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+	return signed
+}
+
+// fakeJWKS serves a key set that tests can rotate, and can hold requests
+// until released to play a slow identity provider.
+type fakeJWKS struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	keys    []jwk
+	hold    chan struct{}
+	fetches atomic.Int32
+}
+
+func newFakeJWKS(t *testing.T, keys ...signingKey) *fakeJWKS {
+	t.Helper()
+
+	f := &fakeJWKS{}
+	f.serve(keys...)
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.fetches.Add(1)
+
+		f.mu.Lock()
+		keys, hold := f.keys, f.hold
+		f.mu.Unlock()
+		if hold != nil {
+			<-hold
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string][]jwk{"keys": keys})
+	}))
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+func (f *fakeJWKS) serve(keys ...signingKey) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.keys = nil
+	for _, key := range keys {
+		f.keys = append(f.keys, key.jwk())
+	}
+}
+
+func (f *fakeJWKS) holdRequests() (release func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	hold := make(chan struct{})
+	f.hold = hold
+	return func() { close(hold) }
+}
+
+func newTestVerifier(t *testing.T, url string) *jwtVerifier {
+	t.Helper()
+
+	verifier, err := newJWTVerifier(slog.New(slog.NewTextHandler(io.Discard, nil)), config.JWTConfig{
+		JWKSURL:     url,
+		Timeout:     5 * time.Second,
+		Issuer:      "https://sso.example.com",
+		Audience:    "review-assigner",
+		UserClaim:   "sub",
+		RolesClaim:  "roles",
+		TenantClaim: "tenant",
+		RoleScopes:  map[string][]string{"developer": {"prs:read", "prs:write"}},
+	})
+	if err != nil {
+		t.Fatalf("newJWTVerifier: %v", err)
+	}
+	return verifier
+}
+
+func claims(overrides jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss":   "https://sso.example.com",
+		"aud":   "review-assigner",
+		"sub":   "u1",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"developer", "stats:read", "unknown"},
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+	}
+	return claims
+}
+
+func TestJWTVerify(t *testing.T) {
+	key := newSigningKey(t, "k1")
+	verifier := newTestVerifier(t, newFakeJWKS(t, key).URL)
+	ctx := context.Background()
+
+	token, err := verifier.verify(ctx, key.sign(t, claims(jwt.MapClaims{"tenant": "acme"})))
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if token.UserID != "u1" || token.Tenant != "acme" ||
+		!slices.Equal(token.Scopes, []core.Scope{core.ScopePRsRead, core.ScopePRsWrite, core.ScopeStatsRead}) {
+		t.Errorf("unexpected token %+v", token)
+	}
+
+	token, err = verifier.verify(ctx, key.sign(t, claims(jwt.MapClaims{"roles": "admin"})))
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if token.Tenant != core.DefaultTenant || !token.IsAdmin() {
+		t.Errorf("unexpected token %+v", token)
+	}
+
+	other := newSigningKey(t, "k1")
+	rejected := map[string]string{
+		"expired":         key.sign(t, claims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})),
+		"without expiry":  key.sign(t, claims(jwt.MapClaims{"exp": nil})),
+		"other issuer":    key.sign(t, claims(jwt.MapClaims{"iss": "https://evil.example.com"})),
+		"other audience":  key.sign(t, claims(jwt.MapClaims{"aud": "billing"})),
+		"without subject": key.sign(t, claims(jwt.MapClaims{"sub": nil})),
+		"invalid tenant":  key.sign(t, claims(jwt.MapClaims{"tenant": "acme/evil"})),
+		"forged":          other.sign(t, claims(nil)),
+		"unsigned": func() string {
+			signed, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claims(nil)).
+				SignedString(jwt.UnsafeAllowNoneSignatureType)
+			return signed
+		}(),
+	}
+	for name, raw := range rejected {
+		t.Run(name, func(t *testing.T) {
+			if _, err := verifier.verify(ctx, raw); !errors.Is(err, core.ErrTokenNotFound) {
+				t.Errorf("got %v, want ErrTokenNotFound", err)
+			}
+		})
+	}
+}
+
+func TestJWTKeyRotation(t *testing.T) {
+	old, rotated := newSigningKey(t, "k1"), newSigningKey(t, "k2")
+	jwks := newFakeJWKS(t, old)
+	verifier := newTestVerifier(t, jwks.URL)
+	ctx := context.Background()
+
+	jwks.serve(old, rotated)
+	if _, err := verifier.verify(ctx, rotated.sign(t, claims(nil))); err == nil {
+		t.Fatal("key set was reloaded within the refresh interval")
+	}
+
+	verifier.refreshed = time.Now().Add(-2 * jwksMinRefresh)
+	if _, err := verifier.verify(ctx, rotated.sign(t, claims(nil))); err != nil {
+		t.Fatalf("verify with rotated key: %v", err)
+	}
+	if _, err := verifier.verify(ctx, old.sign(t, claims(nil))); err != nil {
+		t.Fatalf("verify with old key: %v", err)
+	}
+	if fetches := jwks.fetches.Load(); fetches != 2 {
+		t.Errorf("got %d fetches, want 2", fetches)
+	}
+}
+
+func TestJWTSlowRefreshDoesNotBlockKnownKeys(t *testing.T) {
+	known, unknown := newSigningKey(t, "k1"), newSigningKey(t, "k2")
+	jwks := newFakeJWKS(t, known)
+	verifier := newTestVerifier(t, jwks.URL)
+	verifier.refreshed = time.Now().Add(-2 * jwksMinRefresh)
+	ctx := context.Background()
+
+	release := jwks.holdRequests()
+	refreshed := make(chan error)
+	go func() {
+		_, err := verifier.verify(ctx, unknown.sign(t, claims(nil)))
+		refreshed <- err
+	}()
+	for jwks.fetches.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	verified := make(chan error)
+	go func() {
+		_, err := verifier.verify(ctx, known.sign(t, claims(nil)))
+		verified <- err
+	}()
+	select {
+	case err := <-verified:
+		if err != nil {
+			t.Errorf("verify with known key: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("verification with a known key waited for the jwks refresh")
+	}
+
+	release()
+	if err := <-refreshed; !errors.Is(err, core.ErrTokenNotFound) {
+		t.Errorf("got %v for a key missing from the refreshed set", err)
+	}
+}
+
+func TestJWKPublicKeyExponent(t *testing.T) {
+	key := newSigningKey(t, "k1").jwk()
+
+	parsed, err := key.publicKey()
+	if err != nil {
+		t.Fatalf("publicKey: %v", err)
+	}
+	if e := parsed.(*rsa.PublicKey).E; e != 65537 {
+		t.Errorf("got exponent %d, want 65537", e)
+	}
+
+	for name, e := range map[string][]byte{
+		"empty":     {},
+		"too small": {1},
+		"too large": {1, 0, 0, 0, 1},
+		"negative":  {0x80, 0, 0, 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			key.E = base64.RawURLEncoding.EncodeToString(e)
+			if _, err := key.publicKey(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"review-assigner/core"
+	"strconv"
+	"time"
+)
+
+func parsePRFilter(query url.Values) (core.PRFilter, error) {
+	filter := core.PRFilter{
+		AuthorID:   query.Get("author_id"),
+		Repository: query.Get("repository"),
+		TeamName:   query.Get("team_name"),
+		Status:     query.Get("state"),
+	}
+
+	for name, target := range map[string]**time.Time{
+		"created_from": &filter.CreatedFrom,
+		"created_to":   &filter.CreatedTo,
+	} {
+		if value := query.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return core.PRFilter{}, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+			}
+			*target = &t
+		}
+	}
+
+	switch query.Get("sort") {
+	case "", "created_at":
+	case "-created_at":
+		filter.Descending = true
+	default:
+		return core.PRFilter{}, fmt.Errorf("sort must be created_at or -created_at")
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > core.MaxPageSize {
+			return core.PRFilter{}, fmt.Errorf("limit must be between 1 and %d", core.MaxPageSize)
+		}
+		filter.Limit = limit
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		cursor, err := core.DecodePRCursor(value)
+		if err != nil {
+			return core.PRFilter{}, err
+		}
+		filter.After = &cursor
+	}
+
+	return filter, nil
+}
+
+func (h *Handler) ListPullRequests(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePRFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PARAMETER", err.Error())
+		return
+	}
+
+	page, err := h.service.ListPRs(r.Context(), filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response := ListPRsResponse{
+		PullRequests: make([]PRResponse, 0, len(page.PullRequests)),
+		NextCursor:   page.NextCursor,
+	}
+	for _, pr := range page.PullRequests {
+		response.PullRequests = append(response.PullRequests, toPRResponse(pr))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,123 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"review-assigner/core"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newReviewsAPI returns an API where u2 reviews the given number of PRs.
+func newReviewsAPI(t *testing.T, count int) *testAPI {
+	t.Helper()
+
+	api := newTestAPI(t)
+	ctx := context.Background()
+	_, err := api.service.CreateTeam(ctx, core.Team{TeamName: "backend", Members: []core.TeamMember{
+		{UserID: "u1", Username: "Alice", IsActive: true},
+		{UserID: "u2", Username: "Bob", IsActive: true},
+		{UserID: "u3", Username: "Carol", IsActive: true},
+	}})
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	for i := range count {
+		pullRequest := core.PullRequest{PullRequestID: fmt.Sprintf("pr-%d", i), PullRequestName: "Change", AuthorID: "u1"}
+		if _, err := api.service.CreatePR(ctx, pullRequest); err != nil {
+			t.Fatalf("create pr: %v", err)
+		}
+	}
+	return api
+}
+
+func ids(prs []PRShortResponse) []string {
+	var ids []string
+	for _, pr := range prs {
+		ids = append(ids, pr.PullRequestID)
+	}
+	return ids
+}
+
+func TestUserReviewsPagination(t *testing.T) {
+	const count = core.DefaultPageSize + 5
+	api := newReviewsAPI(t, count)
+
+	all := decode[GetUserReviewsResponse](t, api.must(t, http.StatusOK, testRequest{
+		method: "GET", path: "/users/getReview?user_id=u2", token: testAdminToken}))
+	if len(all.PullRequests) != count || all.NextCursor != "" {
+		t.Fatalf("without limit got %d PRs and cursor %q, want all %d", len(all.PullRequests), all.NextCursor, count)
+	}
+
+	less := func(a, b PRShortResponse) int {
+		ta, _ := time.Parse(time.RFC3339Nano, *a.CreatedAt)
+		tb, _ := time.Parse(time.RFC3339Nano, *b.CreatedAt)
+		if c := ta.Compare(tb); c != 0 {
+			return c
+		}
+		return strings.Compare(a.PullRequestID, b.PullRequestID)
+	}
+	if !slices.IsSortedFunc(all.PullRequests, less) {
+		t.Errorf("reviews are not ordered by creation time: %v", ids(all.PullRequests))
+	}
+
+	desc := decode[GetUserReviewsResponse](t, api.must(t, http.StatusOK, testRequest{
+		method: "GET", path: "/users/getReview?user_id=u2&sort=-created_at", token: testAdminToken}))
+	reversed := slices.Clone(ids(all.PullRequests))
+	slices.Reverse(reversed)
+	if !slices.Equal(ids(desc.PullRequests), reversed) {
+		t.Errorf("descending order %v, want %v", ids(desc.PullRequests), reversed)
+	}
+
+	var paged []string
+	path := "/users/getReview?user_id=u2&limit=20"
+	for pages := 0; ; pages++ {
+		if pages > count {
+			t.Fatal("cursors do not end")
+		}
+		page := decode[GetUserReviewsResponse](t, api.must(t, http.StatusOK, testRequest{
+			method: "GET", path: path, token: testAdminToken}))
+		if len(page.PullRequests) > 20 {
+			t.Fatalf("page of %d PRs, want at most 20", len(page.PullRequests))
+		}
+		paged = append(paged, ids(page.PullRequests)...)
+		if page.NextCursor == "" {
+			break
+		}
+		path = "/users/getReview?user_id=u2&limit=20&cursor=" + page.NextCursor
+	}
+	if !slices.Equal(paged, ids(all.PullRequests)) {
+		t.Errorf("pages %v, want %v", paged, ids(all.PullRequests))
+	}
+
+	api.must(t, http.StatusBadRequest, testRequest{
+		method: "GET", path: "/users/getReview?user_id=u2&cursor=garbage", token: testAdminToken})
+}
+
+func TestUserReviewsV2Pagination(t *testing.T) {
+	const count = core.DefaultPageSize + 5
+	api := newReviewsAPI(t, count)
+
+	first := api.must(t, http.StatusOK, testRequest{method: "GET", path: "/v2/users/u2/reviews", token: testAdminToken})
+	if got := len(decode[[]PRShortResponse](t, first)); got != core.DefaultPageSize {
+		t.Fatalf("first page has %d PRs, want %d", got, core.DefaultPageSize)
+	}
+
+	link := first.header.Get("Link")
+	next, ok := strings.CutPrefix(link, "<")
+	next, _, found := strings.Cut(next, `>; rel="next"`)
+	if !ok || !found {
+		t.Fatalf("first page Link header %q", link)
+	}
+
+	second := api.must(t, http.StatusOK, testRequest{method: "GET", path: next, token: testAdminToken})
+	if got := len(decode[[]PRShortResponse](t, second)); got != count-core.DefaultPageSize {
+		t.Errorf("second page has %d PRs, want %d", got, count-core.DefaultPageSize)
+	}
+	if link := second.header.Get("Link"); link != "" {
+		t.Errorf("last page has Link header %q", link)
+	}
+}
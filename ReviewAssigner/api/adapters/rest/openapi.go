@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+func mustLoadSpec() *openapi3.T {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPISpec)
+	if err != nil {
+		panic(fmt.Sprintf("load openapi spec: %v", err))
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		panic(fmt.Sprintf("invalid openapi spec: %v", err))
+	}
+	return doc
+}
+
+func (h *Handler) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openAPISpec)
+}
+
+func validateRequests(doc *openapi3.T) mux.MiddlewareFunc {
+	specRouter, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		panic(fmt.Sprintf("build openapi router: %v", err))
+	}
+
+	options := &openapi3filter.Options{
+		MultiError: true,
+		// Credentials are checked by the auth middleware before validation.
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+	// Bodies of other media types (CSV) are streamed and checked by their handler.
+	streamed := *options
+	streamed.ExcludeRequestBody = true
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := specRouter.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+
+			// Handlers decode JSON, so treat bodies sent without a JSON content
+			// type (e.g. plain curl -d) as JSON instead of rejecting them.
+			if body := route.Operation.RequestBody; body != nil {
+				if body.Value.Content.Get("application/json") == nil {
+					input.Options = &streamed
+				} else if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+					r.Header.Set("Content-Type", "application/json")
+				}
+			}
+
+			err = openapi3filter.ValidateRequest(r.Context(), input)
+			if err != nil {
+				var parseErr *openapi3filter.ParseError
+				if errors.As(err, &parseErr) {
+					writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+					return
+				}
+				writeError(w, http.StatusBadRequest, "VALIDATION_ERROR", validationMessage(err))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func validationMessage(err error) string {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		messages := make([]string, 0, len(e))
+		for _, inner := range e {
+			messages = append(messages, validationMessage(inner))
+		}
+		return strings.Join(messages, "; ")
+	case *openapi3filter.RequestError:
+		message := e.Reason
+		if e.Err != nil {
+			message = validationMessage(e.Err)
+		}
+		switch {
+		case e.Parameter != nil:
+			return fmt.Sprintf("parameter %q: %s", e.Parameter.Name, message)
+		case e.RequestBody != nil:
+			return "request body: " + message
+		default:
+			return message
+		}
+	case *openapi3.SchemaError:
+		if path := e.JSONPointer(); len(path) > 0 {
+			return fmt.Sprintf("field %q: %s", strings.Join(path, "."), e.Reason)
+		}
+		return e.Reason
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// routeKeys lists the routes of the router as "METHOD /path/template".
+func routeKeys(t *testing.T, router *mux.Router) []string {
+	t.Helper()
+
+	var keys []string
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		for _, method := range methods {
+			keys = append(keys, method+" "+path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk routes: %v", err)
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+func TestSpecMatchesRoutes(t *testing.T) {
+	doc := mustLoadSpec()
+
+	var documented []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented = append(documented, method+" "+path)
+		}
+	}
+
+	routes := routeKeys(t, (&Handler{}).routes())
+	for _, key := range routes {
+		if !slices.Contains(documented, key) {
+			t.Errorf("undocumented route %s", key)
+		}
+	}
+	for _, key := range documented {
+		if !slices.Contains(routes, key) {
+			t.Errorf("documented route without handler %s", key)
+		}
+	}
+}
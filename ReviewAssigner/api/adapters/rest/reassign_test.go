@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestReassignUrgentPicksLeastLoaded(t *testing.T) {
+	api := newTestAPI(t)
+	api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/team/add", token: testAdminToken,
+		body: `{"team_name":"backend","members":[` +
+			`{"user_id":"u1","username":"Alice","is_active":true},` +
+			`{"user_id":"u2","username":"Bob","is_active":true},` +
+			`{"user_id":"u3","username":"Carol","is_active":true},` +
+			`{"user_id":"u4","username":"Dave","is_active":true},` +
+			`{"user_id":"u5","username":"Eve","is_active":true}]}`})
+
+	create := func(prId, authorId, priority string) []string {
+		t.Helper()
+		resp := api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/pullRequest/create", token: testAdminToken,
+			body: `{"pull_request_id":"` + prId + `","pull_request_name":"Change","author_id":"` + authorId +
+				`","priority":"` + priority + `"}`})
+		return decode[CreatePRResponse](t, resp).PR.AssignedReviewers
+	}
+
+	// Open reviews: u1 has three, u2 two, u3 one and u4 none.
+	create("pr-1", "u5", "NORMAL")
+	create("pr-2", "u5", "NORMAL")
+	create("pr-3", "u2", "NORMAL")
+	if reviewers := create("pr-4", "u5", "URGENT"); !slices.Equal(reviewers, []string{"u4", "u3"}) {
+		t.Fatalf("urgent pr reviewers %v, want [u4 u3]", reviewers)
+	}
+
+	resp := api.must(t, http.StatusOK, testRequest{method: "POST", path: "/pullRequest/reassign", token: testAdminToken,
+		body: `{"pull_request_id":"pr-4","old_user_id":"u4"}`})
+	if replacedBy := decode[ReassignPRResponse](t, resp).ReplacedBy; replacedBy != "u2" {
+		t.Errorf("replaced by %s, want the least loaded u2", replacedBy)
+	}
+}
@@ -0,0 +1,145 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"review-assigner/core"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func toRepositoryDTO(repository core.Repository) RepositoryDTO {
+	dto := RepositoryDTO{Name: repository.Name, TeamNames: repository.Teams}
+	if dto.TeamNames == nil {
+		dto.TeamNames = []string{}
+	}
+	return dto
+}
+
+// resolvePR returns the id of a pull request given either by id or by its
+// number in a repository. The id wins when both are given.
+func (h *Handler) resolvePR(w http.ResponseWriter, r *http.Request, id, repository string, number int64) (string, bool) {
+	if id != "" {
+		return id, true
+	}
+
+	id, err := h.service.FindPR(r.Context(), repository, number)
+	if err != nil {
+		writeServiceError(w, err)
+		return "", false
+	}
+	return id, true
+}
+
+// pathPR resolves the pull request of a v2 route, addressed either as
+// /pull-requests/{id} or as /repositories/{repository}/pull-requests/{number}.
+func (h *Handler) pathPR(w http.ResponseWriter, r *http.Request) (string, bool) {
+	vars := mux.Vars(r)
+	if id, ok := vars["id"]; ok {
+		return id, true
+	}
+
+	number, err := strconv.ParseInt(vars["number"], 10, 64)
+	if err != nil {
+		writeServiceError(w, core.ErrInvalidRepository)
+		return "", false
+	}
+	return h.resolvePR(w, r, "", vars["repository"], number)
+}
+
+func (h *Handler) CreateRepository(w http.ResponseWriter, r *http.Request) {
+	var req RepositoryDTO
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	repository, err := h.service.CreateRepository(r.Context(), core.Repository{Name: req.Name, Teams: req.TeamNames})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, RepositoryResponse{Repository: toRepositoryDTO(repository)})
+}
+
+func (h *Handler) GetRepository(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("repository")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_PARAMETER", "repository parameter is required")
+		return
+	}
+
+	repository, err := h.service.GetRepository(r.Context(), name)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, RepositoryResponse{Repository: toRepositoryDTO(repository)})
+}
+
+func (h *Handler) LinkRepository(w http.ResponseWriter, r *http.Request) {
+	var req LinkRepositoryRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	if req.Repository == "" || req.TeamName == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_FIELDS", "repository and team_name are required")
+		return
+	}
+
+	repository, err := h.service.LinkRepository(r.Context(), req.Repository, req.TeamName)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, RepositoryResponse{Repository: toRepositoryDTO(repository)})
+}
+
+func (h *Handler) CreateRepositoryV2(w http.ResponseWriter, r *http.Request) {
+	var req RepositoryDTO
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	repository, err := h.service.CreateRepository(r.Context(), core.Repository{Name: req.Name, Teams: req.TeamNames})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v2/repositories/"+url.PathEscape(repository.Name))
+	writeJSON(w, http.StatusCreated, toRepositoryDTO(repository))
+}
+
+func (h *Handler) GetRepositoryV2(w http.ResponseWriter, r *http.Request) {
+	repository, err := h.service.GetRepository(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toRepositoryDTO(repository))
+}
+
+func (h *Handler) LinkRepositoryV2(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	repository, err := h.service.LinkRepository(r.Context(), vars["name"], vars["team_name"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toRepositoryDTO(repository))
+}
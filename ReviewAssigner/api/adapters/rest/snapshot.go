@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"review-assigner/core"
+	"strconv"
+)
+
+func toSnapshotDTO(snapshot core.Snapshot) SnapshotDTO {
+	dto := SnapshotDTO{
+		Version:      snapshot.Version,
+		ExportedAt:   snapshot.ExportedAt,
+		Teams:        make([]SnapshotTeamDTO, 0, len(snapshot.Teams)),
+		Users:        make([]UserResponse, 0, len(snapshot.Users)),
+		PullRequests: make([]SnapshotPRDTO, 0, len(snapshot.PullRequests)),
+	}
+
+	for _, team := range snapshot.Teams {
+		dto.Teams = append(dto.Teams, SnapshotTeamDTO{TeamName: team.TeamName, LeadID: team.LeadID})
+	}
+	for _, user := range snapshot.Users {
+		dto.Users = append(dto.Users, toUserResponse(user))
+	}
+	for _, repository := range snapshot.Repositories {
+		dto.Repositories = append(dto.Repositories, toRepositoryDTO(repository))
+	}
+	for _, pr := range snapshot.PullRequests {
+		pullRequest := SnapshotPRDTO{
+			PullRequestID:     pr.PullRequest.PullRequestID,
+			Repository:        pr.PullRequest.Repository,
+			PullRequestNumber: pr.PullRequest.Number,
+			PullRequestName:   pr.PullRequest.PullRequestName,
+			AuthorID:          pr.PullRequest.AuthorID,
+			Status:            pr.PullRequest.Status,
+			CreatedAt:         pr.PullRequest.CreatedAt,
+			MergedAt:          pr.PullRequest.MergedAt,
+			Version:           pr.PullRequest.Version,
+			Reviewers:         make([]SnapshotReviewerDTO, 0, len(pr.Reviewers)),
+			History:           make([]HistoryEntryDTO, 0, len(pr.History)),
+			PRMetadataDTO:     toPRMetadataDTO(pr.PullRequest),
+		}
+		for _, reviewer := range pr.Reviewers {
+			pullRequest.Reviewers = append(pullRequest.Reviewers, SnapshotReviewerDTO{
+				UserID:     reviewer.ReviewerID,
+				ApprovedAt: reviewer.ApprovedAt,
+			})
+		}
+		for _, entry := range pr.History {
+			pullRequest.History = append(pullRequest.History, HistoryEntryDTO{
+				Event:     string(entry.Event),
+				ActorID:   entry.ActorID,
+				Details:   entry.Details,
+				CreatedAt: entry.CreatedAt,
+			})
+		}
+		dto.PullRequests = append(dto.PullRequests, pullRequest)
+	}
+
+	return dto
+}
+
+func fromSnapshotDTO(dto SnapshotDTO) core.Snapshot {
+	snapshot := core.Snapshot{
+		Version:    dto.Version,
+		ExportedAt: dto.ExportedAt,
+	}
+
+	for _, team := range dto.Teams {
+		snapshot.Teams = append(snapshot.Teams, core.Team{TeamName: team.TeamName, LeadID: team.LeadID})
+	}
+	for _, user := range dto.Users {
+		snapshot.Users = append(snapshot.Users, core.User{
+			UserID:           user.UserID,
+			Username:         user.Username,
+			TeamName:         user.TeamName,
+			IsActive:         user.IsActive,
+			OutOfOfficeUntil: user.OutOfOfficeUntil,
+		})
+	}
+	for _, repository := range dto.Repositories {
+		snapshot.Repositories = append(snapshot.Repositories, core.Repository{
+			Name:  repository.Name,
+			Teams: repository.TeamNames,
+		})
+	}
+	for _, pr := range dto.PullRequests {
+		pullRequest := core.PRSnapshot{
+			PullRequest: withPRMetadata(core.PullRequest{
+				PullRequestID:   pr.PullRequestID,
+				Repository:      pr.Repository,
+				Number:          pr.PullRequestNumber,
+				PullRequestName: pr.PullRequestName,
+				AuthorID:        pr.AuthorID,
+				Status:          pr.Status,
+				CreatedAt:       pr.CreatedAt,
+				MergedAt:        pr.MergedAt,
+				Version:         pr.Version,
+			}, pr.PRMetadataDTO),
+		}
+		for _, reviewer := range pr.Reviewers {
+			pullRequest.Reviewers = append(pullRequest.Reviewers, core.Assignment{
+				PullRequestID: pr.PullRequestID,
+				ReviewerID:    reviewer.UserID,
+				ApprovedAt:    reviewer.ApprovedAt,
+			})
+		}
+		for _, entry := range pr.History {
+			pullRequest.History = append(pullRequest.History, core.PRHistoryEntry{
+				PullRequestID: pr.PullRequestID,
+				Event:         core.HistoryEvent(entry.Event),
+				ActorID:       entry.ActorID,
+				Details:       entry.Details,
+				CreatedAt:     entry.CreatedAt,
+			})
+		}
+		snapshot.PullRequests = append(snapshot.PullRequests, pullRequest)
+	}
+
+	return snapshot
+}
+
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := h.service.Export(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toSnapshotDTO(snapshot))
+}
+
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	var dryRun bool
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_PARAMETER", "dry_run must be a boolean")
+			return
+		}
+	}
+
+	var req SnapshotDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	result, err := h.service.Import(r.Context(), fromSnapshotDTO(req), dryRun)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ImportResponse{
+		DryRun:       result.DryRun,
+		Teams:        ImportCountDTO(result.Teams),
+		Users:        ImportCountDTO(result.Users),
+		Repositories: ImportCountDTO(result.Repositories),
+		PullRequests: ImportCountDTO(result.PullRequests),
+	})
+}
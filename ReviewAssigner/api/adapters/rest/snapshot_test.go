@@ -0,0 +1,28 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestImportRejectsDuplicatePRNumbers(t *testing.T) {
+	api := newTestAPI(t)
+
+	snapshot := `{"version":1,"exported_at":"2024-03-01T10:00:00Z",` +
+		`"teams":[{"team_name":"backend"}],` +
+		`"users":[{"user_id":"u1","username":"Alice","team_name":"backend","is_active":true}],` +
+		`"repositories":[{"name":"api","team_names":[]}],` +
+		`"pull_requests":[` +
+		`{"pull_request_id":"pr-1","repository":"api","pull_request_number":7,"pull_request_name":"First",` +
+		`"author_id":"u1","status":"OPEN","version":1,"reviewers":[],"history":[]},` +
+		`{"pull_request_id":"pr-2","repository":"api","pull_request_number":7,"pull_request_name":"Second",` +
+		`"author_id":"u1","status":"OPEN","version":1,"reviewers":[],"history":[]}]}`
+
+	resp := api.must(t, http.StatusBadRequest, testRequest{method: "POST", path: "/admin/import", token: testAdminToken, body: snapshot})
+	if !strings.Contains(string(resp.body), "INVALID_SNAPSHOT") {
+		t.Errorf("unexpected response %s", resp.body)
+	}
+
+	api.must(t, http.StatusNotFound, testRequest{method: "GET", path: "/team/get?team_name=backend", token: testAdminToken})
+}
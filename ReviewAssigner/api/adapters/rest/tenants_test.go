@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"maps"
+	"net/http"
+	"review-assigner/core"
+	"slices"
+	"testing"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	api := newTestAPI(t)
+
+	for tenant, prId := range map[string]string{"default": "pr-1", "acme": "pr-2"} {
+		headers := map[string]string{tenantHeader: tenant}
+		api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/team/add", token: testAdminToken,
+			headers: headers, body: backendTeam})
+		api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/pullRequest/create", token: testAdminToken,
+			headers: headers, body: `{"pull_request_id":"` + prId + `","pull_request_name":"Add search","author_id":"u1"}`})
+	}
+
+	readers := map[string]string{}
+	for _, tenant := range []string{"default", "acme"} {
+		readers[tenant] = api.token(t, tenant, core.Token{
+			Name:   "reader",
+			Scopes: []core.Scope{core.ScopeStatsRead, core.ScopeUsersRead},
+		})
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		headers map[string]string
+		prIds   []string
+	}{
+		{name: "default token", token: readers["default"], prIds: []string{"pr-1"}},
+		{name: "acme token", token: readers["acme"], prIds: []string{"pr-2"}},
+		{name: "acme token repeating its tenant", token: readers["acme"],
+			headers: map[string]string{tenantHeader: "acme"}, prIds: []string{"pr-2"}},
+		{name: "admin token selecting acme", token: testAdminToken,
+			headers: map[string]string{tenantHeader: "acme"}, prIds: []string{"pr-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := decode[StatsResponse](t, api.must(t, http.StatusOK, testRequest{
+				method: "GET", path: "/stats", token: tt.token, headers: tt.headers,
+			}))
+			if prIds := slices.Sorted(maps.Keys(stats.Stats.PRReviewerCounts)); !slices.Equal(prIds, tt.prIds) {
+				t.Errorf("stats cover %v, want %v", prIds, tt.prIds)
+			}
+
+			reviews := decode[GetUserReviewsResponse](t, api.must(t, http.StatusOK, testRequest{
+				method: "GET", path: "/users/getReview?user_id=u2", token: tt.token, headers: tt.headers,
+			}))
+			var prIds []string
+			for _, pr := range reviews.PullRequests {
+				prIds = append(prIds, pr.PullRequestID)
+			}
+			if !slices.Equal(prIds, tt.prIds) {
+				t.Errorf("reviews of u2 are %v, want %v", prIds, tt.prIds)
+			}
+		})
+	}
+
+	for _, path := range []string{"/stats", "/users/getReview?user_id=u2"} {
+		api.must(t, http.StatusForbidden, testRequest{method: "GET", path: path, token: readers["acme"],
+			headers: map[string]string{tenantHeader: "default"}})
+		api.must(t, http.StatusForbidden, testRequest{method: "GET", path: path, token: readers["default"],
+			headers: map[string]string{tenantHeader: "acme"}})
+	}
+}
@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"review-assigner/core"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func toTokenResponse(token core.Token) TokenResponse {
+	response := TokenResponse{
+		ID:         token.ID,
+		Tenant:     token.Tenant,
+		Name:       token.Name,
+		UserID:     token.UserID,
+		Scopes:     make([]string, 0, len(token.Scopes)),
+		CreatedAt:  token.CreatedAt,
+		LastUsedAt: token.LastUsedAt,
+	}
+
+	for _, scope := range token.Scopes {
+		response.Scopes = append(response.Scopes, string(scope))
+	}
+
+	return response
+}
+
+func (h *Handler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	var req CreateTokenRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	token := core.Token{
+		Name:   req.Name,
+		UserID: req.UserID,
+	}
+	for _, scope := range req.Scopes {
+		token.Scopes = append(token.Scopes, core.Scope(scope))
+	}
+
+	created, secret, err := h.service.CreateToken(r.Context(), token)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, CreateTokenResponse{
+		Token:  toTokenResponse(created),
+		Secret: secret,
+	})
+}
+
+func (h *Handler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := h.service.ListTokens(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response := ListTokensResponse{Tokens: make([]TokenResponse, 0, len(tokens))}
+	for _, token := range tokens {
+		response.Tokens = append(response.Tokens, toTokenResponse(token))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PARAMETER", "id must be an integer")
+		return
+	}
+
+	if err := h.service.RevokeToken(r.Context(), id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
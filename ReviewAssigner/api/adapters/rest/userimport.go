@@ -0,0 +1,134 @@
+package rest
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"review-assigner/core"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var userImportColumns = []string{"user_id", "username", "team_name", "active"}
+
+// ImportUsers reads CSV rows of user_id, username, team_name and active one by
+// one and stores each in its own transaction, so a bad row is reported
+// without affecting the others. The columns may be named in a header row and
+// come in any order; without a header they are taken in the order above.
+func (h *Handler) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	reader := csv.NewReader(r.Body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	response := UserImportResponse{Errors: []UserImportErrorDTO{}}
+	columns := []int{0, 1, 2, 3}
+	first := true
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				response.Errors = append(response.Errors, UserImportErrorDTO{Line: line, Message: "failed to read request body"})
+				break
+			}
+			response.Rows++
+			response.Errors = append(response.Errors, UserImportErrorDTO{Line: parseErr.Line, Message: parseErr.Err.Error()})
+			continue
+		}
+
+		if first {
+			first = false
+			if header, ok := userImportHeader(record); ok {
+				columns = header
+				continue
+			}
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		response.Rows++
+		user, err := userFromRecord(record, columns)
+		if err != nil {
+			response.Errors = append(response.Errors, UserImportErrorDTO{Line: line, UserID: user.UserID, Message: err.Error()})
+			continue
+		}
+
+		result, err := h.service.ImportUser(r.Context(), user)
+		if err != nil {
+			response.Errors = append(response.Errors, UserImportErrorDTO{Line: line, UserID: user.UserID, Message: h.rowError(err)})
+			continue
+		}
+		if result.UserCreated {
+			response.Users.Created++
+		} else {
+			response.Users.Updated++
+		}
+		if result.TeamCreated {
+			response.TeamsCreated++
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func userImportHeader(record []string) ([]int, bool) {
+	columns := make([]int, len(userImportColumns))
+	for i, name := range userImportColumns {
+		columns[i] = slices.IndexFunc(record, func(field string) bool {
+			return strings.EqualFold(strings.TrimSpace(field), name)
+		})
+	}
+	if slices.Contains(columns, -1) {
+		return nil, false
+	}
+	return columns, true
+}
+
+func userFromRecord(record []string, columns []int) (core.User, error) {
+	field := func(i int) string {
+		if columns[i] >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[columns[i]])
+	}
+
+	user := core.User{
+		UserID:   field(0),
+		Username: field(1),
+		TeamName: field(2),
+		IsActive: true,
+	}
+	if len(record) < len(userImportColumns) {
+		return user, fmt.Errorf("expected %d columns, got %d", len(userImportColumns), len(record))
+	}
+
+	if active := field(3); active != "" {
+		value, err := strconv.ParseBool(active)
+		if err != nil {
+			return user, fmt.Errorf("invalid active value %q", active)
+		}
+		user.IsActive = value
+	}
+
+	return user, nil
+}
+
+func (h *Handler) rowError(err error) string {
+	for _, known := range []error{core.ErrInvalidUser, core.ErrForbidden} {
+		if errors.Is(err, known) {
+			return err.Error()
+		}
+	}
+	h.log.Error("failed to import user", "error", err)
+	return "failed to store user"
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"net/http"
+	"review-assigner/core"
+	"testing"
+)
+
+func TestImportUsersErrorRows(t *testing.T) {
+	api := newTestAPI(t)
+
+	csv := "team_name,user_id,active,username\n" +
+		"backend,u1,true,Alice\n" +
+		"backend,u2,maybe,Bob\n" +
+		"frontend,u3\n" +
+		"backend,,true,Nobody\n" +
+		"\n" +
+		"frontend,u4,false,Dave\n" +
+		"backend,u1,true,Alice Smith\n" +
+		"backend,u\"5,true,Eve\n"
+
+	resp := api.must(t, http.StatusOK, testRequest{method: "POST", path: "/admin/users/import", token: testAdminToken,
+		headers: map[string]string{"Content-Type": "text/csv"}, body: csv})
+	result := decode[UserImportResponse](t, resp)
+
+	if result.Rows != 7 || result.Users.Created != 2 || result.Users.Updated != 1 || result.TeamsCreated != 2 {
+		t.Errorf("got %d rows, %+v users and %d new teams, want 7 rows, 2 created, 1 updated and 2 new teams",
+			result.Rows, result.Users, result.TeamsCreated)
+	}
+
+	want := []UserImportErrorDTO{
+		{Line: 3, UserID: "u2", Message: `invalid active value "maybe"`},
+		{Line: 4, UserID: "u3", Message: "expected 4 columns, got 2"},
+		{Line: 5, Message: core.ErrInvalidUser.Error()},
+		{Line: 9, Message: `bare " in non-quoted-field`},
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("got errors %+v, want %+v", result.Errors, want)
+	}
+	for i, w := range want {
+		if result.Errors[i] != w {
+			t.Errorf("error %d: got %+v, want %+v", i, result.Errors[i], w)
+		}
+	}
+
+	frontend := decode[GetTeamResponse](t, api.must(t, http.StatusOK, testRequest{method: "GET",
+		path: "/team/get?team_name=frontend", token: testAdminToken})).Team
+	if len(frontend.Members) != 1 || frontend.Members[0].UserID != "u4" || frontend.Members[0].IsActive {
+		t.Errorf("got frontend members %+v, want inactive u4 only", frontend.Members)
+	}
+}
@@ -0,0 +1,376 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"review-assigner/core"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+func (h *Handler) registerV2(router *mux.Router) {
+	v2 := router.PathPrefix("/v2").Subrouter()
+	v2.HandleFunc("/teams", h.CreateTeamV2).Methods("POST")
+	v2.HandleFunc("/teams/{name}", h.GetTeamV2).Methods("GET")
+	v2.HandleFunc("/teams/{name}", h.PatchTeamV2).Methods("PATCH")
+	v2.HandleFunc("/users/{id}", h.GetUserV2).Methods("GET")
+	v2.HandleFunc("/users/{id}", h.PatchUserV2).Methods("PATCH")
+	v2.HandleFunc("/users/{id}/reviews", h.GetUserReviewsV2).Methods("GET")
+	v2.HandleFunc("/pull-requests", h.CreatePullRequestV2).Methods("POST")
+	v2.HandleFunc("/repositories", h.CreateRepositoryV2).Methods("POST")
+	v2.HandleFunc("/repositories/{name}", h.GetRepositoryV2).Methods("GET")
+	v2.HandleFunc("/repositories/{name}/teams/{team_name}", h.LinkRepositoryV2).Methods("PUT")
+
+	// Pull requests are addressed by id or by their number in a repository.
+	for _, prefix := range []string{"/pull-requests/{id}", "/repositories/{repository}/pull-requests/{number}"} {
+		v2.HandleFunc(prefix, h.GetPullRequestV2).Methods("GET")
+		v2.HandleFunc(prefix, h.PatchPullRequestV2).Methods("PATCH")
+		v2.HandleFunc(prefix+"/reviewers", h.GetReviewersV2).Methods("GET")
+		v2.HandleFunc(prefix+"/reviewers/{user_id}/reassign", h.ReassignReviewerV2).Methods("POST")
+		v2.HandleFunc(prefix+"/reviewers/{user_id}/approval", h.ApproveV2).Methods("PUT")
+	}
+	v2.HandleFunc("/stats", h.GetStats).Methods("GET")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, core.ErrTeamNotFound):
+		writeError(w, http.StatusNotFound, "TEAM_NOT_FOUND", err.Error())
+	case errors.Is(err, core.ErrUserNotFound):
+		writeError(w, http.StatusNotFound, "USER_NOT_FOUND", err.Error())
+	case errors.Is(err, core.ErrPRNotFound):
+		writeError(w, http.StatusNotFound, "PR_NOT_FOUND", err.Error())
+	case errors.Is(err, core.ErrTeamAlreadyExists):
+		writeError(w, http.StatusConflict, "TEAM_EXISTS", err.Error())
+	case errors.Is(err, core.ErrPRAAlreadyExists):
+		writeError(w, http.StatusConflict, "PR_EXISTS", err.Error())
+	case errors.Is(err, core.ErrNotEnoughReviewers):
+		writeError(w, http.StatusConflict, "NOT_ENOUGH_REVIEWERS", err.Error())
+	case errors.Is(err, core.ErrPRAlreadyMerged):
+		writeError(w, http.StatusConflict, "PR_MERGED", err.Error())
+	case errors.Is(err, core.ErrReviewerNotAssigned):
+		writeError(w, http.StatusConflict, "NOT_ASSIGNED", err.Error())
+	case errors.Is(err, core.ErrNoReplacementCandidate):
+		writeError(w, http.StatusConflict, "NO_CANDIDATE", err.Error())
+	case errors.Is(err, core.ErrLeadNotMember):
+		writeError(w, http.StatusBadRequest, "LEAD_NOT_MEMBER", err.Error())
+	case errors.Is(err, core.ErrTokenNotFound):
+		writeError(w, http.StatusNotFound, "TOKEN_NOT_FOUND", err.Error())
+	case errors.Is(err, core.ErrInvalidScope):
+		writeError(w, http.StatusBadRequest, "INVALID_SCOPE", err.Error())
+	case errors.Is(err, core.ErrForbidden):
+		writeError(w, http.StatusForbidden, "FORBIDDEN", err.Error())
+	case errors.Is(err, core.ErrPreconditionFailed):
+		writeError(w, http.StatusPreconditionFailed, "PRECONDITION_FAILED", err.Error())
+	case errors.Is(err, core.ErrPRConflict):
+		writeError(w, http.StatusConflict, "PR_CONFLICT", err.Error())
+	case errors.Is(err, core.ErrInvalidSnapshot):
+		writeError(w, http.StatusBadRequest, "INVALID_SNAPSHOT", err.Error())
+	case errors.Is(err, core.ErrInvalidUser):
+		writeError(w, http.StatusBadRequest, "INVALID_USER", err.Error())
+	case errors.Is(err, core.ErrRepositoryNotFound):
+		writeError(w, http.StatusNotFound, "REPOSITORY_NOT_FOUND", err.Error())
+	case errors.Is(err, core.ErrRepositoryExists):
+		writeError(w, http.StatusConflict, "REPOSITORY_EXISTS", err.Error())
+	case errors.Is(err, core.ErrInvalidRepository):
+		writeError(w, http.StatusBadRequest, "INVALID_REPOSITORY", err.Error())
+	case errors.Is(err, core.ErrInvalidPRMetadata):
+		writeError(w, http.StatusBadRequest, "INVALID_PR_METADATA", err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error")
+	}
+}
+
+func (h *Handler) CreateTeamV2(w http.ResponseWriter, r *http.Request) {
+	var req AddTeamRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	team := core.Team{
+		TeamName: req.TeamName,
+		LeadID:   req.LeadID,
+	}
+	for _, member := range req.Members {
+		team.Members = append(team.Members, core.TeamMember{
+			UserID:   member.UserID,
+			Username: member.Username,
+			IsActive: member.IsActive,
+		})
+	}
+
+	createdTeam, err := h.service.CreateTeam(r.Context(), team)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v2/teams/"+url.PathEscape(createdTeam.TeamName))
+	writeJSON(w, http.StatusCreated, toTeamResponse(createdTeam))
+}
+
+func (h *Handler) GetTeamV2(w http.ResponseWriter, r *http.Request) {
+	team, err := h.service.GetTeam(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toTeamResponse(team))
+}
+
+func (h *Handler) PatchTeamV2(w http.ResponseWriter, r *http.Request) {
+	var req PatchTeamRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	var leadID string
+	if req.LeadID != nil {
+		leadID = *req.LeadID
+	}
+
+	team, err := h.service.SetTeamLead(r.Context(), mux.Vars(r)["name"], leadID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toTeamResponse(team))
+}
+
+func (h *Handler) GetUserV2(w http.ResponseWriter, r *http.Request) {
+	user, err := h.service.GetUser(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toUserResponse(user))
+}
+
+func (h *Handler) PatchUserV2(w http.ResponseWriter, r *http.Request) {
+	var (
+		req    PatchUserRequest
+		fields map[string]json.RawMessage
+	)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "Failed to read request body")
+		return
+	}
+	if json.Unmarshal(body, &fields) != nil || json.Unmarshal(body, &req) != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	// A present but null out_of_office_until clears the period, an absent one keeps it.
+	_, setOutOfOffice := fields["out_of_office_until"]
+
+	user, err := h.service.UpdateUser(r.Context(), mux.Vars(r)["id"], core.UserUpdate{
+		IsActive:         req.IsActive,
+		SetOutOfOffice:   setOutOfOffice,
+		OutOfOfficeUntil: req.OutOfOfficeUntil,
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toUserResponse(user))
+}
+
+func (h *Handler) GetUserReviewsV2(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePRFilter(url.Values{
+		"limit":  r.URL.Query()["limit"],
+		"cursor": r.URL.Query()["cursor"],
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_PARAMETER", err.Error())
+		return
+	}
+	filter.ReviewerID = mux.Vars(r)["id"]
+
+	page, err := h.service.ListPRs(r.Context(), filter)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if page.NextCursor != "" {
+		next := *r.URL
+		query := next.Query()
+		query.Set("cursor", page.NextCursor)
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.RequestURI()))
+	}
+
+	writeJSON(w, http.StatusOK, toPRShortResponses(page.PullRequests))
+}
+
+func (h *Handler) CreatePullRequestV2(w http.ResponseWriter, r *http.Request) {
+	var req CreatePRRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	pr, err := h.service.CreatePR(r.Context(), withPRMetadata(core.PullRequest{
+		PullRequestID:   req.PullRequestID,
+		Repository:      req.Repository,
+		Number:          req.PullRequestNumber,
+		PullRequestName: req.PullRequestName,
+		AuthorID:        req.AuthorID,
+	}, req.PRMetadataDTO))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v2/pull-requests/"+url.PathEscape(pr.PullRequestID))
+	setETag(w, pr)
+	writeJSON(w, http.StatusCreated, toPRResponse(pr))
+}
+
+func (h *Handler) GetPullRequestV2(w http.ResponseWriter, r *http.Request) {
+	prID, ok := h.pathPR(w, r)
+	if !ok {
+		return
+	}
+
+	pr, err := h.service.GetPR(r.Context(), prID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	setETag(w, pr)
+	writeJSON(w, http.StatusOK, toPRResponse(pr))
+}
+
+func (h *Handler) PatchPullRequestV2(w http.ResponseWriter, r *http.Request) {
+	var req PatchPRRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_JSON", "Invalid JSON format")
+		return
+	}
+
+	if req.Status != "MERGED" {
+		writeError(w, http.StatusBadRequest, "INVALID_STATUS", "status can only be changed to MERGED")
+		return
+	}
+
+	prID, ok := h.pathPR(w, r)
+	if !ok {
+		return
+	}
+
+	r, ok = ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	pr, err := h.service.Merged(r.Context(), prID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	setETag(w, pr)
+	writeJSON(w, http.StatusOK, toPRResponse(pr))
+}
+
+func (h *Handler) GetReviewersV2(w http.ResponseWriter, r *http.Request) {
+	prID, ok := h.pathPR(w, r)
+	if !ok {
+		return
+	}
+
+	pr, err := h.service.GetPR(r.Context(), prID)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	users, err := h.service.GetUsers(r.Context(), pr.AssignedReviewers)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	response := make([]UserResponse, 0, len(users))
+	for _, user := range users {
+		response = append(response, toUserResponse(user))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) ReassignReviewerV2(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	prID, ok := h.pathPR(w, r)
+	if !ok {
+		return
+	}
+
+	r, ok = ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	pr, newReviewer, err := h.service.Reassign(r.Context(), core.ReassignReviewer{
+		PRId:   prID,
+		UserID: vars["user_id"],
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	setETag(w, pr)
+	writeJSON(w, http.StatusOK, ReassignPRResponse{
+		PR:         toPRResponse(pr),
+		ReplacedBy: newReviewer,
+	})
+}
+
+func (h *Handler) ApproveV2(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	prID, ok := h.pathPR(w, r)
+	if !ok {
+		return
+	}
+
+	r, ok = ifMatch(w, r)
+	if !ok {
+		return
+	}
+
+	pr, err := h.service.Approve(r.Context(), prID, vars["user_id"])
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	setETag(w, pr)
+	writeJSON(w, http.StatusOK, toPRResponse(pr))
+}
@@ -0,0 +1,139 @@
+package rest
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestTeamsV2(t *testing.T) {
+	api := newTestAPI(t)
+
+	resp := api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/v2/teams", token: testAdminToken,
+		body: `{"team_name":"backend","lead_id":"u1","members":[` +
+			`{"user_id":"u1","username":"Alice","is_active":true},` +
+			`{"user_id":"u2","username":"Bob","is_active":true}]}`})
+	if location := resp.header.Get("Location"); location != "/v2/teams/backend" {
+		t.Errorf("got Location %q, want /v2/teams/backend", location)
+	}
+	api.must(t, http.StatusConflict, testRequest{method: "POST", path: "/v2/teams", token: testAdminToken,
+		body: `{"team_name":"backend","members":[]}`})
+
+	team := decode[TeamResponse](t, api.must(t, http.StatusOK, testRequest{
+		method: "GET", path: "/v2/teams/backend", token: testAdminToken}))
+	if team.LeadID != "u1" || len(team.Members) != 2 {
+		t.Errorf("got team %+v", team)
+	}
+	api.must(t, http.StatusNotFound, testRequest{method: "GET", path: "/v2/teams/missing", token: testAdminToken})
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		lead   string
+	}{
+		{name: "set lead", body: `{"lead_id":"u2"}`, status: http.StatusOK, lead: "u2"},
+		{name: "lead from another team", body: `{"lead_id":"u9"}`, status: http.StatusBadRequest},
+		{name: "clear lead", body: `{"lead_id":null}`, status: http.StatusOK, lead: ""},
+		{name: "missing lead", body: `{}`, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := api.must(t, tt.status, testRequest{method: "PATCH", path: "/v2/teams/backend", token: testAdminToken,
+				body: tt.body})
+			if tt.status == http.StatusOK {
+				if lead := decode[TeamResponse](t, resp).LeadID; lead != tt.lead {
+					t.Errorf("got lead %q, want %q", lead, tt.lead)
+				}
+			}
+		})
+	}
+}
+
+func TestUsersV2(t *testing.T) {
+	api := newTestAPI(t)
+	addBackendPR(t, api)
+
+	user := decode[UserResponse](t, api.must(t, http.StatusOK, testRequest{
+		method: "GET", path: "/v2/users/u2", token: testAdminToken}))
+	if user.TeamName != "backend" || !user.IsActive {
+		t.Errorf("got user %+v", user)
+	}
+	api.must(t, http.StatusNotFound, testRequest{method: "GET", path: "/v2/users/missing", token: testAdminToken})
+
+	user = decode[UserResponse](t, api.must(t, http.StatusOK, testRequest{method: "PATCH", path: "/v2/users/u5",
+		token: testAdminToken, body: `{"out_of_office_until":"2030-01-01T00:00:00Z"}`}))
+	if user.OutOfOfficeUntil == nil || !user.IsActive {
+		t.Errorf("got user %+v, want active and out of office", user)
+	}
+	user = decode[UserResponse](t, api.must(t, http.StatusOK, testRequest{method: "PATCH", path: "/v2/users/u5",
+		token: testAdminToken, body: `{"is_active":false}`}))
+	if user.OutOfOfficeUntil == nil || user.IsActive {
+		t.Errorf("got user %+v, want inactive and still out of office", user)
+	}
+	user = decode[UserResponse](t, api.must(t, http.StatusOK, testRequest{method: "PATCH", path: "/v2/users/u5",
+		token: testAdminToken, body: `{"out_of_office_until":null}`}))
+	if user.OutOfOfficeUntil != nil {
+		t.Errorf("got user %+v, want the period cleared", user)
+	}
+}
+
+func TestPullRequestsV2(t *testing.T) {
+	api := newTestAPI(t)
+	addBackendPR(t, api)
+	api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/v2/repositories", token: testAdminToken,
+		body: `{"name":"api","teams":["backend"]}`})
+
+	resp := api.must(t, http.StatusCreated, testRequest{method: "POST", path: "/v2/pull-requests", token: testAdminToken,
+		body: `{"repository":"api","pull_request_number":7,"pull_request_name":"Fix search","author_id":"u2"}`})
+	created := decode[PRResponse](t, resp)
+	if created.Repository != "api" || created.PullRequestNumber != 7 || len(created.AssignedReviewers) != 2 {
+		t.Fatalf("got pull request %+v", created)
+	}
+
+	for _, prefix := range []string{"/v2/pull-requests/" + created.PullRequestID, "/v2/repositories/api/pull-requests/7"} {
+		t.Run(prefix, func(t *testing.T) {
+			resp := api.must(t, http.StatusOK, testRequest{method: "GET", path: prefix, token: testAdminToken})
+			pr := decode[PRResponse](t, resp)
+			if pr.PullRequestID != created.PullRequestID || resp.header.Get("ETag") == "" {
+				t.Errorf("got pull request %+v with ETag %q", pr, resp.header.Get("ETag"))
+			}
+
+			reviewers := decode[[]UserResponse](t, api.must(t, http.StatusOK, testRequest{
+				method: "GET", path: prefix + "/reviewers", token: testAdminToken}))
+			if len(reviewers) != len(pr.AssignedReviewers) {
+				t.Errorf("got reviewers %+v, want %v", reviewers, pr.AssignedReviewers)
+			}
+		})
+	}
+
+	prefix := "/v2/pull-requests/" + created.PullRequestID
+	old := created.AssignedReviewers[0]
+	reassigned := decode[ReassignPRResponse](t, api.must(t, http.StatusOK, testRequest{
+		method: "POST", path: prefix + "/reviewers/" + old + "/reassign", token: testAdminToken}))
+	if reassigned.ReplacedBy == "" || slices.Contains(reassigned.PR.AssignedReviewers, old) ||
+		!slices.Contains(reassigned.PR.AssignedReviewers, reassigned.ReplacedBy) {
+		t.Errorf("reassigning %s gave %+v", old, reassigned)
+	}
+	api.must(t, http.StatusConflict, testRequest{
+		method: "POST", path: prefix + "/reviewers/" + old + "/reassign", token: testAdminToken})
+	api.must(t, http.StatusNotFound, testRequest{
+		method: "DELETE", path: prefix + "/reviewers/" + reassigned.ReplacedBy, token: testAdminToken})
+
+	approved := decode[PRResponse](t, api.must(t, http.StatusOK, testRequest{
+		method: "PUT", path: "/v2/repositories/api/pull-requests/7/reviewers/" + reassigned.ReplacedBy + "/approval",
+		token: testAdminToken}))
+	if approved.PullRequestID != created.PullRequestID {
+		t.Errorf("approved %+v", approved)
+	}
+
+	api.must(t, http.StatusBadRequest, testRequest{method: "PATCH", path: prefix, token: testAdminToken,
+		body: `{"status":"OPEN"}`})
+	merged := decode[PRResponse](t, api.must(t, http.StatusOK, testRequest{method: "PATCH", path: prefix,
+		token: testAdminToken, body: `{"status":"MERGED"}`}))
+	if merged.Status != "MERGED" {
+		t.Errorf("got status %s, want MERGED", merged.Status)
+	}
+	api.must(t, http.StatusConflict, testRequest{
+		method: "POST", path: prefix + "/reviewers/" + reassigned.ReplacedBy + "/reassign", token: testAdminToken})
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type Scheduler struct {
+	log     *slog.Logger
+	cron    *cron.Cron
+	timeout time.Duration
+	ctx     context.Context
+}
+
+func New(log *slog.Logger, timeout time.Duration) *Scheduler {
+	return &Scheduler{
+		log:     log,
+		cron:    cron.New(),
+		timeout: timeout,
+		ctx:     context.Background(),
+	}
+}
+
+func (s *Scheduler) AddJob(name, spec string, job func(context.Context) error) error {
+	_, err := s.cron.AddFunc(spec, func() {
+		ctx, cancel := context.WithTimeout(s.ctx, s.timeout)
+		defer cancel()
+
+		s.log.Debug("running scheduled job", "job", name)
+		if err := job(ctx); err != nil {
+			s.log.Error("scheduled job failed", "job", name, "error", err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for job %s: %w", spec, name, err)
+	}
+
+	s.log.Info("scheduled job", "job", name, "schedule", spec)
+	return nil
+}
+
+// Start runs the jobs on their schedules. Every run is bound to ctx, so
+// cancelling it aborts the jobs that are still running.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.ctx = ctx
+	s.cron.Start()
+}
+
+func (s *Scheduler) Stop() {
+	<-s.cron.Stop().Done()
+}
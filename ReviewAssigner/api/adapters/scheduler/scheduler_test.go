@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestScheduler(timeout time.Duration) *Scheduler {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)), timeout)
+}
+
+func TestAddJobInvalidSchedule(t *testing.T) {
+	s := newTestScheduler(time.Minute)
+
+	err := s.AddJob("reminders", "every morning", func(context.Context) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "reminders") {
+		t.Fatalf("got error %v, want an invalid schedule error naming the job", err)
+	}
+}
+
+func TestJobRunsWithTimeout(t *testing.T) {
+	s := newTestScheduler(time.Minute)
+
+	runs := make(chan time.Duration, 10)
+	err := s.AddJob("reminders", "@every 1s", func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			runs <- 0
+		} else {
+			runs <- time.Until(deadline)
+		}
+		// A failing run is logged and does not stop the schedule.
+		return errors.New("smtp is down")
+	})
+	if err != nil {
+		t.Fatalf("add job: %v", err)
+	}
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	for range 2 {
+		select {
+		case left := <-runs:
+			if left <= 0 || left > time.Minute {
+				t.Fatalf("job ran with %v left until the deadline, want at most %v", left, time.Minute)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("job did not run")
+		}
+	}
+}
+
+func TestJobCancelledWithStartContext(t *testing.T) {
+	s := newTestScheduler(time.Minute)
+
+	started, stopped := make(chan struct{}, 10), make(chan error, 10)
+	err := s.AddJob("escalations", "@every 1s", func(ctx context.Context) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("add job: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	defer s.Stop()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not run")
+	}
+	cancel()
+
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("job stopped with %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("job was not cancelled")
+	}
+}
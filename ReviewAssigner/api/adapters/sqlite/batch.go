@@ -0,0 +1,168 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"review-assigner/core"
+)
+
+func (db *DB) GetTeams(ctx context.Context, teamNames []string) ([]core.Team, error) {
+	in, args := inList(teamNames, 1)
+	args = append([]any{tenant(ctx)}, args...)
+
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT name, lead_id FROM teams WHERE tenant = $1 AND name IN `+in+` ORDER BY name`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []core.Team
+	index := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			team   core.Team
+			leadID sql.NullString
+		)
+		if err = rows.Scan(&team.TeamName, &leadID); err != nil {
+			return nil, fmt.Errorf("failed to scan team: %w", err)
+		}
+		team.LeadID = leadID.String
+		index[team.TeamName] = len(teams)
+		teams = append(teams, team)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating teams: %w", err)
+	}
+	rows.Close()
+
+	memberRows, err := db.conn.QueryContext(ctx,
+		`SELECT team_name, id, name, active FROM users WHERE tenant = $1 AND team_name IN `+in+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query team members: %w", err)
+	}
+	defer memberRows.Close()
+
+	for memberRows.Next() {
+		var (
+			teamName string
+			member   core.TeamMember
+		)
+		if err = memberRows.Scan(&teamName, &member.UserID, &member.Username, &member.IsActive); err != nil {
+			return nil, fmt.Errorf("failed to scan team member: %w", err)
+		}
+		if i, ok := index[teamName]; ok {
+			teams[i].Members = append(teams[i].Members, member)
+		}
+	}
+
+	if err = memberRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating team members: %w", err)
+	}
+
+	return teams, nil
+}
+
+func (db *DB) GetUsers(ctx context.Context, userIds []string) ([]core.User, error) {
+	in, args := inList(userIds, 1)
+	args = append([]any{tenant(ctx)}, args...)
+
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT `+userColumns+` FROM users WHERE tenant = $1 AND id IN `+in+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []core.User
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	return users, nil
+}
+
+func (db *DB) GetPRs(ctx context.Context, prIds []string) ([]core.PullRequest, error) {
+	in, args := inList(prIds, 1)
+	args = append([]any{tenant(ctx)}, args...)
+
+	return db.queryPRs(ctx,
+		`SELECT `+prColumns+` FROM pull_request pr WHERE pr.tenant = $1 AND pr.id IN `+in+` ORDER BY pr.created_at, pr.id`,
+		args...)
+}
+
+func (db *DB) GetReviewsByUsers(ctx context.Context, userIds []string) (map[string][]core.PullRequest, error) {
+	in, args := inList(userIds, 1)
+	args = append([]any{tenant(ctx)}, args...)
+
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT pr_reviewers.reviewer_id, `+prColumns+`
+         FROM pull_request pr
+         JOIN pr_reviewers ON pr_reviewers.tenant = pr.tenant AND pr.id = pr_reviewers.pr_id
+         WHERE pr.tenant = $1 AND pr_reviewers.reviewer_id IN `+in+`
+         ORDER BY pr.created_at, pr.id`,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := make(map[string][]core.PullRequest)
+
+	for rows.Next() {
+		var reviewerID string
+		pullRequest, err := scanPR(rows, &reviewerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		reviews[reviewerID] = append(reviews[reviewerID], pullRequest)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return reviews, nil
+}
+
+func (db *DB) GetAssignments(ctx context.Context, prIds []string) (map[string][]core.Assignment, error) {
+	in, args := inList(prIds, 1)
+	args = append([]any{tenant(ctx)}, args...)
+
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT pr_id, reviewer_id, approved_at FROM pr_reviewers WHERE tenant = $1 AND pr_id IN `+in+` ORDER BY id`,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := make(map[string][]core.Assignment)
+
+	for rows.Next() {
+		var assignment core.Assignment
+		err = rows.Scan(&assignment.PullRequestID, &assignment.ReviewerID, timeColumn{&assignment.ApprovedAt})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		assignments[assignment.PullRequestID] = append(assignments[assignment.PullRequestID], assignment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assignments: %w", err)
+	}
+
+	return assignments, nil
+}
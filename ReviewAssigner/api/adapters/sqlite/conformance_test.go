@@ -0,0 +1,23 @@
+package sqlite
+
+import (
+	"io"
+	"log/slog"
+	"review-assigner/core"
+	"review-assigner/core/conformance"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) core.DB {
+		db, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), "sqlite://:memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Migrate(); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		return db
+	})
+}
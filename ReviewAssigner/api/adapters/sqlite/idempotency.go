@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"review-assigner/core"
+	"time"
+)
+
+func (db *DB) ReserveIdempotencyKey(ctx context.Context, record core.IdempotencyRecord) (core.IdempotencyRecord, bool, error) {
+	var (
+		scope string
+		now   = timestamp(time.Now())
+	)
+
+	// An expired key is taken over as if it had never been used.
+	err := db.conn.QueryRowContext(ctx,
+		`INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, created_at, expires_at)
+         VALUES ($1, $2, $3, $4, $5)
+         ON CONFLICT (scope, idempotency_key) DO UPDATE
+         SET request_hash = excluded.request_hash, status = 0, headers = '{}', body = NULL,
+             created_at = excluded.created_at, expires_at = excluded.expires_at
+         WHERE idempotency_keys.expires_at < $4
+         RETURNING scope`,
+		record.Scope, record.Key, record.RequestHash, now, timestamp(record.ExpiresAt),
+	).Scan(&scope)
+	if err == nil {
+		return record, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return core.IdempotencyRecord{}, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	var (
+		existing  core.IdempotencyRecord
+		headers   string
+		body      sql.NullString
+		expiresAt *time.Time
+	)
+	err = db.conn.QueryRowContext(ctx,
+		`SELECT scope, idempotency_key, request_hash, status, headers, body, expires_at
+         FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2`,
+		record.Scope, record.Key,
+	).Scan(&existing.Scope, &existing.Key, &existing.RequestHash, &existing.Status, &headers, &body, timeColumn{&expiresAt})
+	if err != nil {
+		return core.IdempotencyRecord{}, false, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(headers), &existing.Headers); err != nil {
+		return core.IdempotencyRecord{}, false, fmt.Errorf("failed to decode stored headers: %w", err)
+	}
+	existing.Body = []byte(body.String)
+	existing.ExpiresAt = *expiresAt
+
+	return existing, false, nil
+}
+
+func (db *DB) CompleteIdempotencyKey(ctx context.Context, record core.IdempotencyRecord) error {
+	headers, err := json.Marshal(record.Headers)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.conn.ExecContext(ctx,
+		`UPDATE idempotency_keys SET request_hash = $1, status = $2, headers = $3, body = $4
+         WHERE scope = $5 AND idempotency_key = $6`,
+		record.RequestHash, record.Status, string(headers), string(record.Body), record.Scope, record.Key)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+func (db *DB) ReleaseIdempotencyKey(ctx context.Context, scope string, key string) error {
+	_, err := db.conn.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE scope = $1 AND idempotency_key = $2`, scope, key)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (db *DB) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	result, err := db.conn.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at < $1`, timestamp(now))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"review-assigner/core"
+	"strings"
+)
+
+func (db *DB) ListPRs(ctx context.Context, filter core.PRFilter) ([]core.PullRequest, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions = append(conditions, "pr.tenant = "+arg(tenant(ctx)))
+	if filter.ReviewerID != "" {
+		conditions = append(conditions,
+			"EXISTS (SELECT 1 FROM pr_reviewers r WHERE r.tenant = pr.tenant AND r.pr_id = pr.id AND r.reviewer_id = "+
+				arg(filter.ReviewerID)+")")
+	}
+	if filter.AuthorID != "" {
+		conditions = append(conditions, "pr.author_id = "+arg(filter.AuthorID))
+	}
+	if filter.Repository != "" {
+		conditions = append(conditions, "pr.repository = "+arg(filter.Repository))
+	}
+	if filter.TeamName != "" {
+		conditions = append(conditions, "u.team_name = "+arg(filter.TeamName))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "pr.state = "+arg(filter.Status))
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "pr.created_at >= "+arg(timestamp(*filter.CreatedFrom)))
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "pr.created_at < "+arg(timestamp(*filter.CreatedTo)))
+	}
+
+	direction, compare := "ASC", ">"
+	if filter.Descending {
+		direction, compare = "DESC", "<"
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(pr.created_at, pr.id) %s (%s, %s)",
+			compare, arg(timestamp(filter.After.CreatedAt)), arg(filter.After.ID)))
+	}
+
+	query := `SELECT ` + prColumns + `
+         FROM pull_request pr
+         JOIN users u ON u.tenant = pr.tenant AND u.id = pr.author_id
+         WHERE ` + strings.Join(conditions, " AND ")
+	query += fmt.Sprintf("\n         ORDER BY pr.created_at %s, pr.id %s LIMIT %s", direction, direction, arg(filter.Limit))
+
+	return db.queryPRs(ctx, query, args...)
+}
@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"embed"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+func (db *DB) Migrations() (source.Driver, error) {
+	return iofs.New(migrationFiles, "migrations")
+}
+
+func (db *DB) Migrator() (*migrate.Migrate, error) {
+	files, err := db.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	driver, err := sqlite.WithInstance(db.pool.DB, &sqlite.Config{})
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewWithInstance("iofs", files, "sqlite", driver)
+}
+
+// Migrate applies pending migrations. A dirty schema is left for the migrate
+// command to fix and is reported as migrate.ErrDirty.
+func (db *DB) Migrate() error {
+	db.log.Debug("running migration")
+	m, err := db.Migrator()
+	if err != nil {
+		return err
+	}
+
+	err = m.Up()
+
+	if err != nil {
+		if err != migrate.ErrNoChange {
+			db.log.Error("migration failed", "error", err)
+			return err
+		}
+		db.log.Debug("migration did not change anything")
+	}
+
+	db.log.Debug("migration finished")
+	return nil
+}
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS api_tokens;
+DROP TABLE IF EXISTS pr_history;
+DROP TABLE IF EXISTS pr_reviewers;
+DROP TABLE IF EXISTS pull_request;
+UPDATE teams SET lead_id = NULL;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS teams;
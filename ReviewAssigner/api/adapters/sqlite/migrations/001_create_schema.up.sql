@@ -0,0 +1,74 @@
+CREATE TABLE IF NOT EXISTS teams (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    lead_id TEXT REFERENCES users(id)
+    );
+
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT NOT NULL PRIMARY KEY,
+    name TEXT NOT NULL,
+    team_name TEXT NOT NULL REFERENCES teams(name),
+    active BOOLEAN NOT NULL DEFAULT 1,
+    out_of_office_until TEXT
+    );
+
+CREATE INDEX IF NOT EXISTS users_team_name_idx ON users (team_name);
+
+CREATE TABLE IF NOT EXISTS pull_request (
+    id TEXT NOT NULL PRIMARY KEY,
+    title TEXT NOT NULL,
+    author_id TEXT NOT NULL REFERENCES users(id),
+    state TEXT NOT NULL DEFAULT 'OPEN' CHECK (state IN ('OPEN', 'MERGED', 'CLOSED')),
+    created_at TEXT NOT NULL,
+    merged_at TEXT,
+    version INTEGER NOT NULL DEFAULT 1
+    );
+
+CREATE INDEX IF NOT EXISTS pull_request_created_at_idx ON pull_request (created_at, id);
+CREATE INDEX IF NOT EXISTS pull_request_author_created_at_idx ON pull_request (author_id, created_at, id);
+CREATE INDEX IF NOT EXISTS pull_request_state_created_at_idx ON pull_request (state, created_at, id);
+
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+    id INTEGER PRIMARY KEY,
+    pr_id TEXT NOT NULL REFERENCES pull_request(id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL REFERENCES users(id),
+    approved_at TEXT,
+    UNIQUE (pr_id, reviewer_id)
+    );
+
+CREATE INDEX IF NOT EXISTS pr_reviewers_reviewer_id_idx ON pr_reviewers (reviewer_id, pr_id);
+
+CREATE TABLE IF NOT EXISTS pr_history (
+    id INTEGER PRIMARY KEY,
+    pr_id TEXT NOT NULL REFERENCES pull_request(id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    actor_id TEXT,
+    details TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+    );
+
+CREATE INDEX IF NOT EXISTS pr_history_pr_id_idx ON pr_history (pr_id, created_at);
+
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    user_id TEXT REFERENCES users(id) ON DELETE CASCADE,
+    scopes TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TEXT NOT NULL,
+    last_used_at TEXT
+    );
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope TEXT NOT NULL,
+    idempotency_key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    headers TEXT NOT NULL DEFAULT '{}',
+    body TEXT,
+    created_at TEXT NOT NULL,
+    expires_at TEXT NOT NULL,
+    PRIMARY KEY (scope, idempotency_key)
+    );
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,86 @@
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TABLE teams_old (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    lead_id TEXT REFERENCES users_old(id)
+    );
+
+CREATE TABLE users_old (
+    id TEXT NOT NULL PRIMARY KEY,
+    name TEXT NOT NULL,
+    team_name TEXT NOT NULL REFERENCES teams_old(name),
+    active BOOLEAN NOT NULL DEFAULT 1,
+    out_of_office_until TEXT
+    );
+
+CREATE TABLE pull_request_old (
+    id TEXT NOT NULL PRIMARY KEY,
+    title TEXT NOT NULL,
+    author_id TEXT NOT NULL REFERENCES users_old(id),
+    state TEXT NOT NULL DEFAULT 'OPEN' CHECK (state IN ('OPEN', 'MERGED', 'CLOSED')),
+    created_at TEXT NOT NULL,
+    merged_at TEXT,
+    version INTEGER NOT NULL DEFAULT 1
+    );
+
+CREATE TABLE pr_reviewers_old (
+    id INTEGER PRIMARY KEY,
+    pr_id TEXT NOT NULL REFERENCES pull_request_old(id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL REFERENCES users_old(id),
+    approved_at TEXT,
+    UNIQUE (pr_id, reviewer_id)
+    );
+
+CREATE TABLE pr_history_old (
+    id INTEGER PRIMARY KEY,
+    pr_id TEXT NOT NULL REFERENCES pull_request_old(id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    actor_id TEXT,
+    details TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+    );
+
+CREATE TABLE api_tokens_old (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    user_id TEXT REFERENCES users_old(id) ON DELETE CASCADE,
+    scopes TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TEXT NOT NULL,
+    last_used_at TEXT
+    );
+
+INSERT INTO teams_old (id, name, lead_id) SELECT id, name, lead_id FROM teams WHERE tenant = 'default';
+INSERT INTO users_old (id, name, team_name, active, out_of_office_until)
+    SELECT id, name, team_name, active, out_of_office_until FROM users WHERE tenant = 'default';
+INSERT INTO pull_request_old (id, title, author_id, state, created_at, merged_at, version)
+    SELECT id, title, author_id, state, created_at, merged_at, version FROM pull_request WHERE tenant = 'default';
+INSERT INTO pr_reviewers_old (id, pr_id, reviewer_id, approved_at)
+    SELECT id, pr_id, reviewer_id, approved_at FROM pr_reviewers WHERE tenant = 'default';
+INSERT INTO pr_history_old (id, pr_id, event, actor_id, details, created_at)
+    SELECT id, pr_id, event, actor_id, details, created_at FROM pr_history WHERE tenant = 'default';
+INSERT INTO api_tokens_old (id, name, user_id, scopes, token_hash, created_at, last_used_at)
+    SELECT id, name, user_id, scopes, token_hash, created_at, last_used_at FROM api_tokens WHERE tenant = 'default';
+
+DROP TABLE api_tokens;
+DROP TABLE pr_history;
+DROP TABLE pr_reviewers;
+DROP TABLE pull_request;
+UPDATE teams SET lead_id = NULL;
+DROP TABLE users;
+DROP TABLE teams;
+
+ALTER TABLE teams_old RENAME TO teams;
+ALTER TABLE users_old RENAME TO users;
+ALTER TABLE pull_request_old RENAME TO pull_request;
+ALTER TABLE pr_reviewers_old RENAME TO pr_reviewers;
+ALTER TABLE pr_history_old RENAME TO pr_history;
+ALTER TABLE api_tokens_old RENAME TO api_tokens;
+
+CREATE INDEX IF NOT EXISTS users_team_name_idx ON users (team_name);
+CREATE INDEX IF NOT EXISTS pull_request_created_at_idx ON pull_request (created_at, id);
+CREATE INDEX IF NOT EXISTS pull_request_author_created_at_idx ON pull_request (author_id, created_at, id);
+CREATE INDEX IF NOT EXISTS pull_request_state_created_at_idx ON pull_request (state, created_at, id);
+CREATE INDEX IF NOT EXISTS pr_reviewers_reviewer_id_idx ON pr_reviewers (reviewer_id, pr_id);
+CREATE INDEX IF NOT EXISTS pr_history_pr_id_idx ON pr_history (pr_id, created_at);
@@ -0,0 +1,103 @@
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TABLE teams_new (
+    id INTEGER PRIMARY KEY,
+    tenant TEXT NOT NULL DEFAULT 'default',
+    name TEXT NOT NULL,
+    lead_id TEXT,
+    UNIQUE (tenant, name),
+    FOREIGN KEY (tenant, lead_id) REFERENCES users_new(tenant, id)
+    );
+
+CREATE TABLE users_new (
+    tenant TEXT NOT NULL DEFAULT 'default',
+    id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    team_name TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT 1,
+    out_of_office_until TEXT,
+    PRIMARY KEY (tenant, id),
+    FOREIGN KEY (tenant, team_name) REFERENCES teams_new(tenant, name)
+    );
+
+CREATE TABLE pull_request_new (
+    tenant TEXT NOT NULL DEFAULT 'default',
+    id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    author_id TEXT NOT NULL,
+    state TEXT NOT NULL DEFAULT 'OPEN' CHECK (state IN ('OPEN', 'MERGED', 'CLOSED')),
+    created_at TEXT NOT NULL,
+    merged_at TEXT,
+    version INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (tenant, id),
+    FOREIGN KEY (tenant, author_id) REFERENCES users_new(tenant, id)
+    );
+
+CREATE TABLE pr_reviewers_new (
+    id INTEGER PRIMARY KEY,
+    tenant TEXT NOT NULL DEFAULT 'default',
+    pr_id TEXT NOT NULL,
+    reviewer_id TEXT NOT NULL,
+    approved_at TEXT,
+    UNIQUE (tenant, pr_id, reviewer_id),
+    FOREIGN KEY (tenant, pr_id) REFERENCES pull_request_new(tenant, id) ON DELETE CASCADE,
+    FOREIGN KEY (tenant, reviewer_id) REFERENCES users_new(tenant, id)
+    );
+
+CREATE TABLE pr_history_new (
+    id INTEGER PRIMARY KEY,
+    tenant TEXT NOT NULL DEFAULT 'default',
+    pr_id TEXT NOT NULL,
+    event TEXT NOT NULL,
+    actor_id TEXT,
+    details TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (tenant, pr_id) REFERENCES pull_request_new(tenant, id) ON DELETE CASCADE
+    );
+
+CREATE TABLE api_tokens_new (
+    id INTEGER PRIMARY KEY,
+    tenant TEXT NOT NULL DEFAULT 'default',
+    name TEXT NOT NULL,
+    user_id TEXT,
+    scopes TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at TEXT NOT NULL,
+    last_used_at TEXT,
+    FOREIGN KEY (tenant, user_id) REFERENCES users_new(tenant, id) ON DELETE CASCADE
+    );
+
+INSERT INTO teams_new (id, name, lead_id) SELECT id, name, lead_id FROM teams;
+INSERT INTO users_new (id, name, team_name, active, out_of_office_until)
+    SELECT id, name, team_name, active, out_of_office_until FROM users;
+INSERT INTO pull_request_new (id, title, author_id, state, created_at, merged_at, version)
+    SELECT id, title, author_id, state, created_at, merged_at, version FROM pull_request;
+INSERT INTO pr_reviewers_new (id, pr_id, reviewer_id, approved_at)
+    SELECT id, pr_id, reviewer_id, approved_at FROM pr_reviewers;
+INSERT INTO pr_history_new (id, pr_id, event, actor_id, details, created_at)
+    SELECT id, pr_id, event, actor_id, details, created_at FROM pr_history;
+INSERT INTO api_tokens_new (id, name, user_id, scopes, token_hash, created_at, last_used_at)
+    SELECT id, name, user_id, scopes, token_hash, created_at, last_used_at FROM api_tokens;
+
+DROP TABLE api_tokens;
+DROP TABLE pr_history;
+DROP TABLE pr_reviewers;
+DROP TABLE pull_request;
+UPDATE teams SET lead_id = NULL;
+DROP TABLE users;
+DROP TABLE teams;
+
+ALTER TABLE teams_new RENAME TO teams;
+ALTER TABLE users_new RENAME TO users;
+ALTER TABLE pull_request_new RENAME TO pull_request;
+ALTER TABLE pr_reviewers_new RENAME TO pr_reviewers;
+ALTER TABLE pr_history_new RENAME TO pr_history;
+ALTER TABLE api_tokens_new RENAME TO api_tokens;
+
+CREATE INDEX IF NOT EXISTS users_team_name_idx ON users (tenant, team_name);
+CREATE INDEX IF NOT EXISTS pull_request_created_at_idx ON pull_request (tenant, created_at, id);
+CREATE INDEX IF NOT EXISTS pull_request_author_created_at_idx ON pull_request (tenant, author_id, created_at, id);
+CREATE INDEX IF NOT EXISTS pull_request_state_created_at_idx ON pull_request (tenant, state, created_at, id);
+CREATE INDEX IF NOT EXISTS pr_reviewers_reviewer_id_idx ON pr_reviewers (tenant, reviewer_id, pr_id);
+CREATE INDEX IF NOT EXISTS pr_history_pr_id_idx ON pr_history (tenant, pr_id, created_at);
+CREATE INDEX IF NOT EXISTS api_tokens_tenant_idx ON api_tokens (tenant, id);
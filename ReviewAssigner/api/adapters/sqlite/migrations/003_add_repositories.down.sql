@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS pull_request_repository_number_idx;
+
+ALTER TABLE pull_request DROP COLUMN number;
+ALTER TABLE pull_request DROP COLUMN repository;
+
+DROP TABLE IF EXISTS repository_teams;
+DROP TABLE IF EXISTS repositories;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS repositories (
+    tenant TEXT NOT NULL DEFAULT 'default',
+    name TEXT NOT NULL,
+    PRIMARY KEY (tenant, name)
+    );
+
+CREATE TABLE IF NOT EXISTS repository_teams (
+    tenant TEXT NOT NULL DEFAULT 'default',
+    repository TEXT NOT NULL,
+    team_name TEXT NOT NULL,
+    PRIMARY KEY (tenant, repository, team_name),
+    FOREIGN KEY (tenant, repository) REFERENCES repositories(tenant, name) ON DELETE CASCADE,
+    FOREIGN KEY (tenant, team_name) REFERENCES teams(tenant, name) ON DELETE CASCADE
+    );
+
+-- SQLite cannot drop a column that takes part in a foreign key, so the
+-- repository of a pull request is checked by the service only.
+ALTER TABLE pull_request ADD COLUMN repository TEXT;
+ALTER TABLE pull_request ADD COLUMN number INTEGER CHECK (number IS NULL OR number > 0);
+
+CREATE UNIQUE INDEX IF NOT EXISTS pull_request_repository_number_idx ON pull_request (tenant, repository, number);
@@ -0,0 +1,5 @@
+ALTER TABLE pull_request DROP COLUMN priority;
+ALTER TABLE pull_request DROP COLUMN files_changed;
+ALTER TABLE pull_request DROP COLUMN lines_removed;
+ALTER TABLE pull_request DROP COLUMN lines_added;
+ALTER TABLE pull_request DROP COLUMN labels;
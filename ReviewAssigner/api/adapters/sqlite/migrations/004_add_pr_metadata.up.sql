@@ -0,0 +1,7 @@
+-- Labels are stored space separated, like token scopes.
+ALTER TABLE pull_request ADD COLUMN labels TEXT NOT NULL DEFAULT '';
+ALTER TABLE pull_request ADD COLUMN lines_added INTEGER NOT NULL DEFAULT 0 CHECK (lines_added >= 0);
+ALTER TABLE pull_request ADD COLUMN lines_removed INTEGER NOT NULL DEFAULT 0 CHECK (lines_removed >= 0);
+ALTER TABLE pull_request ADD COLUMN files_changed INTEGER NOT NULL DEFAULT 0 CHECK (files_changed >= 0);
+ALTER TABLE pull_request ADD COLUMN priority TEXT NOT NULL DEFAULT 'NORMAL'
+    CHECK (priority IN ('LOW', 'NORMAL', 'HIGH', 'URGENT'));
@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"review-assigner/core"
+	"testing"
+)
+
+// TestMigrationsRoundTrip rolls the schema back past tenants with the same
+// ids in two tenants, then migrates up again.
+func TestMigrationsRoundTrip(t *testing.T) {
+	db, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), "sqlite://:memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := db.Migrator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	ctx := context.Background()
+	for _, tenant := range []string{core.DefaultTenant, "acme"} {
+		team := core.Team{TeamName: "backend", LeadID: "u1", Members: []core.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+		}}
+		if err := db.AddTeam(core.WithTenant(ctx, tenant), team); err != nil {
+			t.Fatalf("add team to %s: %v", tenant, err)
+		}
+	}
+
+	if err := m.Migrate(1); err != nil {
+		t.Fatalf("down to 1: %v", err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("up again: %v", err)
+	}
+
+	team, err := db.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("default tenant lost its team: %v", err)
+	}
+	if team.LeadID != "u1" || len(team.Members) != 2 {
+		t.Errorf("got team %+v after the round trip", team)
+	}
+
+	if err := m.Down(); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if err := m.Up(); err != nil {
+		t.Fatalf("up from scratch: %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"review-assigner/core"
+	"strings"
+	"time"
+)
+
+func (db *DB) GetTeamNames(ctx context.Context) ([]string, error) {
+	var names []string
+	rows, err := db.conn.QueryContext(ctx, `SELECT name FROM teams WHERE tenant = $1 ORDER BY name`, tenant(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query teams: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan team: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating teams: %w", err)
+	}
+
+	return names, nil
+}
+
+// GetTenants lists every tenant that owns at least one team, for jobs that
+// run outside of a request.
+func (db *DB) GetTenants(ctx context.Context) ([]string, error) {
+	var tenants []string
+	rows, err := db.conn.QueryContext(ctx, `SELECT DISTINCT tenant FROM teams ORDER BY tenant`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenants: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenants = append(tenants, name)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tenants: %w", err)
+	}
+
+	return tenants, nil
+}
+
+func (db *DB) ImportTeam(ctx context.Context, teamName string) error {
+	_, err := db.conn.ExecContext(ctx, `INSERT INTO teams (tenant, name) VALUES ($1, $2) ON CONFLICT (tenant, name) DO NOTHING`,
+		tenant(ctx), teamName)
+	if err != nil {
+		return fmt.Errorf("failed to import team: %w", err)
+	}
+
+	return nil
+}
+
+func (db *DB) ImportUser(ctx context.Context, user core.User) error {
+	_, err := db.conn.ExecContext(ctx,
+		`INSERT INTO users (tenant, id, name, team_name, active, out_of_office_until) VALUES ($1, $2, $3, $4, $5, $6)
+         ON CONFLICT (tenant, id) DO UPDATE
+         SET name = excluded.name, team_name = excluded.team_name, active = excluded.active,
+             out_of_office_until = excluded.out_of_office_until`,
+		tenant(ctx), user.UserID, user.Username, user.TeamName, user.IsActive, nullTimestamp(user.OutOfOfficeUntil))
+	if err != nil {
+		return fmt.Errorf("failed to import user: %w", err)
+	}
+
+	return nil
+}
+
+// importTimestamp converts an RFC 3339 time from a snapshot to the stored
+// layout, using the current time when it is missing.
+func importTimestamp(value *string) (string, error) {
+	if value == nil {
+		return timestamp(time.Now()), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, *value)
+	if err != nil {
+		return "", fmt.Errorf("invalid timestamp %q: %w", *value, err)
+	}
+	return timestamp(t), nil
+}
+
+// ImportPR replaces the pull request together with its reviewers and history.
+func (db *DB) ImportPR(ctx context.Context, snapshot core.PRSnapshot) error {
+	pullRequest := snapshot.PullRequest
+
+	createdAt, err := importTimestamp(pullRequest.CreatedAt)
+	if err != nil {
+		return err
+	}
+	mergedAt := sql.NullString{}
+	if pullRequest.MergedAt != nil {
+		mergedAt.String, err = importTimestamp(pullRequest.MergedAt)
+		if err != nil {
+			return err
+		}
+		mergedAt.Valid = true
+	}
+
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO pull_request (tenant, id, title, author_id, state, created_at, merged_at, version, repository, number,
+                                   labels, lines_added, lines_removed, files_changed, priority)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), NULLIF($10, 0), $11, $12, $13, $14,
+                 COALESCE(NULLIF($15, ''), 'NORMAL'))
+         ON CONFLICT (tenant, id) DO UPDATE
+         SET title = excluded.title, author_id = excluded.author_id, state = excluded.state,
+             created_at = excluded.created_at, merged_at = excluded.merged_at, version = excluded.version,
+             repository = excluded.repository, number = excluded.number, labels = excluded.labels,
+             lines_added = excluded.lines_added, lines_removed = excluded.lines_removed,
+             files_changed = excluded.files_changed, priority = excluded.priority`,
+			tenant(ctx), pullRequest.PullRequestID, pullRequest.PullRequestName, pullRequest.AuthorID, pullRequest.Status,
+			createdAt, mergedAt, pullRequest.Version, pullRequest.Repository, pullRequest.Number,
+			strings.Join(pullRequest.Labels, " "), pullRequest.LinesAdded, pullRequest.LinesRemoved,
+			pullRequest.FilesChanged, pullRequest.Priority)
+		if err != nil {
+			if isUniqueConstraintError(err) {
+				return fmt.Errorf("%w: pull request %q reuses number %d of repository %q", core.ErrInvalidSnapshot,
+					pullRequest.PullRequestID, pullRequest.Number, pullRequest.Repository)
+			}
+			return fmt.Errorf("failed to import pr: %w", err)
+		}
+
+		if _, err = tx.ExecContext(ctx, `DELETE FROM pr_reviewers WHERE tenant = $1 AND pr_id = $2`,
+			tenant(ctx), pullRequest.PullRequestID); err != nil {
+			return fmt.Errorf("failed to clear reviewers: %w", err)
+		}
+		for _, reviewer := range snapshot.Reviewers {
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO pr_reviewers (tenant, pr_id, reviewer_id, approved_at) VALUES ($1, $2, $3, $4)`,
+				tenant(ctx), pullRequest.PullRequestID, reviewer.ReviewerID, nullTimestamp(reviewer.ApprovedAt))
+			if err != nil {
+				return fmt.Errorf("failed to import reviewer: %w", err)
+			}
+		}
+
+		if _, err = tx.ExecContext(ctx, `DELETE FROM pr_history WHERE tenant = $1 AND pr_id = $2`,
+			tenant(ctx), pullRequest.PullRequestID); err != nil {
+			return fmt.Errorf("failed to clear pr history: %w", err)
+		}
+		for _, entry := range snapshot.History {
+			createdAt := entry.CreatedAt
+			if createdAt.IsZero() {
+				createdAt = time.Now()
+			}
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO pr_history (tenant, pr_id, event, actor_id, details, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+				tenant(ctx), pullRequest.PullRequestID, string(entry.Event),
+				sql.NullString{String: entry.ActorID, Valid: entry.ActorID != ""}, entry.Details, timestamp(createdAt))
+			if err != nil {
+				return fmt.Errorf("failed to import pr history: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,733 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"log/slog"
+	"review-assigner/core"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const Scheme = "sqlite:"
+
+// Timestamps are stored as fixed-width UTC text so that they sort and
+// compare correctly as strings.
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+var memoryDatabases atomic.Int64
+
+type DB struct {
+	log  *slog.Logger
+	pool *sqlx.DB
+	conn executor
+	tx   *sql.Tx
+}
+
+// New opens the database file named by an address like sqlite://review.db,
+// sqlite:///var/lib/review.db or sqlite://:memory:.
+func New(log *slog.Logger, address string) (*DB, error) {
+	path := strings.TrimPrefix(strings.TrimPrefix(address, Scheme), "//")
+
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+	if path == ":memory:" {
+		// memdb keeps one database for every connection of the pool, unlike
+		// plain :memory: which gives each connection its own.
+		dsn = fmt.Sprintf("file:/review-assigner-%d?vfs=memdb&_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_txlock=immediate",
+			memoryDatabases.Add(1))
+	}
+
+	db, err := sqlx.Connect("sqlite", dsn)
+	if err != nil {
+		log.Error("connection problem", "address", address, "error", err)
+		return nil, err
+	}
+	// The in-memory database is gone once its last connection closes.
+	db.SetConnMaxIdleTime(0)
+	db.SetConnMaxLifetime(0)
+
+	return &DB{
+		log:  log,
+		pool: db,
+		conn: db,
+	}, nil
+}
+
+func (db *DB) Close() error {
+	return db.pool.Close()
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func nullTimestamp(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: timestamp(*t), Valid: true}
+}
+
+func parseTimestamp(value sql.NullString) (*time.Time, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value.String)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp %q: %w", value.String, err)
+	}
+	return &t, nil
+}
+
+func formatTime(value sql.NullString) (*string, error) {
+	t, err := parseTimestamp(value)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	formatted := t.UTC().Format(time.RFC3339Nano)
+	return &formatted, nil
+}
+
+// timeColumn scans a TEXT timestamp into a *time.Time.
+type timeColumn struct {
+	dest **time.Time
+}
+
+func (c timeColumn) Scan(src any) error {
+	var value sql.NullString
+	if err := value.Scan(src); err != nil {
+		return err
+	}
+	t, err := parseTimestamp(value)
+	if err != nil {
+		return err
+	}
+	*c.dest = t
+	return nil
+}
+
+// tenant scopes every query to the organisation the request belongs to.
+func tenant(ctx context.Context) string {
+	return core.TenantFrom(ctx)
+}
+
+func isUniqueConstraintError(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY)
+}
+
+// inList expands ids into a placeholder list for an IN clause, numbering the
+// placeholders after the given number of preceding arguments.
+func inList(ids []string, offset int) (string, []any) {
+	if len(ids) == 0 {
+		return "(NULL)", nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", offset+i+1)
+		args[i] = id
+	}
+	return "(" + strings.Join(placeholders, ", ") + ")", args
+}
+
+func (db *DB) AddUserTX(ctx context.Context, tx *sql.Tx, user core.User) error {
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO users (tenant, id, name, team_name, active) VALUES ($1, $2, $3, $4, $5)",
+		tenant(ctx), user.UserID, user.Username, user.TeamName, user.IsActive)
+
+	return err
+}
+
+func (db *DB) AddTeam(ctx context.Context, team core.Team) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO teams (tenant, name) VALUES ($1, $2)", tenant(ctx), team.TeamName)
+		if err != nil {
+			if isUniqueConstraintError(err) {
+				return core.ErrTeamAlreadyExists
+			}
+			return err
+		}
+
+		for _, member := range team.Members {
+			user := core.User{
+				UserID:   member.UserID,
+				Username: member.Username,
+				TeamName: team.TeamName,
+				IsActive: member.IsActive,
+			}
+			if err = db.AddUserTX(ctx, tx, user); err != nil {
+				return err
+			}
+		}
+
+		if team.LeadID != "" {
+			_, err = tx.ExecContext(ctx, "UPDATE teams SET lead_id = $1 WHERE tenant = $2 AND name = $3",
+				team.LeadID, tenant(ctx), team.TeamName)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (db *DB) AddPR(ctx context.Context, pullRequest core.PullRequest) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO pull_request (tenant, id, repository, number, title, author_id, state, created_at,
+                                   labels, lines_added, lines_removed, files_changed, priority)
+         VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, 0), $5, $6, $7, $8,
+                 $9, $10, $11, $12, COALESCE(NULLIF($13, ''), 'NORMAL'))`,
+			tenant(ctx), pullRequest.PullRequestID, pullRequest.Repository, pullRequest.Number,
+			pullRequest.PullRequestName, pullRequest.AuthorID, "OPEN", timestamp(time.Now()),
+			strings.Join(pullRequest.Labels, " "), pullRequest.LinesAdded, pullRequest.LinesRemoved,
+			pullRequest.FilesChanged, pullRequest.Priority)
+		if err != nil {
+			if isUniqueConstraintError(err) {
+				return core.ErrPRAAlreadyExists
+			}
+			return err
+		}
+
+		for _, reviewer := range pullRequest.AssignedReviewers {
+			_, err = tx.ExecContext(ctx,
+				"INSERT INTO pr_reviewers (tenant, pr_id, reviewer_id) VALUES ($1, $2, $3)",
+				tenant(ctx), pullRequest.PullRequestID, reviewer)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (db *DB) GetTeam(ctx context.Context, teamName string) (core.Team, error) {
+	var (
+		leadID sql.NullString
+		team   core.Team
+	)
+
+	err := db.conn.QueryRowContext(ctx, "SELECT lead_id FROM teams WHERE tenant = $1 AND name = $2", tenant(ctx), teamName).Scan(&leadID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return core.Team{}, core.ErrTeamNotFound
+		}
+		return core.Team{}, err
+	}
+	team.TeamName = teamName
+	team.LeadID = leadID.String
+
+	rows, err := db.conn.QueryContext(ctx,
+		"SELECT id, name, active FROM users WHERE tenant = $1 AND team_name = $2 ORDER BY rowid", tenant(ctx), teamName)
+	if err != nil {
+		return core.Team{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member core.TeamMember
+		if err = rows.Scan(&member.UserID, &member.Username, &member.IsActive); err != nil {
+			return core.Team{}, err
+		}
+		team.Members = append(team.Members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return core.Team{}, err
+	}
+
+	return team, nil
+}
+
+func (db *DB) SetTeamLead(ctx context.Context, teamName string, userId string) error {
+	result, err := db.conn.ExecContext(ctx, "UPDATE teams SET lead_id = NULLIF($1, '') WHERE tenant = $2 AND name = $3",
+		userId, tenant(ctx), teamName)
+	if err != nil {
+		return fmt.Errorf("failed to set team lead: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return core.ErrTeamNotFound
+	}
+
+	return nil
+}
+
+const userColumns = "id, name, team_name, active, out_of_office_until"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row rowScanner) (core.User, error) {
+	var user core.User
+	err := row.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, timeColumn{&user.OutOfOfficeUntil})
+	return user, err
+}
+
+func (db *DB) GetUser(ctx context.Context, userId string) (core.User, error) {
+	user, err := scanUser(db.conn.QueryRowContext(ctx,
+		"SELECT "+userColumns+" FROM users WHERE tenant = $1 AND id = $2", tenant(ctx), userId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return core.User{}, core.ErrUserNotFound
+		}
+		return core.User{}, err
+	}
+
+	return user, nil
+}
+
+func (db *DB) IsActive(ctx context.Context, userId string, status bool) (core.User, error) {
+	user, err := scanUser(db.conn.QueryRowContext(ctx,
+		"UPDATE users SET active = $1 WHERE tenant = $2 AND id = $3 RETURNING "+userColumns,
+		status, tenant(ctx), userId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return core.User{}, core.ErrUserNotFound
+		}
+		return core.User{}, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (db *DB) SetOutOfOffice(ctx context.Context, userId string, until *time.Time) (core.User, error) {
+	user, err := scanUser(db.conn.QueryRowContext(ctx,
+		"UPDATE users SET out_of_office_until = $1 WHERE tenant = $2 AND id = $3 RETURNING "+userColumns,
+		nullTimestamp(until), tenant(ctx), userId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return core.User{}, core.ErrUserNotFound
+		}
+		return core.User{}, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return user, nil
+}
+
+const (
+	prColumns = "pr.id, COALESCE(pr.repository, ''), COALESCE(pr.number, 0), " +
+		"pr.labels, pr.lines_added, pr.lines_removed, pr.files_changed, pr.priority, " +
+		"pr.title, pr.author_id, pr.state, pr.created_at, pr.merged_at, pr.version"
+	prReturning = "id, COALESCE(repository, ''), COALESCE(number, 0), " +
+		"labels, lines_added, lines_removed, files_changed, priority, " +
+		"title, author_id, state, created_at, merged_at, version"
+)
+
+func scanPR(row rowScanner, extra ...any) (core.PullRequest, error) {
+	var (
+		pullRequest         core.PullRequest
+		labels              string
+		createdAt, mergedAt sql.NullString
+	)
+
+	dest := append(extra, &pullRequest.PullRequestID, &pullRequest.Repository, &pullRequest.Number,
+		&labels, &pullRequest.LinesAdded, &pullRequest.LinesRemoved, &pullRequest.FilesChanged, &pullRequest.Priority,
+		&pullRequest.PullRequestName, &pullRequest.AuthorID, &pullRequest.Status, &createdAt, &mergedAt,
+		&pullRequest.Version)
+	if err := row.Scan(dest...); err != nil {
+		return core.PullRequest{}, err
+	}
+	pullRequest.Labels = strings.Fields(labels)
+
+	var err error
+	if pullRequest.CreatedAt, err = formatTime(createdAt); err != nil {
+		return core.PullRequest{}, err
+	}
+	if pullRequest.MergedAt, err = formatTime(mergedAt); err != nil {
+		return core.PullRequest{}, err
+	}
+
+	return pullRequest, nil
+}
+
+func (db *DB) Merged(ctx context.Context, prId string, version int64) (core.PullRequest, error) {
+	pullRequest, err := scanPR(db.conn.QueryRowContext(ctx,
+		`UPDATE pull_request SET state = 'MERGED', merged_at = $1, version = version + 1
+         WHERE tenant = $2 AND id = $3 AND state = 'OPEN' AND version = $4
+         RETURNING `+prReturning,
+		timestamp(time.Now()), tenant(ctx), prId, version))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return core.PullRequest{}, prConflict(ctx, db.conn, prId)
+		}
+		return core.PullRequest{}, fmt.Errorf("failed to update pr: %w", err)
+	}
+
+	pullRequest.AssignedReviewers, err = db.getReviewers(ctx, prId)
+	if err != nil {
+		return core.PullRequest{}, err
+	}
+
+	return pullRequest, nil
+}
+
+func (db *DB) GetPRDetailsWithReviewers(ctx context.Context, prId string) (core.PullRequest, error) {
+	pullRequest, err := scanPR(db.conn.QueryRowContext(ctx,
+		"SELECT "+prColumns+" FROM pull_request pr WHERE pr.tenant = $1 AND pr.id = $2", tenant(ctx), prId))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return core.PullRequest{}, core.ErrPRNotFound
+		}
+		return core.PullRequest{}, fmt.Errorf("failed to get pr: %w", err)
+	}
+
+	pullRequest.AssignedReviewers, err = db.getReviewers(ctx, prId)
+	if err != nil {
+		return core.PullRequest{}, fmt.Errorf("failed to get reviewers: %w", err)
+	}
+
+	return pullRequest, nil
+}
+
+func (db *DB) getReviewers(ctx context.Context, prId string) ([]string, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		"SELECT reviewer_id FROM pr_reviewers WHERE tenant = $1 AND pr_id = $2 ORDER BY id", tenant(ctx), prId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	var reviewers []string
+
+	for rows.Next() {
+		var reviewerID string
+		if err = rows.Scan(&reviewerID); err != nil {
+			return nil, fmt.Errorf("failed to scan reviewer: %w", err)
+		}
+		reviewers = append(reviewers, reviewerID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reviewers: %w", err)
+	}
+
+	return reviewers, nil
+}
+
+// bumpPRVersion advances the version of an open PR. A zero version skips the
+// comparison.
+func bumpPRVersion(ctx context.Context, tx *sql.Tx, prId string, version int64) error {
+	result, err := tx.ExecContext(ctx,
+		`UPDATE pull_request SET version = version + 1
+         WHERE tenant = $1 AND id = $2 AND state = 'OPEN' AND ($3 = 0 OR version = $3)`,
+		tenant(ctx), prId, version)
+	if err != nil {
+		return fmt.Errorf("failed to update pr version: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return prConflict(ctx, tx, prId)
+	}
+
+	return nil
+}
+
+// prConflict tells a PR that does not exist from one that was changed since
+// it was read.
+func prConflict(ctx context.Context, conn executor, prId string) error {
+	var exists bool
+	err := conn.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM pull_request WHERE tenant = $1 AND id = $2)",
+		tenant(ctx), prId).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check pr: %w", err)
+	}
+	if !exists {
+		return core.ErrPRNotFound
+	}
+	return core.ErrPRConflict
+}
+
+func (db *DB) Reassign(ctx context.Context, oldReviewer core.ReassignReviewer, newReviewer string, version int64) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		if err := bumpPRVersion(ctx, tx, oldReviewer.PRId, version); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx,
+			`UPDATE pr_reviewers SET reviewer_id = $1, approved_at = NULL
+         WHERE tenant = $2 AND pr_id = $3 AND reviewer_id = $4`,
+			newReviewer, tenant(ctx), oldReviewer.PRId, oldReviewer.UserID)
+		if err != nil {
+			if isUniqueConstraintError(err) {
+				return core.ErrPRConflict
+			}
+			return fmt.Errorf("failed to reassign reviewer: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return core.ErrReviewerNotAssigned
+		}
+
+		return nil
+	})
+}
+
+func (db *DB) AddReviewer(ctx context.Context, prId string, reviewerId string) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		if err := bumpPRVersion(ctx, tx, prId, 0); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO pr_reviewers (tenant, pr_id, reviewer_id) VALUES ($1, $2, $3)
+         ON CONFLICT (tenant, pr_id, reviewer_id) DO NOTHING`,
+			tenant(ctx), prId, reviewerId)
+		if err != nil {
+			return fmt.Errorf("failed to add reviewer: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (db *DB) Approve(ctx context.Context, prId string, reviewerId string, version int64) error {
+	return db.inTx(ctx, func(tx *sql.Tx) error {
+		if err := bumpPRVersion(ctx, tx, prId, version); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx,
+			`UPDATE pr_reviewers SET approved_at = COALESCE(approved_at, $1)
+         WHERE tenant = $2 AND pr_id = $3 AND reviewer_id = $4`,
+			timestamp(time.Now()), tenant(ctx), prId, reviewerId)
+		if err != nil {
+			return fmt.Errorf("failed to approve pr: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return core.ErrReviewerNotAssigned
+		}
+
+		return nil
+	})
+}
+
+func (db *DB) AddPRHistory(ctx context.Context, entry core.PRHistoryEntry) error {
+	_, err := db.conn.ExecContext(ctx,
+		`INSERT INTO pr_history (tenant, pr_id, event, actor_id, details, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		tenant(ctx), entry.PullRequestID, string(entry.Event), sql.NullString{String: entry.ActorID, Valid: entry.ActorID != ""},
+		entry.Details, timestamp(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to add pr history: %w", err)
+	}
+
+	return nil
+}
+
+func (db *DB) GetPRHistory(ctx context.Context, prId string) ([]core.PRHistoryEntry, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT pr_id, event, actor_id, details, created_at FROM pr_history WHERE tenant = $1 AND pr_id = $2 ORDER BY created_at, id`,
+		tenant(ctx), prId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pr history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []core.PRHistoryEntry
+
+	for rows.Next() {
+		var (
+			entry     core.PRHistoryEntry
+			actorID   sql.NullString
+			createdAt *time.Time
+		)
+		if err = rows.Scan(&entry.PullRequestID, &entry.Event, &actorID, &entry.Details, timeColumn{&createdAt}); err != nil {
+			return nil, fmt.Errorf("failed to scan pr history: %w", err)
+		}
+		entry.ActorID = actorID.String
+		entry.CreatedAt = *createdAt
+		history = append(history, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pr history: %w", err)
+	}
+
+	return history, nil
+}
+
+// queryPRs runs a query selecting prColumns and fills in the reviewers of
+// every PR it returns.
+func (db *DB) queryPRs(ctx context.Context, query string, args ...any) ([]core.PullRequest, error) {
+	rows, err := db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prs: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		pullRequests []core.PullRequest
+		prIds        []string
+	)
+
+	for rows.Next() {
+		pullRequest, err := scanPR(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pr: %w", err)
+		}
+		pullRequests = append(pullRequests, pullRequest)
+		prIds = append(prIds, pullRequest.PullRequestID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating prs: %w", err)
+	}
+	rows.Close()
+
+	assignments, err := db.GetAssignments(ctx, prIds)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pullRequests {
+		for _, assignment := range assignments[pullRequests[i].PullRequestID] {
+			pullRequests[i].AssignedReviewers = append(pullRequests[i].AssignedReviewers, assignment.ReviewerID)
+		}
+	}
+
+	return pullRequests, nil
+}
+
+func (db *DB) GetStalePRs(ctx context.Context, createdBefore time.Time) ([]core.PullRequest, error) {
+	return db.queryPRs(ctx,
+		`SELECT `+prColumns+`
+         FROM pull_request pr
+         WHERE pr.tenant = $1 AND pr.state = 'OPEN' AND pr.created_at < $2
+           AND NOT EXISTS (SELECT 1 FROM pr_reviewers r
+                           WHERE r.tenant = pr.tenant AND r.pr_id = pr.id AND r.approved_at IS NOT NULL)
+           AND NOT EXISTS (SELECT 1 FROM pr_history h
+                           WHERE h.tenant = pr.tenant AND h.pr_id = pr.id AND h.event = $3)
+         ORDER BY pr.created_at, pr.id`,
+		tenant(ctx), timestamp(createdBefore), string(core.HistoryEscalated))
+}
+
+func (db *DB) GetReview(ctx context.Context, userId string) (core.UserPullRequest, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT `+prColumns+`
+         FROM pull_request pr
+         JOIN pr_reviewers ON pr_reviewers.tenant = pr.tenant AND pr.id = pr_reviewers.pr_id
+         WHERE pr.tenant = $1 AND pr_reviewers.reviewer_id = $2
+         ORDER BY pr.created_at, pr.id`,
+		tenant(ctx), userId)
+	if err != nil {
+		return core.UserPullRequest{}, fmt.Errorf("failed to query reviews: %w", err)
+	}
+	defer rows.Close()
+
+	userPullRequest := core.UserPullRequest{UserID: userId}
+
+	for rows.Next() {
+		pullRequest, err := scanPR(rows)
+		if err != nil {
+			return core.UserPullRequest{}, fmt.Errorf("failed to scan row: %w", err)
+		}
+		userPullRequest.PullRequest = append(userPullRequest.PullRequest, pullRequest)
+	}
+
+	if err = rows.Err(); err != nil {
+		return core.UserPullRequest{}, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return userPullRequest, nil
+}
+
+func (db *DB) countBy(ctx context.Context, query string, args ...any) (map[string]int, error) {
+	rows, err := db.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[string]int)
+
+	for rows.Next() {
+		var (
+			key   string
+			count int
+		)
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, err
+		}
+		stats[key] = count
+	}
+
+	return stats, rows.Err()
+}
+
+func (db *DB) GetUserReviewStats(ctx context.Context) (map[string]int, error) {
+	return db.countBy(ctx, `SELECT reviewer_id, COUNT(*) FROM pr_reviewers WHERE tenant = $1 GROUP BY reviewer_id`,
+		tenant(ctx))
+}
+
+func (db *DB) GetPRReviewerCountStats(ctx context.Context) (map[string]int, error) {
+	return db.countBy(ctx, `SELECT pr_id, COUNT(*) FROM pr_reviewers WHERE tenant = $1 GROUP BY pr_id`, tenant(ctx))
+}
+
+func (db *DB) GetPendingReviews(ctx context.Context, now time.Time) ([]core.PendingReviews, error) {
+	rows, err := db.conn.QueryContext(ctx,
+		`SELECT u.id, u.name, u.team_name, u.active, u.out_of_office_until, `+prColumns+`
+         FROM users u
+         JOIN pr_reviewers ON pr_reviewers.tenant = u.tenant AND pr_reviewers.reviewer_id = u.id
+         JOIN pull_request pr ON pr.tenant = pr_reviewers.tenant AND pr.id = pr_reviewers.pr_id
+         WHERE u.tenant = $1 AND u.active AND pr.state = 'OPEN' AND pr_reviewers.approved_at IS NULL
+           AND (u.out_of_office_until IS NULL OR u.out_of_office_until <= $2)
+         ORDER BY u.id, pr.created_at, pr.id`,
+		tenant(ctx), timestamp(now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var pending []core.PendingReviews
+
+	for rows.Next() {
+		var user core.User
+
+		pullRequest, err := scanPR(rows, &user.UserID, &user.Username, &user.TeamName, &user.IsActive,
+			timeColumn{&user.OutOfOfficeUntil})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		if len(pending) == 0 || pending[len(pending)-1].User.UserID != user.UserID {
+			pending = append(pending, core.PendingReviews{User: user})
+		}
+		last := &pending[len(pending)-1]
+		last.PullRequests = append(last.PullRequests, pullRequest)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return pending, nil
+}
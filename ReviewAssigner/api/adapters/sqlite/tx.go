@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"review-assigner/core"
+)
+
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// WithTx runs fn against a copy of the storage bound to a transaction.
+// Transactions take the write lock when they begin, so SQLite serializes
+// them and there is nothing to retry.
+func (db *DB) WithTx(ctx context.Context, fn func(core.DB) error) error {
+	if db.tx != nil {
+		return fn(db)
+	}
+
+	tx, err := db.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = fn(&DB{log: db.log, pool: db.pool, conn: tx, tx: tx}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (db *DB) inTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	if db.tx != nil {
+		return fn(db.tx)
+	}
+
+	tx, err := db.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// BeginTx starts a transaction on the pool for callers of AddUserTX, which
+// writes into a transaction owned by the caller.
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return db.pool.BeginTx(ctx, opts)
+}
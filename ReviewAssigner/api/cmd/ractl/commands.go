@@ -0,0 +1,625 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"review-assigner/adapters/rest"
+)
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ContinueOnError)
+}
+
+func requireArgs(flags *flag.FlagSet, n int, usage string) error {
+	if flags.NArg() != n {
+		return fmt.Errorf("usage: ractl %s", usage)
+	}
+	return nil
+}
+
+func subcommand(args []string, usage string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("usage: ractl %s", usage)
+	}
+	return args[0], args[1:], nil
+}
+
+func runTeam(ctx context.Context, app *app, args []string) error {
+	sub, args, err := subcommand(args, "team add|get|set-lead ...")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "add":
+		return teamAdd(ctx, app, args)
+	case "get":
+		flags := newFlagSet("team get")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if err := requireArgs(flags, 1, "team get <team_name>"); err != nil {
+			return err
+		}
+
+		var resp rest.GetTeamResponse
+		if err := app.client.get(ctx, "/team/get", url.Values{"team_name": {flags.Arg(0)}}, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) { printTeam(w, resp.Team) })
+	case "set-lead":
+		flags := newFlagSet("team set-lead")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if err := requireArgs(flags, 2, "team set-lead <team_name> <user_id>"); err != nil {
+			return err
+		}
+
+		var resp rest.SetTeamLeadResponse
+		req := rest.SetTeamLeadRequest{TeamName: flags.Arg(0), UserID: flags.Arg(1)}
+		if err := app.client.post(ctx, "/team/setLead", req, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) { printTeam(w, resp.Team) })
+	default:
+		return fmt.Errorf("unknown team command %q", sub)
+	}
+}
+
+func teamAdd(ctx context.Context, app *app, args []string) error {
+	var req rest.AddTeamRequest
+
+	flags := newFlagSet("team add")
+	file := flags.String("f", "", "read the team as JSON from a file (- for stdin)")
+	flags.StringVar(&req.TeamName, "name", "", "team name")
+	flags.StringVar(&req.LeadID, "lead", "", "team lead user id")
+	flags.Func("member", "team member as user_id:username[:inactive], repeatable", func(value string) error {
+		parts := strings.Split(value, ":")
+		if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && parts[2] != "inactive") {
+			return errors.New("expected user_id:username[:inactive]")
+		}
+		req.Members = append(req.Members, rest.TeamMemberDTO{
+			UserID:   parts[0],
+			Username: parts[1],
+			IsActive: len(parts) == 2,
+		})
+		return nil
+	})
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *file != "" {
+		in := os.Stdin
+		if *file != "-" {
+			f, err := os.Open(*file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+		if err := json.NewDecoder(in).Decode(&req); err != nil {
+			return fmt.Errorf("decode team: %w", err)
+		}
+	}
+
+	if req.TeamName == "" {
+		return errors.New("usage: ractl team add -name <team_name> -member <user_id:username> ... | -f <file>")
+	}
+
+	var resp rest.AddTeamResponse
+	if err := app.client.post(ctx, "/team/add", req, &resp); err != nil {
+		return err
+	}
+	return app.printer.print(resp, func(w io.Writer) { printTeam(w, resp.Team) })
+}
+
+func printTeam(w io.Writer, team rest.TeamResponse) {
+	fmt.Fprintf(w, "TEAM\t%s\n", team.TeamName)
+	if team.LeadID != "" {
+		fmt.Fprintf(w, "LEAD\t%s\n", team.LeadID)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "USER ID\tUSERNAME\tACTIVE")
+	for _, member := range team.Members {
+		fmt.Fprintf(w, "%s\t%s\t%t\n", member.UserID, member.Username, member.IsActive)
+	}
+}
+
+func runUser(ctx context.Context, app *app, args []string) error {
+	sub, args, err := subcommand(args, "user set-active|out-of-office|reviews|import ...")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "set-active":
+		flags := newFlagSet("user set-active")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if err := requireArgs(flags, 2, "user set-active <user_id> true|false"); err != nil {
+			return err
+		}
+		active, err := strconv.ParseBool(flags.Arg(1))
+		if err != nil {
+			return fmt.Errorf("invalid active flag %q", flags.Arg(1))
+		}
+
+		var resp rest.SetUserActiveResponse
+		req := rest.SetUserActiveRequest{UserID: flags.Arg(0), IsActive: active}
+		if err := app.client.post(ctx, "/users/setIsActive", req, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) { printUser(w, resp.User) })
+	case "out-of-office":
+		flags := newFlagSet("user out-of-office")
+		clearUntil := flags.Bool("clear", false, "clear the out of office period")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		req := rest.SetOutOfOfficeRequest{UserID: flags.Arg(0)}
+		switch {
+		case flags.NArg() == 1 && *clearUntil:
+		case flags.NArg() == 2 && !*clearUntil:
+			until, err := time.Parse(time.RFC3339, flags.Arg(1))
+			if err != nil {
+				return fmt.Errorf("invalid time %q, expected RFC 3339", flags.Arg(1))
+			}
+			req.Until = &until
+		default:
+			return errors.New("usage: ractl user out-of-office <user_id> <until> | -clear <user_id>")
+		}
+
+		var resp rest.SetOutOfOfficeResponse
+		if err := app.client.post(ctx, "/users/setOutOfOffice", req, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) { printUser(w, resp.User) })
+	case "reviews":
+		flags := newFlagSet("user reviews")
+		query := listFlags(flags)
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if err := requireArgs(flags, 1, "user reviews [filters] <user_id>"); err != nil {
+			return err
+		}
+		query.Set("user_id", flags.Arg(0))
+
+		var resp rest.GetUserReviewsResponse
+		if err := app.client.get(ctx, "/users/getReview", query, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) {
+			fmt.Fprintln(w, "PR ID\tNAME\tAUTHOR\tSTATUS")
+			for _, pr := range resp.PullRequests {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status)
+			}
+			printNextCursor(w, resp.NextCursor)
+		})
+	case "import":
+		flags := newFlagSet("user import")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if err := requireArgs(flags, 1, "user import <file.csv|->"); err != nil {
+			return err
+		}
+
+		in := io.Reader(os.Stdin)
+		if flags.Arg(0) != "-" {
+			f, err := os.Open(flags.Arg(0))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+
+		var resp rest.UserImportResponse
+		if err := app.client.upload(ctx, "/admin/users/import", "text/csv", in, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) {
+			fmt.Fprintf(w, "ROWS\t%d\n", resp.Rows)
+			fmt.Fprintf(w, "USERS CREATED\t%d\n", resp.Users.Created)
+			fmt.Fprintf(w, "USERS UPDATED\t%d\n", resp.Users.Updated)
+			fmt.Fprintf(w, "TEAMS CREATED\t%d\n", resp.TeamsCreated)
+			if len(resp.Errors) > 0 {
+				fmt.Fprintln(w)
+				fmt.Fprintln(w, "LINE\tUSER\tERROR")
+				for _, rowErr := range resp.Errors {
+					user := "-"
+					if rowErr.UserID != "" {
+						user = rowErr.UserID
+					}
+					fmt.Fprintf(w, "%d\t%s\t%s\n", rowErr.Line, user, rowErr.Message)
+				}
+			}
+		})
+	default:
+		return fmt.Errorf("unknown user command %q", sub)
+	}
+}
+
+func listFlags(flags *flag.FlagSet) url.Values {
+	query := url.Values{}
+	for _, name := range []string{"state", "author_id", "team_name", "repository", "created_from", "created_to", "sort", "limit", "cursor"} {
+		flags.Func(name, "filter by "+name, func(value string) error {
+			query.Set(name, value)
+			return nil
+		})
+	}
+	return query
+}
+
+func printNextCursor(w io.Writer, cursor string) {
+	if cursor != "" {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "NEXT CURSOR\t%s\n", cursor)
+	}
+}
+
+func printUser(w io.Writer, user rest.UserResponse) {
+	fmt.Fprintln(w, "USER ID\tUSERNAME\tTEAM\tACTIVE\tOUT OF OFFICE UNTIL")
+	until := "-"
+	if user.OutOfOfficeUntil != nil {
+		until = user.OutOfOfficeUntil.Format(time.RFC3339)
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", user.UserID, user.Username, user.TeamName, user.IsActive, until)
+}
+
+func runPR(ctx context.Context, app *app, args []string) error {
+	sub, args, err := subcommand(args, "pr list|get|create|approve|merge ...")
+	if err != nil {
+		return err
+	}
+
+	flags := newFlagSet("pr " + sub)
+	var query url.Values
+	if sub == "list" {
+		query = listFlags(flags)
+	}
+	var create rest.CreatePRRequest
+	if sub == "create" {
+		flags.StringVar(&create.Repository, "repository", "", "repository the pull request belongs to")
+		flags.Int64Var(&create.PullRequestNumber, "number", 0, "pull request number in the repository")
+		flags.Func("label", "pull request label, repeatable", func(value string) error {
+			create.Labels = append(create.Labels, value)
+			return nil
+		})
+		flags.IntVar(&create.LinesAdded, "lines-added", 0, "lines added by the pull request")
+		flags.IntVar(&create.LinesRemoved, "lines-removed", 0, "lines removed by the pull request")
+		flags.IntVar(&create.FilesChanged, "files", 0, "files changed by the pull request")
+		flags.StringVar(&create.Priority, "priority", "", "LOW, NORMAL, HIGH or URGENT")
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	switch sub {
+	case "list":
+		if err := requireArgs(flags, 0, "pr list [filters]"); err != nil {
+			return err
+		}
+
+		var resp rest.ListPRsResponse
+		if err := app.client.get(ctx, "/pullRequest/list", query, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) {
+			fmt.Fprintln(w, "PR ID\tNAME\tAUTHOR\tSTATUS\tREVIEWERS")
+			for _, pr := range resp.PullRequests {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", prLabel(pr),
+					pr.PullRequestName, pr.AuthorID, pr.Status, strings.Join(pr.AssignedReviewers, ", "))
+			}
+			printNextCursor(w, resp.NextCursor)
+		})
+	case "get":
+		if err := requireArgs(flags, 1, "pr get <pull_request_id>"); err != nil {
+			return err
+		}
+
+		var resp rest.GetPRResponse
+		if err := app.client.get(ctx, "/pullRequest/get", url.Values{"pull_request_id": {flags.Arg(0)}}, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) { printPRDetails(w, resp.PR) })
+	case "create":
+		// With a repository and number the id is optional and generated by the server.
+		args := flags.Args()
+		if create.Repository != "" && len(args) == 2 {
+			args = append([]string{""}, args...)
+		}
+		if len(args) != 3 {
+			return errors.New("usage: ractl pr create [-repository <name> -number <n>] [-label <label> ...] " +
+				"[-lines-added <n> -lines-removed <n> -files <n>] [-priority <priority>] " +
+				"[<pull_request_id>] <pull_request_name> <author_id>")
+		}
+
+		var resp rest.CreatePRResponse
+		create.PullRequestID, create.PullRequestName, create.AuthorID = args[0], args[1], args[2]
+		if err := app.client.post(ctx, "/pullRequest/create", create, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) { printPR(w, resp.PR) })
+	case "approve":
+		if err := requireArgs(flags, 2, "pr approve <pull_request_id> <user_id>"); err != nil {
+			return err
+		}
+
+		var resp rest.ApprovePRResponse
+		req := rest.ApprovePRRequest{PullRequestID: flags.Arg(0), UserID: flags.Arg(1)}
+		if err := app.client.post(ctx, "/pullRequest/approve", req, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) { printPR(w, resp.PR) })
+	case "merge":
+		if err := requireArgs(flags, 1, "pr merge <pull_request_id>"); err != nil {
+			return err
+		}
+
+		var resp rest.MergePRResponse
+		req := rest.MergePRRequest{PullRequestID: flags.Arg(0)}
+		if err := app.client.post(ctx, "/pullRequest/merge", req, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) { printPR(w, resp.PR) })
+	default:
+		return fmt.Errorf("unknown pr command %q", sub)
+	}
+}
+
+func printPR(w io.Writer, pr rest.PRResponse) {
+	fmt.Fprintln(w, "PR ID\tNAME\tAUTHOR\tSTATUS\tREVIEWERS")
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		prLabel(pr), pr.PullRequestName, pr.AuthorID, pr.Status, strings.Join(pr.AssignedReviewers, ", "))
+}
+
+// prLabel shows the repository and number next to the id of pull requests that have them.
+func prLabel(pr rest.PRResponse) string {
+	if pr.Repository == "" {
+		return pr.PullRequestID
+	}
+	return fmt.Sprintf("%s (%s#%d)", pr.PullRequestID, pr.Repository, pr.PullRequestNumber)
+}
+
+func printPRDetails(w io.Writer, pr rest.PRDetailsDTO) {
+	printPR(w, pr.PRResponse)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "PRIORITY\t%s\n", pr.Priority)
+	if len(pr.Labels) > 0 {
+		fmt.Fprintf(w, "LABELS\t%s\n", strings.Join(pr.Labels, ", "))
+	}
+	if pr.FilesChanged > 0 || pr.LinesAdded > 0 || pr.LinesRemoved > 0 {
+		fmt.Fprintf(w, "SIZE\t+%d -%d in %d files\n", pr.LinesAdded, pr.LinesRemoved, pr.FilesChanged)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "REVIEWER\tUSERNAME\tTEAM\tACTIVE\tSTATE")
+	for _, reviewer := range pr.Reviewers {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n",
+			reviewer.UserID, reviewer.Username, reviewer.TeamName, reviewer.IsActive, reviewer.ReviewState)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "TIME\tEVENT\tACTOR\tDETAILS")
+	for _, entry := range pr.History {
+		actor := entry.ActorID
+		if actor == "" {
+			actor = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.CreatedAt.Format(time.RFC3339), entry.Event, actor, entry.Details)
+	}
+}
+
+func runReassign(ctx context.Context, app *app, args []string) error {
+	flags := newFlagSet("reassign")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := requireArgs(flags, 2, "reassign <pull_request_id> <old_user_id>"); err != nil {
+		return err
+	}
+
+	var resp rest.ReassignPRResponse
+	req := rest.ReassignReviewer{PullRequestID: flags.Arg(0), OldUserID: flags.Arg(1)}
+	if err := app.client.post(ctx, "/pullRequest/reassign", req, &resp); err != nil {
+		return err
+	}
+	return app.printer.print(resp, func(w io.Writer) {
+		printPR(w, resp.PR)
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "REPLACED BY\t%s\n", resp.ReplacedBy)
+	})
+}
+
+func runRepository(ctx context.Context, app *app, args []string) error {
+	sub, args, err := subcommand(args, "repository add|get|link ...")
+	if err != nil {
+		return err
+	}
+
+	flags := newFlagSet("repository " + sub)
+	teams := []string{}
+	if sub == "add" {
+		flags.Func("team", "team that reviews the repository, repeatable", func(value string) error {
+			teams = append(teams, value)
+			return nil
+		})
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var resp rest.RepositoryResponse
+	switch sub {
+	case "add":
+		if err := requireArgs(flags, 1, "repository add [-team <team_name> ...] <name>"); err != nil {
+			return err
+		}
+		req := rest.RepositoryDTO{Name: flags.Arg(0), TeamNames: teams}
+		if err := app.client.post(ctx, "/repository/add", req, &resp); err != nil {
+			return err
+		}
+	case "get":
+		if err := requireArgs(flags, 1, "repository get <name>"); err != nil {
+			return err
+		}
+		if err := app.client.get(ctx, "/repository/get", url.Values{"repository": {flags.Arg(0)}}, &resp); err != nil {
+			return err
+		}
+	case "link":
+		if err := requireArgs(flags, 2, "repository link <name> <team_name>"); err != nil {
+			return err
+		}
+		req := rest.LinkRepositoryRequest{Repository: flags.Arg(0), TeamName: flags.Arg(1)}
+		if err := app.client.post(ctx, "/repository/linkTeam", req, &resp); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown repository command %q", sub)
+	}
+
+	return app.printer.print(resp, func(w io.Writer) {
+		fmt.Fprintf(w, "REPOSITORY\t%s\n", resp.Repository.Name)
+		fmt.Fprintf(w, "TEAMS\t%s\n", strings.Join(resp.Repository.TeamNames, ", "))
+	})
+}
+
+func runStats(ctx context.Context, app *app, args []string) error {
+	flags := newFlagSet("stats")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := requireArgs(flags, 0, "stats"); err != nil {
+		return err
+	}
+
+	var resp rest.StatsResponse
+	if err := app.client.get(ctx, "/stats", nil, &resp); err != nil {
+		return err
+	}
+	return app.printer.print(resp, func(w io.Writer) {
+		stats := resp.Stats
+		fmt.Fprintf(w, "TOTAL ASSIGNMENTS\t%d\n", stats.TotalAssignments)
+		fmt.Fprintf(w, "USERS WITH ASSIGNMENTS\t%d\n", stats.UniqueUsersWithAssignments)
+		fmt.Fprintf(w, "PRS WITH REVIEWERS\t%d\n", stats.UniquePRsWithReviewers)
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "USER ID\tASSIGNMENTS")
+		for _, userID := range sortedByCount(stats.UserAssignments) {
+			fmt.Fprintf(w, "%s\t%d\n", userID, stats.UserAssignments[userID])
+		}
+	})
+}
+
+func runToken(ctx context.Context, app *app, args []string) error {
+	sub, args, err := subcommand(args, "token create|list|revoke ...")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "create":
+		var req rest.CreateTokenRequest
+
+		flags := newFlagSet("token create")
+		flags.StringVar(&req.UserID, "user", "", "restrict the token to a user")
+		flags.Func("scope", "granted scope, repeatable", func(value string) error {
+			req.Scopes = append(req.Scopes, value)
+			return nil
+		})
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if err := requireArgs(flags, 1, "token create [-user <user_id>] -scope <scope> ... <name>"); err != nil {
+			return err
+		}
+		req.Name = flags.Arg(0)
+
+		var resp rest.CreateTokenResponse
+		if err := app.client.post(ctx, "/admin/tokens", req, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) {
+			printTokens(w, []rest.TokenResponse{resp.Token})
+			fmt.Fprintln(w)
+			fmt.Fprintf(w, "SECRET\t%s\n", resp.Secret)
+		})
+	case "list":
+		flags := newFlagSet("token list")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if err := requireArgs(flags, 0, "token list"); err != nil {
+			return err
+		}
+
+		var resp rest.ListTokensResponse
+		if err := app.client.get(ctx, "/admin/tokens", nil, &resp); err != nil {
+			return err
+		}
+		return app.printer.print(resp, func(w io.Writer) { printTokens(w, resp.Tokens) })
+	case "revoke":
+		flags := newFlagSet("token revoke")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if err := requireArgs(flags, 1, "token revoke <token_id>"); err != nil {
+			return err
+		}
+		if _, err := strconv.ParseInt(flags.Arg(0), 10, 64); err != nil {
+			return fmt.Errorf("invalid token id %q", flags.Arg(0))
+		}
+
+		if err := app.client.delete(ctx, "/admin/tokens/"+flags.Arg(0)); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "token %s revoked\n", flags.Arg(0))
+		return nil
+	default:
+		return fmt.Errorf("unknown token command %q", sub)
+	}
+}
+
+func printTokens(w io.Writer, tokens []rest.TokenResponse) {
+	fmt.Fprintln(w, "ID\tTENANT\tNAME\tUSER\tSCOPES\tCREATED\tLAST USED")
+	for _, token := range tokens {
+		user, lastUsed := "-", "-"
+		if token.UserID != "" {
+			user = token.UserID
+		}
+		if token.LastUsedAt != nil {
+			lastUsed = token.LastUsedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", token.ID, token.Tenant, token.Name, user,
+			strings.Join(token.Scopes, ","), token.CreatedAt.Format(time.RFC3339), lastUsed)
+	}
+}
+
+func sortedByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+type Config struct {
+	Server  string        `yaml:"server" env:"RACTL_SERVER" env-default:"http://localhost:8080"`
+	Token   string        `yaml:"token" env:"RACTL_TOKEN"`
+	Tenant  string        `yaml:"tenant" env:"RACTL_TENANT"`
+	Output  string        `yaml:"output" env:"RACTL_OUTPUT" env-default:"table"`
+	Timeout time.Duration `yaml:"timeout" env:"RACTL_TIMEOUT" env-default:"10s"`
+}
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, app *app, args []string) error
+}
+
+var commands = []command{
+	{"team", "team add|get|set-lead ...", runTeam},
+	{"user", "user set-active|out-of-office|reviews|import ...", runUser},
+	{"pr", "pr list|get|create|approve|merge ...", runPR},
+	{"reassign", "reassign <pull_request_id> <old_user_id>", runReassign},
+	{"repository", "repository add|get|link ...", runRepository},
+	{"stats", "stats", runStats},
+	{"token", "token create|list|revoke ...", runToken},
+	{"export", "export [-f <file>]", runExport},
+	{"import", "import [-dry-run] <file|->", runImport},
+}
+
+type app struct {
+	client  *client
+	printer printer
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "ractl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("ractl", flag.ContinueOnError)
+	flags.Usage = func() { usage(flags) }
+
+	configPath := flags.String("config", defaultConfigPath(), "client configuration file")
+	server := flags.String("server", "", "server URL (overrides RACTL_SERVER)")
+	token := flags.String("token", "", "API token (overrides RACTL_TOKEN)")
+	tenant := flags.String("tenant", "", "tenant to act in (overrides RACTL_TENANT)")
+	output := flags.String("o", "", "output format: table, json or yaml")
+	timeout := flags.Duration("timeout", 0, "command deadline, 0 for none (overrides RACTL_TIMEOUT, which export and import ignore)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if *server != "" {
+		cfg.Server = *server
+	}
+	if *token != "" {
+		cfg.Token = *token
+	}
+	if *tenant != "" {
+		cfg.Tenant = *tenant
+	}
+	if *output != "" {
+		cfg.Output = *output
+	}
+	timeoutSet := false
+	flags.Visit(func(f *flag.Flag) { timeoutSet = timeoutSet || f.Name == "timeout" })
+
+	p, err := newPrinter(cfg.Output, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		usage(flags)
+		return errors.New("no command given")
+	}
+
+	name, rest := flags.Arg(0), flags.Args()[1:]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			deadline := cfg.Timeout
+			switch {
+			case timeoutSet:
+				deadline = *timeout
+			case streams(name, rest):
+				deadline = 0
+			}
+
+			ctx := context.Background()
+			if deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, deadline)
+				defer cancel()
+			}
+			return cmd.run(ctx, &app{client: newClient(cfg), printer: p}, rest)
+		}
+	}
+
+	usage(flags)
+	return fmt.Errorf("unknown command %q", name)
+}
+
+// streams reports whether the command uploads or downloads a whole snapshot or
+// CSV file, which may take longer than the default timeout of a single call.
+func streams(name string, args []string) bool {
+	switch name {
+	case "export", "import":
+		return true
+	case "user":
+		return len(args) > 0 && args[0] == "import"
+	default:
+		return false
+	}
+}
+
+func usage(flags *flag.FlagSet) {
+	out := flags.Output()
+	fmt.Fprintln(out, "Usage: ractl [flags] <command> [args]")
+	fmt.Fprintln(out, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintln(out, "  "+cmd.usage)
+	}
+	fmt.Fprintln(out, "\nFlags:")
+	flags.PrintDefaults()
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "ractl", "config.yaml")
+}
+
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+	if _, err := os.Stat(path); path != "" && err == nil {
+		if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+			return Config{}, fmt.Errorf("cannot read config %q: %w", path, err)
+		}
+		return cfg, nil
+	}
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return Config{}, fmt.Errorf("cannot read config from env: %w", err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"review-assigner/adapters/rest"
+)
+
+// newTestServer answers /team/get with a backend team, and /stats and
+// /admin/export after the given delay.
+func newTestServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /team/get", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]any{"error": map[string]string{"code": "UNAUTHORIZED", "message": "invalid token"}})
+			return
+		}
+		json.NewEncoder(w).Encode(rest.GetTeamResponse{Team: rest.TeamResponse{
+			TeamName: r.URL.Query().Get("team_name"),
+			LeadID:   "u1",
+			Members: []rest.TeamMemberDTO{
+				{UserID: "u1", Username: "Alice", IsActive: true},
+				{UserID: "u2", Username: "Bob", IsActive: false},
+			},
+		}})
+	})
+	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		json.NewEncoder(w).Encode(rest.StatsResponse{})
+	})
+	mux.HandleFunc("GET /admin/export", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		json.NewEncoder(w).Encode(rest.SnapshotDTO{})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOutputFormats(t *testing.T) {
+	server := newTestServer(t, 0)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "table",
+			want: "TEAM  backend\n" +
+				"LEAD  u1\n" +
+				"\n" +
+				"USER ID  USERNAME  ACTIVE\n" +
+				"u1       Alice     true\n" +
+				"u2       Bob       false\n",
+		},
+		{
+			format: "json",
+			want: `{
+  "team": {
+    "team_name": "backend",
+    "lead_id": "u1",
+    "members": [
+      {
+        "user_id": "u1",
+        "username": "Alice",
+        "is_active": true
+      },
+      {
+        "user_id": "u2",
+        "username": "Bob",
+        "is_active": false
+      }
+    ]
+  }
+}
+`,
+		},
+		{
+			format: "yaml",
+			want: `team:
+  lead_id: u1
+  members:
+    - is_active: true
+      user_id: u1
+      username: Alice
+    - is_active: false
+      user_id: u2
+      username: Bob
+  team_name: backend
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out bytes.Buffer
+			p, err := newPrinter(tt.format, &out)
+			if err != nil {
+				t.Fatalf("new printer: %v", err)
+			}
+
+			app := &app{client: newClient(Config{Server: server.URL, Token: "test-token"}), printer: p}
+			if err := runTeam(context.Background(), app, []string{"get", "backend"}); err != nil {
+				t.Fatalf("team get: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("got output\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	server := newTestServer(t, 0)
+
+	p, _ := newPrinter("table", &bytes.Buffer{})
+	app := &app{client: newClient(Config{Server: server.URL, Token: "wrong"}), printer: p}
+
+	err := runTeam(context.Background(), app, []string{"get", "backend"})
+
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnauthorized || apiErr.Code != "UNAUTHORIZED" {
+		t.Fatalf("got error %v, want UNAUTHORIZED", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	server := newTestServer(t, 200*time.Millisecond)
+	t.Setenv("RACTL_TIMEOUT", "50ms")
+
+	base := []string{"-config", "", "-server", server.URL}
+	export := filepath.Join(t.TempDir(), "backup.json")
+
+	tests := []struct {
+		name    string
+		args    []string
+		timeout bool
+	}{
+		{name: "regular command", args: []string{"stats"}, timeout: true},
+		{name: "regular command without deadline", args: []string{"-timeout", "0", "stats"}},
+		{name: "export ignores RACTL_TIMEOUT", args: []string{"export", "-f", export}},
+		{name: "export with an explicit deadline", args: []string{"-timeout", "50ms", "export", "-f", export}, timeout: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := run(append(append([]string{}, base...), tt.args...))
+			if tt.timeout && !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+			}
+			if !tt.timeout && err != nil {
+				t.Errorf("got error %v, want none", err)
+			}
+		})
+	}
+}
+
+func TestStreams(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"export"}, want: true},
+		{args: []string{"import", "backup.json"}, want: true},
+		{args: []string{"user", "import", "people.csv"}, want: true},
+		{args: []string{"user", "reviews", "u1"}},
+		{args: []string{"stats"}},
+	}
+
+	for _, tt := range tests {
+		if got := streams(tt.args[0], tt.args[1:]); got != tt.want {
+			t.Errorf("streams(%q) = %v, want %v", strings.Join(tt.args, " "), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+type printer struct {
+	format string
+	out    io.Writer
+}
+
+func newPrinter(format string, out io.Writer) (printer, error) {
+	switch format {
+	case "table", "json", "yaml":
+		return printer{format: format, out: out}, nil
+	default:
+		return printer{}, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func (p printer) print(v any, table func(w io.Writer)) error {
+	switch p.format {
+	case "json":
+		encoder := json.NewEncoder(p.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case "yaml":
+		// Round-trip through JSON so YAML keys match the API field names.
+		payload, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var generic any
+		if err := json.Unmarshal(payload, &generic); err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(p.out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(generic); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		w := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+		table(w)
+		return w.Flush()
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"review-assigner/adapters/rest"
+)
+
+func runExport(ctx context.Context, app *app, args []string) error {
+	flags := newFlagSet("export")
+	file := flags.String("f", "", "write the snapshot to a file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := requireArgs(flags, 0, "export [-f <file>]"); err != nil {
+		return err
+	}
+
+	var snapshot rest.SnapshotDTO
+	if err := app.client.get(ctx, "/admin/export", nil, &snapshot); err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snapshot); err != nil {
+		return err
+	}
+
+	if *file != "" {
+		fmt.Fprintf(os.Stderr, "exported %d teams, %d users, %d repositories and %d pull requests to %s\n",
+			len(snapshot.Teams), len(snapshot.Users), len(snapshot.Repositories), len(snapshot.PullRequests), *file)
+	}
+	return nil
+}
+
+func runImport(ctx context.Context, app *app, args []string) error {
+	flags := newFlagSet("import")
+	dryRun := flags.Bool("dry-run", false, "validate the snapshot without storing it")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := requireArgs(flags, 1, "import [-dry-run] <file|->"); err != nil {
+		return err
+	}
+
+	in := io.Reader(os.Stdin)
+	if flags.Arg(0) != "-" {
+		f, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var snapshot rest.SnapshotDTO
+	if err := json.NewDecoder(in).Decode(&snapshot); err != nil {
+		return fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	path := "/admin/import"
+	if *dryRun {
+		path += "?dry_run=true"
+	}
+
+	var resp rest.ImportResponse
+	if err := app.client.post(ctx, path, snapshot, &resp); err != nil {
+		return err
+	}
+	return app.printer.print(resp, func(w io.Writer) {
+		if resp.DryRun {
+			fmt.Fprintln(w, "DRY RUN, nothing was stored")
+		}
+		fmt.Fprintln(w, "\tCREATED\tUPDATED")
+		fmt.Fprintf(w, "TEAMS\t%d\t%d\n", resp.Teams.Created, resp.Teams.Updated)
+		fmt.Fprintf(w, "USERS\t%d\t%d\n", resp.Users.Created, resp.Users.Updated)
+		fmt.Fprintf(w, "REPOSITORIES\t%d\t%d\n", resp.Repositories.Created, resp.Repositories.Updated)
+		fmt.Fprintf(w, "PULL REQUESTS\t%d\t%d\n", resp.PullRequests.Created, resp.PullRequests.Updated)
+	})
+}
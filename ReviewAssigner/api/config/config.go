@@ -7,14 +7,98 @@ import (
 )
 
 type HTTPConfig struct {
-	Address string        `yaml:"address" env:"API_ADDRESS" env-default:"localhost:80"`
-	Timeout time.Duration `yaml:"timeout" env:"API_TIMEOUT" env-default:"1s"`
+	Address         string        `yaml:"address" env:"API_ADDRESS" env-default:"localhost:80"`
+	Timeout         time.Duration `yaml:"timeout" env:"API_TIMEOUT" env-default:"1s"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"API_SHUTDOWN_TIMEOUT" env-default:"30s"`
+}
+
+type GRPCConfig struct {
+	Address string `yaml:"address" env:"GRPC_ADDRESS" env-default:"localhost:9090"`
+}
+
+type JWTConfig struct {
+	JWKSFile    string              `yaml:"jwks_file" env:"JWT_JWKS_FILE"`
+	JWKSURL     string              `yaml:"jwks_url" env:"JWT_JWKS_URL"`
+	Timeout     time.Duration       `yaml:"timeout" env:"JWT_JWKS_TIMEOUT" env-default:"5s"`
+	Issuer      string              `yaml:"issuer" env:"JWT_ISSUER"`
+	Audience    string              `yaml:"audience" env:"JWT_AUDIENCE"`
+	Leeway      time.Duration       `yaml:"leeway" env:"JWT_LEEWAY" env-default:"30s"`
+	UserClaim   string              `yaml:"user_claim" env:"JWT_USER_CLAIM" env-default:"sub"`
+	RolesClaim  string              `yaml:"roles_claim" env:"JWT_ROLES_CLAIM" env-default:"roles"`
+	TenantClaim string              `yaml:"tenant_claim" env:"JWT_TENANT_CLAIM" env-default:"tenant"`
+	RoleScopes  map[string][]string `yaml:"role_scopes"`
+}
+
+type AuthConfig struct {
+	Enabled     bool      `yaml:"enabled" env:"AUTH_ENABLED" env-default:"true"`
+	AdminTokens []string  `yaml:"admin_tokens" env:"AUTH_ADMIN_TOKENS" env-separator:","`
+	JWT         JWTConfig `yaml:"jwt"`
+}
+
+type WebhookConfig struct {
+	URL            string            `yaml:"url" env:"WEBHOOK_URL"`
+	Timeout        time.Duration     `yaml:"timeout" env:"WEBHOOK_TIMEOUT" env-default:"5s"`
+	Username       string            `yaml:"username" env:"WEBHOOK_USERNAME" env-default:"review-assigner"`
+	IconEmoji      string            `yaml:"icon_emoji" env:"WEBHOOK_ICON_EMOJI"`
+	DefaultChannel string            `yaml:"default_channel" env:"WEBHOOK_DEFAULT_CHANNEL"`
+	Channels       map[string]string `yaml:"channels"`
+	Handles        map[string]string `yaml:"handles"`
+	Templates      map[string]string `yaml:"templates"`
+}
+
+type SMTPConfig struct {
+	Host       string            `yaml:"host" env:"SMTP_HOST"`
+	Port       int               `yaml:"port" env:"SMTP_PORT" env-default:"587"`
+	Username   string            `yaml:"username" env:"SMTP_USERNAME"`
+	Password   string            `yaml:"password" env:"SMTP_PASSWORD"`
+	From       string            `yaml:"from" env:"SMTP_FROM" env-default:"review-assigner@localhost"`
+	Domain     string            `yaml:"domain" env:"SMTP_DOMAIN"`
+	Recipients map[string]string `yaml:"recipients"`
+	Digest     bool              `yaml:"digest" env:"SMTP_DIGEST"`
+	DigestTime string            `yaml:"digest_time" env:"SMTP_DIGEST_TIME" env-default:"09:00"`
+	Templates  map[string]string `yaml:"templates"`
+}
+
+type NotifierConfig struct {
+	QueueSize int           `yaml:"queue_size" env:"NOTIFIER_QUEUE_SIZE" env-default:"100"`
+	Webhook   WebhookConfig `yaml:"webhook"`
+	SMTP      SMTPConfig    `yaml:"smtp"`
+}
+
+type SchedulerConfig struct {
+	Timeout            time.Duration `yaml:"timeout" env:"SCHEDULER_TIMEOUT" env-default:"5m"`
+	ReminderSchedule   string        `yaml:"reminder_schedule" env:"REMINDER_SCHEDULE"`
+	EscalationSchedule string        `yaml:"escalation_schedule" env:"ESCALATION_SCHEDULE"`
+	CleanupSchedule    string        `yaml:"cleanup_schedule" env:"CLEANUP_SCHEDULE" env-default:"@hourly"`
+}
+
+type IdempotencyConfig struct {
+	TTL time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" env-default:"24h"`
+}
+
+type EscalationConfig struct {
+	Threshold         time.Duration `yaml:"threshold" env:"ESCALATION_THRESHOLD" env-default:"48h"`
+	AddLeadAsReviewer bool          `yaml:"add_lead_as_reviewer" env:"ESCALATION_ADD_LEAD_AS_REVIEWER"`
+}
+
+type AssignmentConfig struct {
+	LargeLines    int    `yaml:"large_lines" env:"ASSIGNMENT_LARGE_LINES" env-default:"500"`
+	LargeFiles    int    `yaml:"large_files" env:"ASSIGNMENT_LARGE_FILES" env-default:"20"`
+	SecurityLabel string `yaml:"security_label" env:"ASSIGNMENT_SECURITY_LABEL" env-default:"security"`
+	SecurityTeam  string `yaml:"security_team" env:"ASSIGNMENT_SECURITY_TEAM" env-default:"security"`
 }
 
 type Config struct {
-	LogLevel   string     `yaml:"log_level" env:"LOG_LEVEL" env-default:"DEBUG"`
-	DBAddress  string     `yaml:"db_address" env:"DB_ADDRESS" env-default:"localhost:82"`
-	HTTPConfig HTTPConfig `yaml:"api_server"`
+	LogLevel    string            `yaml:"log_level" env:"LOG_LEVEL" env-default:"DEBUG"`
+	DBAddress   string            `yaml:"db_address" env:"DB_ADDRESS" env-default:"localhost:82"`
+	HTTPConfig  HTTPConfig        `yaml:"api_server"`
+	GRPCConfig  GRPCConfig        `yaml:"grpc_server"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Notifier    NotifierConfig    `yaml:"notifier"`
+	Scheduler   SchedulerConfig   `yaml:"scheduler"`
+	Escalation  EscalationConfig  `yaml:"escalation"`
+	Assignment  AssignmentConfig  `yaml:"assignment"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
 }
 
 func MustLoad(configPath string) Config {
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMustLoadDefaults(t *testing.T) {
+	cfg := MustLoad(writeConfig(t, "scheduler:\n  reminder_schedule: \"0 9 * * 1-5\"\n"))
+
+	if cfg.Scheduler.ReminderSchedule != "0 9 * * 1-5" {
+		t.Errorf("reminder schedule: got %q", cfg.Scheduler.ReminderSchedule)
+	}
+	if cfg.Scheduler.EscalationSchedule != "" {
+		t.Errorf("escalations are scheduled by default: %q", cfg.Scheduler.EscalationSchedule)
+	}
+	if cfg.Scheduler.CleanupSchedule != "@hourly" || cfg.Scheduler.Timeout != 5*time.Minute {
+		t.Errorf("scheduler defaults: got %+v", cfg.Scheduler)
+	}
+	if cfg.Escalation.Threshold != 48*time.Hour || cfg.Notifier.SMTP.DigestTime != "09:00" {
+		t.Errorf("escalation threshold %v, digest time %q", cfg.Escalation.Threshold, cfg.Notifier.SMTP.DigestTime)
+	}
+	if !cfg.Auth.Enabled {
+		t.Error("authentication is disabled by default")
+	}
+}
+
+func TestMustLoadEnvOverridesFile(t *testing.T) {
+	t.Setenv("ESCALATION_SCHEDULE", "@every 1h")
+	t.Setenv("AUTH_ADMIN_TOKENS", "first,second")
+
+	cfg := MustLoad(writeConfig(t, "scheduler:\n  escalation_schedule: \"@daily\"\n"))
+
+	if cfg.Scheduler.EscalationSchedule != "@every 1h" {
+		t.Errorf("escalation schedule: got %q, want the environment value", cfg.Scheduler.EscalationSchedule)
+	}
+	if len(cfg.Auth.AdminTokens) != 2 || cfg.Auth.AdminTokens[1] != "second" {
+		t.Errorf("admin tokens: got %q", cfg.Auth.AdminTokens)
+	}
+}
+
+func TestMustLoadRepositoryConfig(t *testing.T) {
+	cfg := MustLoad("../config.yaml")
+
+	if cfg.HTTPConfig.Address != "0.0.0.0:8080" || cfg.GRPCConfig.Address != "0.0.0.0:9090" {
+		t.Errorf("addresses: got %q and %q", cfg.HTTPConfig.Address, cfg.GRPCConfig.Address)
+	}
+	if cfg.Notifier.Webhook.DefaultChannel != "#code-review" || cfg.Idempotency.TTL != 24*time.Hour {
+		t.Errorf("got %+v", cfg)
+	}
+}
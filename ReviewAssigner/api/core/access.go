@@ -0,0 +1,196 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+type Role string
+
+// Team leads are not a global role: a member is a lead only for the team
+// whose lead_id points at them, so it is resolved per request.
+const (
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+	RoleBot    Role = "bot"
+)
+
+type Principal struct {
+	UserID string
+	Role   Role
+	Scopes []Scope
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+func actor(ctx context.Context) string {
+	principal, _ := PrincipalFrom(ctx)
+	return principal.UserID
+}
+
+type rule struct {
+	users []string
+	team  string
+	bots  bool
+}
+
+// Calls without a principal come from inside the process (scheduler, digests)
+// or from a server running with authentication disabled, so they are trusted.
+func (s *Service) authorize(ctx context.Context, db DB, r rule) error {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok || principal.Role == RoleAdmin {
+		return nil
+	}
+
+	if principal.Role == RoleBot {
+		if r.bots {
+			return nil
+		}
+		return ErrForbidden
+	}
+
+	if slices.Contains(r.users, principal.UserID) {
+		return nil
+	}
+
+	if r.team != "" {
+		team, err := db.GetTeam(ctx, r.team)
+		if err != nil && !errors.Is(err, ErrTeamNotFound) {
+			return err
+		}
+		if err == nil && team.LeadID != "" && team.LeadID == principal.UserID {
+			return nil
+		}
+	}
+
+	s.log.Info("access denied", "user_id", principal.UserID, "role", principal.Role)
+
+	return ErrForbidden
+}
+
+// requireScope checks a token scope for reads that a transport does not gate
+// per route, such as GraphQL fields.
+func requireScope(ctx context.Context, scope Scope) error {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok || principal.Role == RoleAdmin || slices.Contains(principal.Scopes, scope) {
+		return nil
+	}
+	return fmt.Errorf("%w: token lacks the %s scope", ErrForbidden, scope)
+}
+
+// authorizeUsers checks a read of several users' data at once. Members may
+// read their own and that of the teams they lead, bots and admins anyone's.
+func (s *Service) authorizeUsers(ctx context.Context, db DB, userIds []string) error {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok || principal.Role == RoleAdmin || principal.Role == RoleBot {
+		return nil
+	}
+
+	others := slices.DeleteFunc(slices.Clone(userIds), func(userId string) bool {
+		return userId == principal.UserID
+	})
+	if len(others) == 0 {
+		return nil
+	}
+
+	users, err := db.GetUsers(ctx, others)
+	if err != nil {
+		return err
+	}
+	led, err := ledTeams(ctx, db, principal, users)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if !led[user.TeamName] {
+			s.log.Info("access denied", "user_id", principal.UserID, "role", principal.Role)
+			return ErrForbidden
+		}
+	}
+
+	return nil
+}
+
+// authorizePRs checks a read of pull requests. Members may read the ones
+// they authored or review and those involving the teams they lead.
+func (s *Service) authorizePRs(ctx context.Context, db DB, pullRequests []PullRequest) error {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok || principal.Role == RoleAdmin || principal.Role == RoleBot {
+		return nil
+	}
+
+	var userIds []string
+	for _, pullRequest := range pullRequests {
+		for _, userId := range involved(pullRequest) {
+			if !slices.Contains(userIds, userId) {
+				userIds = append(userIds, userId)
+			}
+		}
+	}
+
+	users, err := db.GetUsers(ctx, userIds)
+	if err != nil {
+		return err
+	}
+	led, err := ledTeams(ctx, db, principal, users)
+	if err != nil {
+		return err
+	}
+
+	teams := make(map[string]string, len(users))
+	for _, user := range users {
+		teams[user.UserID] = user.TeamName
+	}
+
+	for _, pullRequest := range pullRequests {
+		allowed := slices.ContainsFunc(involved(pullRequest), func(userId string) bool {
+			return userId == principal.UserID || led[teams[userId]]
+		})
+		if !allowed {
+			s.log.Info("access denied", "user_id", principal.UserID, "role", principal.Role,
+				"pr_id", pullRequest.PullRequestID)
+			return ErrForbidden
+		}
+	}
+
+	return nil
+}
+
+func involved(pullRequest PullRequest) []string {
+	return append([]string{pullRequest.AuthorID}, pullRequest.AssignedReviewers...)
+}
+
+// ledTeams returns the teams of the users that the principal leads.
+func ledTeams(ctx context.Context, db DB, principal Principal, users []User) (map[string]bool, error) {
+	var teamNames []string
+	for _, user := range users {
+		if !slices.Contains(teamNames, user.TeamName) {
+			teamNames = append(teamNames, user.TeamName)
+		}
+	}
+
+	teams, err := db.GetTeams(ctx, teamNames)
+	if err != nil {
+		return nil, err
+	}
+
+	led := make(map[string]bool)
+	for _, team := range teams {
+		if team.LeadID != "" && team.LeadID == principal.UserID {
+			led[team.TeamName] = true
+		}
+	}
+	return led, nil
+}
@@ -0,0 +1,165 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"review-assigner/core"
+	"testing"
+)
+
+// newService returns a service over an in-memory SQLite database with a
+// backend team led by u1 and a frontend team with u5, and a PR by u2 that
+// two of u1, u3 and u4 review.
+func newService(t *testing.T) (*core.Service, core.PullRequest) {
+	t.Helper()
+
+	service := openService(t, nil, core.EscalationPolicy{})
+
+	ctx := context.Background()
+	teams := []core.Team{
+		{TeamName: "backend", LeadID: "u1", Members: []core.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Carol", IsActive: true},
+			{UserID: "u4", Username: "Dave", IsActive: true},
+		}},
+		{TeamName: "frontend", Members: []core.TeamMember{
+			{UserID: "u5", Username: "Eve", IsActive: true},
+		}},
+	}
+	for _, team := range teams {
+		if _, err := service.CreateTeam(ctx, team); err != nil {
+			t.Fatalf("create team: %v", err)
+		}
+	}
+
+	pullRequest, err := service.CreatePR(ctx, core.PullRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u2"})
+	if err != nil {
+		t.Fatalf("create pr: %v", err)
+	}
+
+	return service, pullRequest
+}
+
+func TestAuthorization(t *testing.T) {
+	admin := core.Principal{Role: core.RoleAdmin}
+	lead := core.Principal{UserID: "u1", Role: core.RoleMember, Scopes: core.Scopes}
+	author := core.Principal{UserID: "u2", Role: core.RoleMember, Scopes: core.Scopes}
+	outsider := core.Principal{UserID: "u5", Role: core.RoleMember, Scopes: core.Scopes}
+	bot := core.Principal{Role: core.RoleBot, Scopes: core.Scopes}
+
+	operations := []struct {
+		name    string
+		call    func(context.Context, *core.Service, core.PullRequest) error
+		allowed map[string]bool
+	}{
+		{
+			name: "deactivate a member",
+			call: func(ctx context.Context, service *core.Service, _ core.PullRequest) error {
+				_, err := service.IsActive(ctx, "u3", false)
+				return err
+			},
+			allowed: map[string]bool{"admin": true, "lead": true},
+		},
+		{
+			name: "reassign a reviewer",
+			call: func(ctx context.Context, service *core.Service, pullRequest core.PullRequest) error {
+				_, _, err := service.Reassign(ctx, core.ReassignReviewer{PRId: pullRequest.PullRequestID, UserID: reviewer(pullRequest)})
+				return err
+			},
+			allowed: map[string]bool{"admin": true, "lead": true, "reviewer": true},
+		},
+		{
+			name: "create a PR",
+			call: func(ctx context.Context, service *core.Service, _ core.PullRequest) error {
+				_, err := service.CreatePR(ctx, core.PullRequest{PullRequestID: "pr-2", PullRequestName: "Fix search", AuthorID: "u2"})
+				return err
+			},
+			allowed: map[string]bool{"admin": true, "lead": true, "author": true, "bot": true},
+		},
+		{
+			name: "merge a PR",
+			call: func(ctx context.Context, service *core.Service, pullRequest core.PullRequest) error {
+				_, err := service.Merged(ctx, pullRequest.PullRequestID)
+				return err
+			},
+			allowed: map[string]bool{"admin": true, "lead": true, "author": true, "bot": true},
+		},
+		{
+			name: "read PR details",
+			call: func(ctx context.Context, service *core.Service, pullRequest core.PullRequest) error {
+				_, err := service.GetPRDetails(ctx, pullRequest.PullRequestID)
+				return err
+			},
+			allowed: map[string]bool{"admin": true, "lead": true, "author": true, "reviewer": true, "bot": true},
+		},
+		{
+			name: "list every PR",
+			call: func(ctx context.Context, service *core.Service, _ core.PullRequest) error {
+				_, err := service.ListPRs(ctx, core.PRFilter{})
+				return err
+			},
+			allowed: map[string]bool{"admin": true, "bot": true},
+		},
+		{
+			name: "list PRs of a team",
+			call: func(ctx context.Context, service *core.Service, _ core.PullRequest) error {
+				_, err := service.ListPRs(ctx, core.PRFilter{TeamName: "backend"})
+				return err
+			},
+			allowed: map[string]bool{"admin": true, "lead": true, "bot": true},
+		},
+		{
+			name: "list PRs of an author",
+			call: func(ctx context.Context, service *core.Service, _ core.PullRequest) error {
+				_, err := service.ListPRs(ctx, core.PRFilter{AuthorID: "u2"})
+				return err
+			},
+			allowed: map[string]bool{"admin": true, "lead": true, "author": true, "bot": true},
+		},
+		{
+			name: "read stats",
+			call: func(ctx context.Context, service *core.Service, _ core.PullRequest) error {
+				_, err := service.GetStats(ctx)
+				return err
+			},
+			allowed: map[string]bool{"admin": true, "bot": true},
+		},
+	}
+
+	for _, op := range operations {
+		for _, role := range []string{"admin", "lead", "author", "reviewer", "outsider", "bot"} {
+			t.Run(op.name+"/"+role, func(t *testing.T) {
+				service, pullRequest := newService(t)
+
+				principal := map[string]core.Principal{
+					"admin":    admin,
+					"lead":     lead,
+					"author":   author,
+					"reviewer": {UserID: reviewer(pullRequest), Role: core.RoleMember, Scopes: core.Scopes},
+					"outsider": outsider,
+					"bot":      bot,
+				}[role]
+
+				err := op.call(core.WithPrincipal(context.Background(), principal), service, pullRequest)
+				if op.allowed[role] && err != nil {
+					t.Errorf("got error %v, want allowed", err)
+				}
+				if !op.allowed[role] && !errors.Is(err, core.ErrForbidden) {
+					t.Errorf("got error %v, want %v", err, core.ErrForbidden)
+				}
+			})
+		}
+	}
+}
+
+// reviewer returns a reviewer of the PR other than the lead, so the lead and
+// reviewer roles stay distinct.
+func reviewer(pullRequest core.PullRequest) string {
+	for _, userId := range pullRequest.AssignedReviewers {
+		if userId != "u1" {
+			return userId
+		}
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultReviewers = 2
+	maxLabels        = 20
+	maxLabelLength   = 50
+)
+
+func validMetadata(pullRequest PullRequest) bool {
+	if len(pullRequest.Labels) > maxLabels ||
+		pullRequest.LinesAdded < 0 || pullRequest.LinesRemoved < 0 || pullRequest.FilesChanged < 0 {
+		return false
+	}
+
+	for i, label := range pullRequest.Labels {
+		if label == "" || len(label) > maxLabelLength || strings.ContainsFunc(label, unicode.IsSpace) ||
+			slices.Contains(pullRequest.Labels[:i], label) {
+			return false
+		}
+	}
+
+	switch pullRequest.Priority {
+	case "", PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p AssignmentPolicy) large(pullRequest PullRequest) bool {
+	return (p.LargeLines > 0 && pullRequest.LinesAdded+pullRequest.LinesRemoved >= p.LargeLines) ||
+		(p.LargeFiles > 0 && pullRequest.FilesChanged >= p.LargeFiles)
+}
+
+func (p AssignmentPolicy) security(pullRequest PullRequest) bool {
+	return p.SecurityTeam != "" && slices.Contains(pullRequest.Labels, p.SecurityLabel)
+}
+
+// candidates lists active members of the team who may review the pull
+// request, in team order or, for urgent pull requests, by their open reviews.
+func candidates(ctx context.Context, db DB, team Team, pullRequest PullRequest, exclude []string) ([]string, error) {
+	var userIds []string
+	for _, teamMember := range team.Members {
+		if teamMember.IsActive && teamMember.UserID != pullRequest.AuthorID && !slices.Contains(exclude, teamMember.UserID) {
+			userIds = append(userIds, teamMember.UserID)
+		}
+	}
+
+	if pullRequest.Priority != PriorityUrgent || len(userIds) < 2 {
+		return userIds, nil
+	}
+
+	reviews, err := db.GetReviewsByUsers(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+	load := make(map[string]int, len(userIds))
+	for userId, prs := range reviews {
+		for _, pr := range prs {
+			if pr.Status == "OPEN" {
+				load[userId]++
+			}
+		}
+	}
+	slices.SortStableFunc(userIds, func(a, b string) int { return load[a] - load[b] })
+
+	return userIds, nil
+}
+
+// pickReviewers chooses the reviewers of a new pull request from the review
+// team: two, or three for a large pull request, and a member of the security
+// team on top when the pull request carries the security label and none of
+// them is in that team already.
+func (s *Service) pickReviewers(ctx context.Context, db DB, team Team, pullRequest PullRequest) ([]string, error) {
+	count := defaultReviewers
+	if s.assignment.large(pullRequest) {
+		count++
+	}
+
+	userIds, err := candidates(ctx, db, team, pullRequest, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(userIds) == 0 {
+		return nil, ErrNotEnoughReviewers
+	}
+	reviewers := userIds[:min(count, len(userIds))]
+
+	if !s.assignment.security(pullRequest) {
+		return reviewers, nil
+	}
+
+	securityTeam, err := db.GetTeam(ctx, s.assignment.SecurityTeam)
+	if errors.Is(err, ErrTeamNotFound) {
+		s.log.Warn("security team not found", "team_name", s.assignment.SecurityTeam)
+		return reviewers, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, reviewer := range reviewers {
+		if isTeamMember(securityTeam, reviewer) {
+			return reviewers, nil
+		}
+	}
+
+	securityIds, err := candidates(ctx, db, securityTeam, pullRequest, reviewers)
+	if err != nil {
+		return nil, err
+	}
+	if len(securityIds) == 0 {
+		s.log.Warn("no active security reviewer", "pr_id", pullRequest.PullRequestID,
+			"team_name", securityTeam.TeamName)
+		return reviewers, nil
+	}
+
+	return append(reviewers, securityIds[0]), nil
+}
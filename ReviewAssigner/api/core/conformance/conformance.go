@@ -0,0 +1,196 @@
+// Package conformance is the specification of core.DB. The Postgres adapter
+// is the reference implementation and every other backend must behave the
+// same way, which a backend checks by running the suite from a test:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, func(t *testing.T) core.DB {
+//			db, err := sqlite.New(slog.Default(), "sqlite://:memory:")
+//			if err != nil {
+//				t.Fatal(err)
+//			}
+//			if err := db.Migrate(); err != nil {
+//				t.Fatal(err)
+//			}
+//			t.Cleanup(func() { db.Close() })
+//			return db
+//		})
+//	}
+//
+// Backends that also implement core.IdempotencyStore are checked against it,
+// and AddUserTX is checked on backends that can begin a transaction for it
+// (see TxBeginner).
+package conformance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"review-assigner/core"
+	"sort"
+	"testing"
+	"time"
+)
+
+// TxBeginner starts the *sql.Tx that AddUserTX writes into.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Opener returns an empty, migrated database. It is called once per check.
+type Opener func(t *testing.T) core.DB
+
+var checks = []struct {
+	name string
+	run  func(t *testing.T, db core.DB)
+}{
+	{"Teams", testTeams},
+	{"TeamLead", testTeamLead},
+	{"BatchTeams", testBatchTeams},
+	{"Users", testUsers},
+	{"AddUserTX", testAddUserTX},
+	{"BatchUsers", testBatchUsers},
+	{"PullRequests", testPullRequests},
+	{"BatchPullRequests", testBatchPullRequests},
+	{"Merged", testMerged},
+	{"Approve", testApprove},
+	{"Reassign", testReassign},
+	{"ConcurrentReassign", testConcurrentReassign},
+	{"AddReviewer", testAddReviewer},
+	{"Assignments", testAssignments},
+	{"History", testHistory},
+	{"Reviews", testReviews},
+	{"ListPRs", testListPRs},
+	{"Stats", testStats},
+	{"PendingReviews", testPendingReviews},
+	{"StalePRs", testStalePRs},
+	{"Tokens", testTokens},
+	{"WithTx", testWithTx},
+	{"Idempotency", testIdempotency},
+	{"TeamNames", testTeamNames},
+	{"ImportUsers", testImportUsers},
+	{"ImportPR", testImportPR},
+	{"Tenants", testTenants},
+	{"Repositories", testRepositories},
+	{"PRNumbers", testPRNumbers},
+	{"PRMetadata", testPRMetadata},
+}
+
+func Run(t *testing.T, open Opener) {
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			check.run(t, open(t))
+		})
+	}
+}
+
+var ctx = context.Background()
+
+// seed creates two teams:
+//
+//	backend:  u1 (lead), u2, u3, u4 (inactive)
+//	frontend: u5, u6
+func seed(t *testing.T, db core.DB) {
+	t.Helper()
+
+	must(t, db.AddTeam(ctx, core.Team{
+		TeamName: "backend",
+		LeadID:   "u1",
+		Members: []core.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Carol", IsActive: true},
+			{UserID: "u4", Username: "Dave", IsActive: false},
+		},
+	}))
+	must(t, db.AddTeam(ctx, core.Team{
+		TeamName: "frontend",
+		Members: []core.TeamMember{
+			{UserID: "u5", Username: "Eve", IsActive: true},
+			{UserID: "u6", Username: "Frank", IsActive: true},
+		},
+	}))
+}
+
+func addPR(t *testing.T, db core.DB, id, author string, reviewers ...string) core.PullRequest {
+	t.Helper()
+
+	must(t, db.AddPR(ctx, core.PullRequest{
+		PullRequestID:     id,
+		PullRequestName:   "title of " + id,
+		AuthorID:          author,
+		AssignedReviewers: reviewers,
+	}))
+
+	pr, err := db.GetPRDetailsWithReviewers(ctx, id)
+	must(t, err)
+	return pr
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func mustFail(t *testing.T, err error, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+}
+
+func equal[T any](t *testing.T, what string, got, want T) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s: got %#v, want %#v", what, got, want)
+	}
+}
+
+func parseTime(t *testing.T, value *string) time.Time {
+	t.Helper()
+	if value == nil {
+		t.Fatal("timestamp is not set")
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, *value)
+	must(t, err)
+	return parsed
+}
+
+func sameTime(t *testing.T, what string, got *time.Time, want time.Time) {
+	t.Helper()
+	if got == nil || !got.Equal(want) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+}
+
+// now is truncated to what every backend can store.
+func now() time.Time {
+	return time.Now().Truncate(time.Millisecond)
+}
+
+func prIds(pullRequests []core.PullRequest) []string {
+	ids := make([]string, 0, len(pullRequests))
+	for _, pullRequest := range pullRequests {
+		ids = append(ids, pullRequest.PullRequestID)
+	}
+	return ids
+}
+
+func memberIds(team core.Team) []string {
+	ids := make([]string, 0, len(team.Members))
+	for _, member := range team.Members {
+		ids = append(ids, member.UserID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func reviewerIds(assignments []core.Assignment) []string {
+	ids := make([]string, 0, len(assignments))
+	for _, assignment := range assignments {
+		ids = append(ids, assignment.ReviewerID)
+	}
+	return ids
+}
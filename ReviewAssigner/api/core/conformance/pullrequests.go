@@ -0,0 +1,495 @@
+package conformance
+
+import (
+	"errors"
+	"review-assigner/core"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testPullRequests(t *testing.T, db core.DB) {
+	seed(t, db)
+
+	before := now().Add(-time.Minute)
+	pr := addPR(t, db, "p1", "u1", "u3", "u2")
+	equal(t, "id", pr.PullRequestID, "p1")
+	equal(t, "title", pr.PullRequestName, "title of p1")
+	equal(t, "author", pr.AuthorID, "u1")
+	equal(t, "status", pr.Status, "OPEN")
+	equal(t, "reviewers keep assignment order", pr.AssignedReviewers, []string{"u3", "u2"})
+	equal(t, "version", pr.Version, int64(1))
+	if createdAt := parseTime(t, pr.CreatedAt); createdAt.Before(before) {
+		t.Fatalf("created_at %v is before %v", createdAt, before)
+	}
+	if pr.MergedAt != nil {
+		t.Fatalf("open PR has merged_at %q", *pr.MergedAt)
+	}
+
+	pr = addPR(t, db, "p2", "u5")
+	equal(t, "no reviewers", len(pr.AssignedReviewers), 0)
+
+	mustFail(t, db.AddPR(ctx, core.PullRequest{PullRequestID: "p1", PullRequestName: "again", AuthorID: "u2"}),
+		core.ErrPRAAlreadyExists)
+
+	_, err := db.GetPRDetailsWithReviewers(ctx, "missing")
+	mustFail(t, err, core.ErrPRNotFound)
+}
+
+func testBatchPullRequests(t *testing.T, db core.DB) {
+	seed(t, db)
+	addPR(t, db, "p1", "u1", "u2", "u3")
+	addPR(t, db, "p2", "u5", "u6")
+	addPR(t, db, "p3", "u2")
+
+	prs, err := db.GetPRs(ctx, []string{"p2", "missing", "p1"})
+	must(t, err)
+	equal(t, "PRs are ordered by creation", prIds(prs), []string{"p1", "p2"})
+	equal(t, "reviewers", prs[0].AssignedReviewers, []string{"u2", "u3"})
+	equal(t, "reviewers", prs[1].AssignedReviewers, []string{"u6"})
+	equal(t, "version", prs[0].Version, int64(1))
+	equal(t, "status", prs[0].Status, "OPEN")
+
+	prs, err = db.GetPRs(ctx, nil)
+	must(t, err)
+	equal(t, "no PRs", len(prs), 0)
+}
+
+func testMerged(t *testing.T, db core.DB) {
+	seed(t, db)
+	addPR(t, db, "p1", "u1", "u2", "u3")
+	addPR(t, db, "p2", "u1", "u2")
+
+	pr, err := db.Merged(ctx, "p1", 1)
+	must(t, err)
+	equal(t, "status", pr.Status, "MERGED")
+	equal(t, "version", pr.Version, int64(2))
+	equal(t, "reviewers", pr.AssignedReviewers, []string{"u2", "u3"})
+	mergedAt := parseTime(t, pr.MergedAt)
+
+	pr, err = db.GetPRDetailsWithReviewers(ctx, "p1")
+	must(t, err)
+	equal(t, "stored status", pr.Status, "MERGED")
+	equal(t, "stored version", pr.Version, int64(2))
+	if !parseTime(t, pr.MergedAt).Equal(mergedAt) {
+		t.Fatalf("stored merged_at %q differs from returned %v", *pr.MergedAt, mergedAt)
+	}
+
+	_, err = db.Merged(ctx, "p1", 2)
+	mustFail(t, err, core.ErrPRConflict)
+
+	_, err = db.Merged(ctx, "p2", 7)
+	mustFail(t, err, core.ErrPRConflict)
+
+	_, err = db.Merged(ctx, "missing", 1)
+	mustFail(t, err, core.ErrPRNotFound)
+}
+
+func testApprove(t *testing.T, db core.DB) {
+	seed(t, db)
+	addPR(t, db, "p1", "u1", "u2", "u3")
+
+	must(t, db.Approve(ctx, "p1", "u2", 1))
+	assignments, err := db.GetAssignments(ctx, []string{"p1"})
+	must(t, err)
+	approvedAt := assignments["p1"][0].ApprovedAt
+	if approvedAt == nil {
+		t.Fatal("approval was not stored")
+	}
+	if assignments["p1"][1].ApprovedAt != nil {
+		t.Fatal("approval was stored for another reviewer")
+	}
+
+	// A repeated approval keeps the time of the first one.
+	must(t, db.Approve(ctx, "p1", "u2", 2))
+	assignments, err = db.GetAssignments(ctx, []string{"p1"})
+	must(t, err)
+	sameTime(t, "approved_at", assignments["p1"][0].ApprovedAt, *approvedAt)
+
+	pr, err := db.GetPRDetailsWithReviewers(ctx, "p1")
+	must(t, err)
+	equal(t, "version", pr.Version, int64(3))
+
+	mustFail(t, db.Approve(ctx, "p1", "u5", 3), core.ErrReviewerNotAssigned)
+	mustFail(t, db.Approve(ctx, "p1", "u3", 1), core.ErrPRConflict)
+	mustFail(t, db.Approve(ctx, "missing", "u2", 1), core.ErrPRNotFound)
+
+	pr, err = db.GetPRDetailsWithReviewers(ctx, "p1")
+	must(t, err)
+	equal(t, "failed approvals keep the version", pr.Version, int64(3))
+
+	// Version 0 skips the check.
+	must(t, db.Approve(ctx, "p1", "u3", 0))
+	pr, err = db.GetPRDetailsWithReviewers(ctx, "p1")
+	must(t, err)
+	equal(t, "version", pr.Version, int64(4))
+}
+
+func testReassign(t *testing.T, db core.DB) {
+	seed(t, db)
+	addPR(t, db, "p1", "u1", "u2", "u3")
+	must(t, db.Approve(ctx, "p1", "u2", 1))
+
+	must(t, db.Reassign(ctx, core.ReassignReviewer{PRId: "p1", UserID: "u2"}, "u5", 2))
+	pr, err := db.GetPRDetailsWithReviewers(ctx, "p1")
+	must(t, err)
+	equal(t, "replacement takes the old place", pr.AssignedReviewers, []string{"u5", "u3"})
+	equal(t, "version", pr.Version, int64(3))
+
+	assignments, err := db.GetAssignments(ctx, []string{"p1"})
+	must(t, err)
+	if assignments["p1"][0].ApprovedAt != nil {
+		t.Fatal("approval of the replaced reviewer was kept")
+	}
+
+	mustFail(t, db.Reassign(ctx, core.ReassignReviewer{PRId: "p1", UserID: "u2"}, "u6", 3),
+		core.ErrReviewerNotAssigned)
+	mustFail(t, db.Reassign(ctx, core.ReassignReviewer{PRId: "p1", UserID: "u3"}, "u5", 3),
+		core.ErrPRConflict)
+	mustFail(t, db.Reassign(ctx, core.ReassignReviewer{PRId: "p1", UserID: "u3"}, "u6", 1),
+		core.ErrPRConflict)
+	mustFail(t, db.Reassign(ctx, core.ReassignReviewer{PRId: "missing", UserID: "u3"}, "u6", 1),
+		core.ErrPRNotFound)
+
+	pr, err = db.GetPRDetailsWithReviewers(ctx, "p1")
+	must(t, err)
+	equal(t, "failed reassignments keep reviewers", pr.AssignedReviewers, []string{"u5", "u3"})
+	equal(t, "failed reassignments keep the version", pr.Version, int64(3))
+}
+
+// testConcurrentReassign replaces both reviewers of a PR at once, each in a
+// unit of work that reads the PR version first, as the service does.
+// Transactions that lose the race are retried or fail with ErrPRConflict,
+// never with a driver error, and the winners are all kept.
+func testConcurrentReassign(t *testing.T, db core.DB) {
+	seed(t, db)
+	addPR(t, db, "p1", "u1", "u2", "u3")
+
+	replacements := map[string]string{"u2": "u5", "u3": "u6"}
+	errs := make(map[string]error, len(replacements))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for old, replacement := range replacements {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := db.WithTx(ctx, func(tx core.DB) error {
+				pr, err := tx.GetPRDetailsWithReviewers(ctx, "p1")
+				if err != nil {
+					return err
+				}
+				return tx.Reassign(ctx, core.ReassignReviewer{PRId: "p1", UserID: old}, replacement, pr.Version)
+			})
+			mu.Lock()
+			errs[old] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	want := []string{"u2", "u3"}
+	applied := 0
+	for i, old := range want {
+		switch err := errs[old]; {
+		case err == nil:
+			want[i] = replacements[old]
+			applied++
+		case !errors.Is(err, core.ErrPRConflict):
+			t.Fatalf("reassign %s: got error %v, want nil or %v", old, err, core.ErrPRConflict)
+		}
+	}
+	if applied == 0 {
+		t.Fatal("no reassignment was applied")
+	}
+
+	pr, err := db.GetPRDetailsWithReviewers(ctx, "p1")
+	must(t, err)
+	equal(t, "reviewers", pr.AssignedReviewers, want)
+	equal(t, "version", pr.Version, int64(1+applied))
+}
+
+func testAddReviewer(t *testing.T, db core.DB) {
+	seed(t, db)
+	addPR(t, db, "p1", "u2", "u3")
+
+	must(t, db.AddReviewer(ctx, "p1", "u1"))
+	pr, err := db.GetPRDetailsWithReviewers(ctx, "p1")
+	must(t, err)
+	equal(t, "reviewer is appended", pr.AssignedReviewers, []string{"u3", "u1"})
+	equal(t, "version", pr.Version, int64(2))
+
+	must(t, db.AddReviewer(ctx, "p1", "u1"))
+	pr, err = db.GetPRDetailsWithReviewers(ctx, "p1")
+	must(t, err)
+	equal(t, "reviewer is added once", pr.AssignedReviewers, []string{"u3", "u1"})
+}
+
+func testAssignments(t *testing.T, db core.DB) {
+	seed(t, db)
+	addPR(t, db, "p1", "u1", "u2", "u3")
+	addPR(t, db, "p2", "u5", "u6")
+	addPR(t, db, "p3", "u5")
+
+	assignments, err := db.GetAssignments(ctx, []string{"p1", "p2", "p3", "missing"})
+	must(t, err)
+	equal(t, "PRs with reviewers", len(assignments), 2)
+	equal(t, "p1", reviewerIds(assignments["p1"]), []string{"u2", "u3"})
+	equal(t, "p2", reviewerIds(assignments["p2"]), []string{"u6"})
+	equal(t, "assignment", assignments["p2"][0], core.Assignment{PullRequestID: "p2", ReviewerID: "u6"})
+
+	assignments, err = db.GetAssignments(ctx, nil)
+	must(t, err)
+	equal(t, "no assignments", len(assignments), 0)
+}
+
+func testHistory(t *testing.T, db core.DB) {
+	seed(t, db)
+	addPR(t, db, "p1", "u1", "u2")
+
+	must(t, db.AddPRHistory(ctx, core.PRHistoryEntry{
+		PullRequestID: "p1", Event: core.HistoryCreated, ActorID: "u1", Details: "created",
+	}))
+	must(t, db.AddPRHistory(ctx, core.PRHistoryEntry{
+		PullRequestID: "p1", Event: core.HistoryEscalated, Details: "escalated",
+	}))
+	must(t, db.AddPRHistory(ctx, core.PRHistoryEntry{
+		PullRequestID: "p1", Event: core.HistoryApproved, ActorID: "u2",
+	}))
+
+	history, err := db.GetPRHistory(ctx, "p1")
+	must(t, err)
+	if len(history) != 3 {
+		t.Fatalf("expected 3 history entries, got %d", len(history))
+	}
+	for i, want := range []core.PRHistoryEntry{
+		{PullRequestID: "p1", Event: core.HistoryCreated, ActorID: "u1", Details: "created"},
+		{PullRequestID: "p1", Event: core.HistoryEscalated, Details: "escalated"},
+		{PullRequestID: "p1", Event: core.HistoryApproved, ActorID: "u2"},
+	} {
+		if history[i].CreatedAt.IsZero() {
+			t.Fatalf("entry %d has no created_at", i)
+		}
+		history[i].CreatedAt = time.Time{}
+		equal(t, "entry", history[i], want)
+	}
+
+	history, err = db.GetPRHistory(ctx, "missing")
+	must(t, err)
+	equal(t, "no history", len(history), 0)
+
+	if err := db.AddPRHistory(ctx, core.PRHistoryEntry{PullRequestID: "missing", Event: core.HistoryCreated}); err == nil {
+		t.Fatal("history was added to a missing PR")
+	}
+}
+
+func testReviews(t *testing.T, db core.DB) {
+	seed(t, db)
+	addPR(t, db, "p1", "u1", "u2", "u3")
+	addPR(t, db, "p2", "u3", "u2")
+	addPR(t, db, "p3", "u5", "u6")
+	_, err := db.Merged(ctx, "p1", 1)
+	must(t, err)
+
+	review, err := db.GetReview(ctx, "u2")
+	must(t, err)
+	equal(t, "user", review.UserID, "u2")
+	equal(t, "merged and open reviews", prIds(review.PullRequest), []string{"p1", "p2"})
+	equal(t, "status", review.PullRequest[0].Status, "MERGED")
+	equal(t, "author", review.PullRequest[1].AuthorID, "u3")
+
+	review, err = db.GetReview(ctx, "missing")
+	must(t, err)
+	equal(t, "user", review.UserID, "missing")
+	equal(t, "no reviews", len(review.PullRequest), 0)
+
+	reviews, err := db.GetReviewsByUsers(ctx, []string{"u2", "u3", "u6", "u1"})
+	must(t, err)
+	equal(t, "users with reviews", len(reviews), 3)
+	equal(t, "u2", prIds(reviews["u2"]), []string{"p1", "p2"})
+	equal(t, "u3", prIds(reviews["u3"]), []string{"p1"})
+	equal(t, "u6", prIds(reviews["u6"]), []string{"p3"})
+
+	reviews, err = db.GetReviewsByUsers(ctx, nil)
+	must(t, err)
+	equal(t, "no reviews", len(reviews), 0)
+}
+
+func testListPRs(t *testing.T, db core.DB) {
+	seed(t, db)
+	var prs []core.PullRequest
+	for _, pr := range []struct {
+		id, author string
+		reviewers  []string
+	}{
+		{"p1", "u1", []string{"u2", "u3"}},
+		{"p2", "u5", []string{"u6"}},
+		{"p3", "u2", []string{"u3"}},
+		{"p4", "u6", []string{"u5"}},
+		{"p5", "u1", []string{"u3", "u2"}},
+	} {
+		prs = append(prs, addPR(t, db, pr.id, pr.author, pr.reviewers...))
+	}
+	_, err := db.Merged(ctx, "p3", 1)
+	must(t, err)
+
+	list := func(filter core.PRFilter) []core.PullRequest {
+		t.Helper()
+		if filter.Limit == 0 {
+			filter.Limit = 10
+		}
+		result, err := db.ListPRs(ctx, filter)
+		must(t, err)
+		return result
+	}
+
+	all := list(core.PRFilter{})
+	equal(t, "all", prIds(all), []string{"p1", "p2", "p3", "p4", "p5"})
+	equal(t, "reviewers", all[4].AssignedReviewers, []string{"u3", "u2"})
+	equal(t, "version", all[2].Version, int64(2))
+	equal(t, "status", all[2].Status, "MERGED")
+
+	equal(t, "reviewer", prIds(list(core.PRFilter{ReviewerID: "u3"})), []string{"p1", "p3", "p5"})
+	equal(t, "author", prIds(list(core.PRFilter{AuthorID: "u1"})), []string{"p1", "p5"})
+	equal(t, "author team", prIds(list(core.PRFilter{TeamName: "frontend"})), []string{"p2", "p4"})
+	equal(t, "status", prIds(list(core.PRFilter{Status: "MERGED"})), []string{"p3"})
+	equal(t, "combined", prIds(list(core.PRFilter{TeamName: "backend", Status: "OPEN", ReviewerID: "u2"})),
+		[]string{"p1", "p5"})
+
+	third := parseTime(t, prs[2].CreatedAt)
+	equal(t, "created from", prIds(list(core.PRFilter{CreatedFrom: &third})), []string{"p3", "p4", "p5"})
+	equal(t, "created before", prIds(list(core.PRFilter{CreatedTo: &third})), []string{"p1", "p2"})
+
+	equal(t, "descending", prIds(list(core.PRFilter{Descending: true})), []string{"p5", "p4", "p3", "p2", "p1"})
+	equal(t, "limit", prIds(list(core.PRFilter{Limit: 2})), []string{"p1", "p2"})
+
+	after := core.PRCursor{CreatedAt: parseTime(t, prs[1].CreatedAt), ID: "p2"}
+	equal(t, "after cursor", prIds(list(core.PRFilter{After: &after, Limit: 2})), []string{"p3", "p4"})
+	after = core.PRCursor{CreatedAt: parseTime(t, prs[3].CreatedAt), ID: "p4"}
+	equal(t, "after cursor descending", prIds(list(core.PRFilter{After: &after, Descending: true})),
+		[]string{"p3", "p2", "p1"})
+
+	equal(t, "nothing", len(list(core.PRFilter{AuthorID: "missing"})), 0)
+}
+
+func testStats(t *testing.T, db core.DB) {
+	seed(t, db)
+
+	stats, err := db.GetUserReviewStats(ctx)
+	must(t, err)
+	equal(t, "no user stats", len(stats), 0)
+
+	addPR(t, db, "p1", "u1", "u2", "u3")
+	addPR(t, db, "p2", "u3", "u2")
+	addPR(t, db, "p3", "u5")
+
+	stats, err = db.GetUserReviewStats(ctx)
+	must(t, err)
+	equal(t, "user stats", stats, map[string]int{"u2": 2, "u3": 1})
+
+	stats, err = db.GetPRReviewerCountStats(ctx)
+	must(t, err)
+	equal(t, "PR stats", stats, map[string]int{"p1": 2, "p2": 1})
+}
+
+func testPendingReviews(t *testing.T, db core.DB) {
+	seed(t, db)
+	addPR(t, db, "p1", "u1", "u2", "u3")
+	addPR(t, db, "p2", "u3", "u2", "u4")
+	addPR(t, db, "p3", "u5", "u6")
+	addPR(t, db, "p4", "u6", "u5")
+	_, err := db.Merged(ctx, "p4", 1)
+	must(t, err)
+
+	must(t, db.Approve(ctx, "p1", "u2", 1))
+
+	// u2 has approved p1, u3 is away, u4 is inactive and u5 only reviews a
+	// merged PR.
+	until := now().Add(time.Hour)
+	_, err = db.SetOutOfOffice(ctx, "u3", &until)
+	must(t, err)
+	passed := now().Add(-time.Hour)
+	_, err = db.SetOutOfOffice(ctx, "u6", &passed)
+	must(t, err)
+
+	pending, err := db.GetPendingReviews(ctx, now())
+	must(t, err)
+	if len(pending) != 2 {
+		t.Fatalf("expected pending reviews of 2 users, got %d", len(pending))
+	}
+	equal(t, "u2", pending[0].User.UserID, "u2")
+	equal(t, "u2 name", pending[0].User.Username, "Bob")
+	equal(t, "u2 team", pending[0].User.TeamName, "backend")
+	equal(t, "u2 PRs", prIds(pending[0].PullRequests), []string{"p2"})
+	equal(t, "u6", pending[1].User.UserID, "u6")
+	sameTime(t, "u6 out of office", pending[1].User.OutOfOfficeUntil, passed)
+	equal(t, "u6 PRs", prIds(pending[1].PullRequests), []string{"p3"})
+
+	pending, err = db.GetPendingReviews(ctx, until)
+	must(t, err)
+	equal(t, "out of office ends", pending[1].User.UserID, "u3")
+}
+
+func testStalePRs(t *testing.T, db core.DB) {
+	seed(t, db)
+	addPR(t, db, "p1", "u1", "u2")
+	addPR(t, db, "p2", "u1", "u2")
+	addPR(t, db, "p3", "u1", "u2")
+	addPR(t, db, "p4", "u1", "u3", "u2")
+	addPR(t, db, "p5", "u1")
+	must(t, db.Approve(ctx, "p1", "u2", 1))
+	must(t, db.AddPRHistory(ctx, core.PRHistoryEntry{PullRequestID: "p2", Event: core.HistoryEscalated}))
+	_, err := db.Merged(ctx, "p3", 1)
+	must(t, err)
+
+	stale, err := db.GetStalePRs(ctx, time.Now().Add(time.Hour))
+	must(t, err)
+	equal(t, "stale", prIds(stale), []string{"p4", "p5"})
+	equal(t, "reviewers", stale[0].AssignedReviewers, []string{"u3", "u2"})
+	equal(t, "author", stale[0].AuthorID, "u1")
+
+	stale, err = db.GetStalePRs(ctx, time.Now().Add(-time.Hour))
+	must(t, err)
+	equal(t, "fresh", len(stale), 0)
+}
+
+func testPRMetadata(t *testing.T, db core.DB) {
+	seed(t, db)
+
+	pr := addPR(t, db, "p1", "u1", "u2")
+	equal(t, "no labels", len(pr.Labels), 0)
+	equal(t, "default priority", pr.Priority, core.PriorityNormal)
+
+	must(t, db.AddPR(ctx, core.PullRequest{
+		PullRequestID: "p2", PullRequestName: "title of p2", AuthorID: "u1", AssignedReviewers: []string{"u2"},
+		Labels: []string{"security", "backend"}, LinesAdded: 120, LinesRemoved: 30, FilesChanged: 7,
+		Priority: core.PriorityUrgent,
+	}))
+	pr, err := db.GetPRDetailsWithReviewers(ctx, "p2")
+	must(t, err)
+	equal(t, "labels keep their order", pr.Labels, []string{"security", "backend"})
+	equal(t, "size", []int{pr.LinesAdded, pr.LinesRemoved, pr.FilesChanged}, []int{120, 30, 7})
+	equal(t, "priority", pr.Priority, core.PriorityUrgent)
+
+	prs, err := db.ListPRs(ctx, core.PRFilter{Limit: 10})
+	must(t, err)
+	equal(t, "listed labels", prs[1].Labels, []string{"security", "backend"})
+
+	review, err := db.GetReview(ctx, "u2")
+	must(t, err)
+	equal(t, "review priority", review.PullRequest[1].Priority, core.PriorityUrgent)
+
+	merged, err := db.Merged(ctx, "p2", 1)
+	must(t, err)
+	equal(t, "merged lines", merged.LinesAdded, 120)
+
+	must(t, db.ImportPR(ctx, core.PRSnapshot{PullRequest: core.PullRequest{
+		PullRequestID: "p1", PullRequestName: "title of p1", AuthorID: "u1", Status: "OPEN", Version: 1,
+		Labels: []string{"docs"}, FilesChanged: 1, Priority: core.PriorityLow,
+	}}))
+	pr, err = db.GetPRDetailsWithReviewers(ctx, "p1")
+	must(t, err)
+	equal(t, "imported labels", pr.Labels, []string{"docs"})
+	equal(t, "imported priority", pr.Priority, core.PriorityLow)
+}
@@ -0,0 +1,109 @@
+package conformance
+
+import (
+	"review-assigner/core"
+	"testing"
+)
+
+func testRepositories(t *testing.T, db core.DB) {
+	seed(t, db)
+
+	must(t, db.AddRepository(ctx, core.Repository{Name: "api", Teams: []string{"frontend", "backend"}}))
+	must(t, db.AddRepository(ctx, core.Repository{Name: "web"}))
+	mustFail(t, db.AddRepository(ctx, core.Repository{Name: "api"}), core.ErrRepositoryExists)
+
+	repository, err := db.GetRepository(ctx, "api")
+	must(t, err)
+	equal(t, "teams", repository.Teams, []string{"backend", "frontend"})
+	repository, err = db.GetRepository(ctx, "web")
+	must(t, err)
+	equal(t, "no teams", len(repository.Teams), 0)
+	_, err = db.GetRepository(ctx, "docs")
+	mustFail(t, err, core.ErrRepositoryNotFound)
+
+	must(t, db.LinkRepository(ctx, "web", "frontend"))
+	must(t, db.LinkRepository(ctx, "web", "frontend"))
+	repositories, err := db.GetRepositories(ctx)
+	must(t, err)
+	equal(t, "repositories", repositories, []core.Repository{
+		{Name: "api", Teams: []string{"backend", "frontend"}},
+		{Name: "web", Teams: []string{"frontend"}},
+	})
+
+	must(t, db.ImportRepository(ctx, core.Repository{Name: "api", Teams: []string{"frontend"}}))
+	must(t, db.ImportRepository(ctx, core.Repository{Name: "docs"}))
+	repositories, err = db.GetRepositories(ctx)
+	must(t, err)
+	equal(t, "imported repositories", repositories, []core.Repository{
+		{Name: "api", Teams: []string{"frontend"}},
+		{Name: "docs"},
+		{Name: "web", Teams: []string{"frontend"}},
+	})
+}
+
+func testPRNumbers(t *testing.T, db core.DB) {
+	seed(t, db)
+	must(t, db.AddRepository(ctx, core.Repository{Name: "api"}))
+	must(t, db.AddRepository(ctx, core.Repository{Name: "web"}))
+
+	for _, pr := range []core.PullRequest{
+		{PullRequestID: "a42", Repository: "api", Number: 42},
+		{PullRequestID: "w42", Repository: "web", Number: 42},
+		{PullRequestID: "p1"},
+	} {
+		pr.PullRequestName = "title of " + pr.PullRequestID
+		pr.AuthorID = "u1"
+		pr.AssignedReviewers = []string{"u2"}
+		must(t, db.AddPR(ctx, pr))
+	}
+	mustFail(t, db.AddPR(ctx, core.PullRequest{
+		PullRequestID: "a42b", PullRequestName: "again", AuthorID: "u1", Repository: "api", Number: 42,
+	}), core.ErrPRAAlreadyExists)
+
+	prId, err := db.FindPR(ctx, "web", 42)
+	must(t, err)
+	equal(t, "found", prId, "w42")
+	_, err = db.FindPR(ctx, "web", 43)
+	mustFail(t, err, core.ErrPRNotFound)
+	_, err = db.FindPR(core.WithTenant(ctx, "acme"), "api", 42)
+	mustFail(t, err, core.ErrPRNotFound)
+
+	pr, err := db.GetPRDetailsWithReviewers(ctx, "a42")
+	must(t, err)
+	equal(t, "repository", pr.Repository, "api")
+	equal(t, "number", pr.Number, int64(42))
+	pr, err = db.GetPRDetailsWithReviewers(ctx, "p1")
+	must(t, err)
+	equal(t, "no repository", pr.Repository, "")
+	equal(t, "no number", pr.Number, int64(0))
+
+	prs, err := db.ListPRs(ctx, core.PRFilter{Repository: "api", Limit: 10})
+	must(t, err)
+	equal(t, "listing by repository", prIds(prs), []string{"a42"})
+	equal(t, "listed number", prs[0].Number, int64(42))
+
+	review, err := db.GetReview(ctx, "u2")
+	must(t, err)
+	equal(t, "reviews", prIds(review.PullRequest), []string{"a42", "w42", "p1"})
+	equal(t, "review repository", review.PullRequest[1].Repository, "web")
+
+	merged, err := db.Merged(ctx, "w42", 1)
+	must(t, err)
+	equal(t, "merged number", merged.Number, int64(42))
+
+	prs, err = db.GetPRs(ctx, []string{"a42"})
+	must(t, err)
+	equal(t, "batch repository", prs[0].Repository, "api")
+
+	must(t, db.ImportPR(ctx, core.PRSnapshot{PullRequest: core.PullRequest{
+		PullRequestID: "p1", PullRequestName: "moved", AuthorID: "u1", Status: "OPEN", Version: 1,
+		Repository: "api", Number: 7,
+	}}))
+	prId, err = db.FindPR(ctx, "api", 7)
+	must(t, err)
+	equal(t, "imported number", prId, "p1")
+	mustFail(t, db.ImportPR(ctx, core.PRSnapshot{PullRequest: core.PullRequest{
+		PullRequestID: "p2", PullRequestName: "clash", AuthorID: "u1", Status: "OPEN", Version: 1,
+		Repository: "api", Number: 7,
+	}}), core.ErrInvalidSnapshot)
+}
@@ -0,0 +1,133 @@
+package conformance
+
+import (
+	"review-assigner/core"
+	"testing"
+	"time"
+)
+
+func testTeamNames(t *testing.T, db core.DB) {
+	names, err := db.GetTeamNames(ctx)
+	must(t, err)
+	equal(t, "no teams", len(names), 0)
+
+	seed(t, db)
+	must(t, db.ImportTeam(ctx, "analytics"))
+	must(t, db.ImportTeam(ctx, "backend"))
+
+	names, err = db.GetTeamNames(ctx)
+	must(t, err)
+	equal(t, "names are ordered", names, []string{"analytics", "backend", "frontend"})
+
+	team, err := db.GetTeam(ctx, "backend")
+	must(t, err)
+	equal(t, "existing team keeps its lead", team.LeadID, "u1")
+	equal(t, "existing team keeps its members", memberIds(team), []string{"u1", "u2", "u3", "u4"})
+}
+
+func testImportUsers(t *testing.T, db core.DB) {
+	seed(t, db)
+
+	until := now().Add(time.Hour)
+	must(t, db.ImportUser(ctx, core.User{
+		UserID: "u2", Username: "Robert", TeamName: "frontend", IsActive: false, OutOfOfficeUntil: &until,
+	}))
+	must(t, db.ImportUser(ctx, core.User{UserID: "u7", Username: "Grace", TeamName: "frontend", IsActive: true}))
+
+	user, err := db.GetUser(ctx, "u2")
+	must(t, err)
+	equal(t, "replaced user", user.Username, "Robert")
+	equal(t, "team", user.TeamName, "frontend")
+	equal(t, "active", user.IsActive, false)
+	sameTime(t, "out of office", user.OutOfOfficeUntil, until)
+
+	user, err = db.GetUser(ctx, "u7")
+	must(t, err)
+	equal(t, "new user", user, core.User{UserID: "u7", Username: "Grace", TeamName: "frontend", IsActive: true})
+
+	if err := db.ImportUser(ctx, core.User{UserID: "u8", Username: "Heidi", TeamName: "missing"}); err == nil {
+		t.Fatal("user was imported into a missing team")
+	}
+}
+
+func testImportPR(t *testing.T, db core.DB) {
+	seed(t, db)
+	addPR(t, db, "p1", "u1", "u2", "u3")
+	must(t, db.AddPRHistory(ctx, core.PRHistoryEntry{PullRequestID: "p1", Event: core.HistoryCreated}))
+
+	createdAt := "2024-03-01T10:00:00Z"
+	mergedAt := "2024-03-02T12:30:00.5Z"
+	approvedAt := time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)
+	historyAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	snapshot := core.PRSnapshot{
+		PullRequest: core.PullRequest{
+			PullRequestID:   "p1",
+			PullRequestName: "imported",
+			AuthorID:        "u5",
+			Status:          "MERGED",
+			CreatedAt:       &createdAt,
+			MergedAt:        &mergedAt,
+			Version:         4,
+		},
+		Reviewers: []core.Assignment{
+			{PullRequestID: "p1", ReviewerID: "u6", ApprovedAt: &approvedAt},
+			{PullRequestID: "p1", ReviewerID: "u1"},
+		},
+		History: []core.PRHistoryEntry{
+			{PullRequestID: "p1", Event: core.HistoryCreated, ActorID: "u5", CreatedAt: historyAt},
+			{PullRequestID: "p1", Event: core.HistoryMerged, Details: "done", CreatedAt: historyAt.Add(time.Hour)},
+		},
+	}
+
+	// Importing twice gives the same result.
+	for range 2 {
+		must(t, db.ImportPR(ctx, snapshot))
+
+		pr, err := db.GetPRDetailsWithReviewers(ctx, "p1")
+		must(t, err)
+		equal(t, "title", pr.PullRequestName, "imported")
+		equal(t, "author", pr.AuthorID, "u5")
+		equal(t, "status", pr.Status, "MERGED")
+		equal(t, "version", pr.Version, int64(4))
+		equal(t, "reviewers are replaced", pr.AssignedReviewers, []string{"u6", "u1"})
+		if !parseTime(t, pr.CreatedAt).Equal(parseTime(t, &createdAt)) {
+			t.Fatalf("created_at: got %q, want %q", *pr.CreatedAt, createdAt)
+		}
+		if !parseTime(t, pr.MergedAt).Equal(parseTime(t, &mergedAt)) {
+			t.Fatalf("merged_at: got %q, want %q", *pr.MergedAt, mergedAt)
+		}
+
+		assignments, err := db.GetAssignments(ctx, []string{"p1"})
+		must(t, err)
+		sameTime(t, "approved_at", assignments["p1"][0].ApprovedAt, approvedAt)
+		if assignments["p1"][1].ApprovedAt != nil {
+			t.Fatal("pending reviewer has approved_at")
+		}
+
+		history, err := db.GetPRHistory(ctx, "p1")
+		must(t, err)
+		if len(history) != 2 {
+			t.Fatalf("history is not replaced: %#v", history)
+		}
+		equal(t, "actor", history[0].ActorID, "u5")
+		equal(t, "details", history[1].Details, "done")
+		if !history[1].CreatedAt.Equal(historyAt.Add(time.Hour)) {
+			t.Fatalf("history created_at: got %v", history[1].CreatedAt)
+		}
+	}
+
+	must(t, db.ImportPR(ctx, core.PRSnapshot{PullRequest: core.PullRequest{
+		PullRequestID: "p2", PullRequestName: "new", AuthorID: "u2", Status: "OPEN", Version: 1,
+	}}))
+	pr, err := db.GetPRDetailsWithReviewers(ctx, "p2")
+	must(t, err)
+	parseTime(t, pr.CreatedAt)
+	equal(t, "no reviewers", len(pr.AssignedReviewers), 0)
+
+	err = db.ImportPR(ctx, core.PRSnapshot{PullRequest: core.PullRequest{
+		PullRequestID: "p3", PullRequestName: "broken", AuthorID: "missing", Status: "OPEN", Version: 1,
+	}})
+	if err == nil {
+		t.Fatal("PR with a missing author was imported")
+	}
+}
@@ -0,0 +1,174 @@
+package conformance
+
+import (
+	"review-assigner/core"
+	"testing"
+	"time"
+)
+
+func testTeams(t *testing.T, db core.DB) {
+	seed(t, db)
+
+	team, err := db.GetTeam(ctx, "backend")
+	must(t, err)
+	equal(t, "team name", team.TeamName, "backend")
+	equal(t, "lead", team.LeadID, "u1")
+	equal(t, "members", memberIds(team), []string{"u1", "u2", "u3", "u4"})
+	for _, member := range team.Members {
+		if member.UserID == "u4" {
+			equal(t, "u4", member, core.TeamMember{UserID: "u4", Username: "Dave", IsActive: false})
+		}
+		if member.UserID == "u2" {
+			equal(t, "u2", member, core.TeamMember{UserID: "u2", Username: "Bob", IsActive: true})
+		}
+	}
+
+	team, err = db.GetTeam(ctx, "frontend")
+	must(t, err)
+	equal(t, "team without lead", team.LeadID, "")
+
+	mustFail(t, db.AddTeam(ctx, core.Team{TeamName: "backend"}), core.ErrTeamAlreadyExists)
+
+	_, err = db.GetTeam(ctx, "missing")
+	mustFail(t, err, core.ErrTeamNotFound)
+
+	user, err := db.GetUser(ctx, "u5")
+	must(t, err)
+	equal(t, "member team", user.TeamName, "frontend")
+}
+
+func testTeamLead(t *testing.T, db core.DB) {
+	seed(t, db)
+
+	must(t, db.SetTeamLead(ctx, "backend", "u2"))
+	team, err := db.GetTeam(ctx, "backend")
+	must(t, err)
+	equal(t, "lead", team.LeadID, "u2")
+
+	mustFail(t, db.SetTeamLead(ctx, "missing", "u2"), core.ErrTeamNotFound)
+
+	must(t, db.SetTeamLead(ctx, "backend", ""))
+	team, err = db.GetTeam(ctx, "backend")
+	must(t, err)
+	equal(t, "cleared lead", team.LeadID, "")
+}
+
+func testBatchTeams(t *testing.T, db core.DB) {
+	seed(t, db)
+
+	teams, err := db.GetTeams(ctx, []string{"frontend", "missing", "backend"})
+	must(t, err)
+	if len(teams) != 2 {
+		t.Fatalf("expected 2 teams, got %d", len(teams))
+	}
+	equal(t, "teams are ordered by name", []string{teams[0].TeamName, teams[1].TeamName}, []string{"backend", "frontend"})
+	equal(t, "lead", teams[0].LeadID, "u1")
+	equal(t, "members", memberIds(teams[0]), []string{"u1", "u2", "u3", "u4"})
+	equal(t, "members", memberIds(teams[1]), []string{"u5", "u6"})
+
+	teams, err = db.GetTeams(ctx, nil)
+	must(t, err)
+	equal(t, "no teams", len(teams), 0)
+}
+
+func testUsers(t *testing.T, db core.DB) {
+	seed(t, db)
+
+	user, err := db.GetUser(ctx, "u2")
+	must(t, err)
+	equal(t, "user", user, core.User{UserID: "u2", Username: "Bob", TeamName: "backend", IsActive: true})
+
+	_, err = db.GetUser(ctx, "missing")
+	mustFail(t, err, core.ErrUserNotFound)
+
+	user, err = db.IsActive(ctx, "u2", false)
+	must(t, err)
+	equal(t, "deactivated user", user, core.User{UserID: "u2", Username: "Bob", TeamName: "backend", IsActive: false})
+	user, err = db.GetUser(ctx, "u2")
+	must(t, err)
+	equal(t, "stored activity", user.IsActive, false)
+
+	_, err = db.IsActive(ctx, "missing", true)
+	mustFail(t, err, core.ErrUserNotFound)
+
+	until := now().Add(48 * time.Hour)
+	user, err = db.SetOutOfOffice(ctx, "u3", &until)
+	must(t, err)
+	equal(t, "user", user.UserID, "u3")
+	sameTime(t, "returned out of office", user.OutOfOfficeUntil, until)
+	user, err = db.GetUser(ctx, "u3")
+	must(t, err)
+	sameTime(t, "stored out of office", user.OutOfOfficeUntil, until)
+
+	user, err = db.SetOutOfOffice(ctx, "u3", nil)
+	must(t, err)
+	if user.OutOfOfficeUntil != nil {
+		t.Fatalf("out of office was not cleared: %v", user.OutOfOfficeUntil)
+	}
+
+	_, err = db.SetOutOfOffice(ctx, "missing", &until)
+	mustFail(t, err, core.ErrUserNotFound)
+}
+
+func testAddUserTX(t *testing.T, db core.DB) {
+	beginner, ok := db.(TxBeginner)
+	if !ok {
+		t.Skip("backend does not implement conformance.TxBeginner")
+	}
+	seed(t, db)
+
+	tx, err := beginner.BeginTx(ctx, nil)
+	must(t, err)
+	must(t, db.AddUserTX(ctx, tx, core.User{UserID: "u7", Username: "Grace", TeamName: "frontend", IsActive: true}))
+	must(t, tx.Commit())
+
+	user, err := db.GetUser(ctx, "u7")
+	must(t, err)
+	equal(t, "user", user, core.User{UserID: "u7", Username: "Grace", TeamName: "frontend", IsActive: true})
+
+	tx, err = beginner.BeginTx(ctx, nil)
+	must(t, err)
+	must(t, db.AddUserTX(ctx, tx, core.User{UserID: "u8", Username: "Heidi", TeamName: "frontend"}))
+	must(t, tx.Rollback())
+
+	_, err = db.GetUser(ctx, "u8")
+	mustFail(t, err, core.ErrUserNotFound)
+
+	tx, err = beginner.BeginTx(ctx, nil)
+	must(t, err)
+	err = db.AddUserTX(ctx, tx, core.User{UserID: "u1", Username: "Alice", TeamName: "backend", IsActive: true})
+	tx.Rollback()
+	if err == nil {
+		t.Fatal("adding an existing user succeeded")
+	}
+
+	acme := core.WithTenant(ctx, "acme")
+	must(t, db.AddTeam(acme, core.Team{TeamName: "backend"}))
+
+	tx, err = beginner.BeginTx(ctx, nil)
+	must(t, err)
+	must(t, db.AddUserTX(acme, tx, core.User{UserID: "u1", Username: "Ivan", TeamName: "backend"}))
+	must(t, tx.Commit())
+
+	user, err = db.GetUser(acme, "u1")
+	must(t, err)
+	equal(t, "user of another tenant", user, core.User{UserID: "u1", Username: "Ivan", TeamName: "backend"})
+	user, err = db.GetUser(ctx, "u1")
+	must(t, err)
+	equal(t, "username", user.Username, "Alice")
+}
+
+func testBatchUsers(t *testing.T, db core.DB) {
+	seed(t, db)
+
+	users, err := db.GetUsers(ctx, []string{"u5", "missing", "u1"})
+	must(t, err)
+	equal(t, "users are ordered by id", users, []core.User{
+		{UserID: "u1", Username: "Alice", TeamName: "backend", IsActive: true},
+		{UserID: "u5", Username: "Eve", TeamName: "frontend", IsActive: true},
+	})
+
+	users, err = db.GetUsers(ctx, nil)
+	must(t, err)
+	equal(t, "no users", len(users), 0)
+}
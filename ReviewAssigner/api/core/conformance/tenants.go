@@ -0,0 +1,121 @@
+package conformance
+
+import (
+	"review-assigner/core"
+	"testing"
+	"time"
+)
+
+// testTenants seeds the same ids into two tenants and checks that no read or
+// write reaches across them.
+func testTenants(t *testing.T, db core.DB) {
+	acme := core.WithTenant(ctx, "acme")
+
+	seed(t, db)
+	addPR(t, db, "p1", "u1", "u2", "u3")
+
+	must(t, db.AddTeam(acme, core.Team{
+		TeamName: "backend",
+		Members: []core.TeamMember{
+			{UserID: "u1", Username: "Mallory", IsActive: true},
+			{UserID: "u2", Username: "Niaj", IsActive: true},
+			{UserID: "u9", Username: "Olivia", IsActive: true},
+		},
+	}))
+	must(t, db.AddPR(acme, core.PullRequest{
+		PullRequestID: "p1", PullRequestName: "acme p1", AuthorID: "u9", AssignedReviewers: []string{"u1"},
+	}))
+	must(t, db.AddPR(acme, core.PullRequest{
+		PullRequestID: "p2", PullRequestName: "acme p2", AuthorID: "u9", AssignedReviewers: []string{"u1", "u2"},
+	}))
+
+	tenants, err := db.GetTenants(ctx)
+	must(t, err)
+	equal(t, "tenants", tenants, []string{"acme", core.DefaultTenant})
+
+	team, err := db.GetTeam(acme, "backend")
+	must(t, err)
+	equal(t, "members", memberIds(team), []string{"u1", "u2", "u9"})
+	equal(t, "lead", team.LeadID, "")
+	_, err = db.GetTeam(acme, "frontend")
+	mustFail(t, err, core.ErrTeamNotFound)
+
+	user, err := db.GetUser(acme, "u1")
+	must(t, err)
+	equal(t, "user", user.Username, "Mallory")
+	_, err = db.GetUser(acme, "u5")
+	mustFail(t, err, core.ErrUserNotFound)
+	_, err = db.GetUser(ctx, "u9")
+	mustFail(t, err, core.ErrUserNotFound)
+
+	review, err := db.GetReview(ctx, "u1")
+	must(t, err)
+	equal(t, "default reviews", len(review.PullRequest), 0)
+	review, err = db.GetReview(acme, "u1")
+	must(t, err)
+	equal(t, "acme reviews", prIds(review.PullRequest), []string{"p1", "p2"})
+	equal(t, "acme title", review.PullRequest[0].PullRequestName, "acme p1")
+
+	reviews, err := db.GetReviewsByUsers(acme, []string{"u2", "u3"})
+	must(t, err)
+	equal(t, "acme batch reviews", prIds(reviews["u2"]), []string{"p2"})
+	equal(t, "no reviews of other tenants", len(reviews["u3"]), 0)
+
+	stats, err := db.GetUserReviewStats(ctx)
+	must(t, err)
+	equal(t, "default user stats", stats, map[string]int{"u2": 1, "u3": 1})
+	stats, err = db.GetUserReviewStats(acme)
+	must(t, err)
+	equal(t, "acme user stats", stats, map[string]int{"u1": 2, "u2": 1})
+
+	stats, err = db.GetPRReviewerCountStats(ctx)
+	must(t, err)
+	equal(t, "default pr stats", stats, map[string]int{"p1": 2})
+	stats, err = db.GetPRReviewerCountStats(acme)
+	must(t, err)
+	equal(t, "acme pr stats", stats, map[string]int{"p1": 1, "p2": 2})
+
+	prs, err := db.ListPRs(ctx, core.PRFilter{Limit: 10})
+	must(t, err)
+	equal(t, "default listing", prIds(prs), []string{"p1"})
+	equal(t, "default reviewers", prs[0].AssignedReviewers, []string{"u2", "u3"})
+
+	prs, err = db.GetPRs(acme, []string{"p1", "p2"})
+	must(t, err)
+	equal(t, "acme prs", prIds(prs), []string{"p1", "p2"})
+	equal(t, "acme reviewers", prs[0].AssignedReviewers, []string{"u1"})
+
+	pending, err := db.GetPendingReviews(acme, time.Now())
+	must(t, err)
+	equal(t, "acme pending reviewers", len(pending), 2)
+	equal(t, "acme pending reviewer", pending[0].User.Username, "Mallory")
+
+	_, err = db.IsActive(acme, "u3", false)
+	mustFail(t, err, core.ErrUserNotFound)
+	_, err = db.Merged(acme, "p1", 1)
+	must(t, err)
+	pr, err := db.GetPRDetailsWithReviewers(ctx, "p1")
+	must(t, err)
+	equal(t, "merge stays in its tenant", pr.Status, "OPEN")
+
+	mustFail(t, db.AddTeam(acme, core.Team{TeamName: "backend"}), core.ErrTeamAlreadyExists)
+	mustFail(t, db.AddPR(acme, core.PullRequest{
+		PullRequestID: "p2", PullRequestName: "again", AuthorID: "u9",
+	}), core.ErrPRAAlreadyExists)
+	if err := db.AddPR(acme, core.PullRequest{
+		PullRequestID: "p3", PullRequestName: "foreign author", AuthorID: "u5",
+	}); err == nil {
+		t.Fatal("pr was created with an author from another tenant")
+	}
+
+	token, err := db.AddToken(acme, core.Token{Name: "acme", Scopes: []core.Scope{core.ScopeAdmin}}, "hash-acme")
+	must(t, err)
+	equal(t, "token tenant", token.Tenant, "acme")
+	token, err = db.GetTokenByHash(ctx, "hash-acme")
+	must(t, err)
+	equal(t, "tokens are found from any tenant", token.Tenant, "acme")
+	tokens, err := db.GetTokens(ctx)
+	must(t, err)
+	equal(t, "default tokens", len(tokens), 0)
+	mustFail(t, db.DeleteToken(ctx, token.ID), core.ErrTokenNotFound)
+}
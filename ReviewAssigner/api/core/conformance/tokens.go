@@ -0,0 +1,173 @@
+package conformance
+
+import (
+	"errors"
+	"review-assigner/core"
+	"testing"
+	"time"
+)
+
+func testTokens(t *testing.T, db core.DB) {
+	seed(t, db)
+
+	before := now().Add(-time.Minute)
+	user, err := db.AddToken(ctx, core.Token{
+		Name:   "laptop",
+		UserID: "u1",
+		Scopes: []core.Scope{core.ScopeUsersRead, core.ScopePRsWrite},
+	}, "hash-1")
+	must(t, err)
+	if user.ID == 0 {
+		t.Fatal("token id was not assigned")
+	}
+	if user.CreatedAt.Before(before) {
+		t.Fatalf("created_at %v is before %v", user.CreatedAt, before)
+	}
+	if user.LastUsedAt != nil {
+		t.Fatal("new token has last_used_at")
+	}
+	equal(t, "scopes keep their order", user.Scopes, []core.Scope{core.ScopeUsersRead, core.ScopePRsWrite})
+
+	bot, err := db.AddToken(ctx, core.Token{Name: "ci", Scopes: []core.Scope{core.ScopePRsWrite}}, "hash-2")
+	must(t, err)
+	equal(t, "bot user", bot.UserID, "")
+
+	token, err := db.GetTokenByHash(ctx, "hash-1")
+	must(t, err)
+	equal(t, "token", token, user)
+
+	_, err = db.GetTokenByHash(ctx, "missing")
+	mustFail(t, err, core.ErrTokenNotFound)
+
+	usedAt := now()
+	must(t, db.TouchToken(ctx, bot.ID, usedAt))
+	token, err = db.GetTokenByHash(ctx, "hash-2")
+	must(t, err)
+	sameTime(t, "last_used_at", token.LastUsedAt, usedAt)
+
+	tokens, err := db.GetTokens(ctx)
+	must(t, err)
+	if len(tokens) != 2 || tokens[0].ID != user.ID || tokens[1].ID != bot.ID {
+		t.Fatalf("tokens are not ordered by id: %#v", tokens)
+	}
+
+	must(t, db.DeleteToken(ctx, user.ID))
+	mustFail(t, db.DeleteToken(ctx, user.ID), core.ErrTokenNotFound)
+	_, err = db.GetTokenByHash(ctx, "hash-1")
+	mustFail(t, err, core.ErrTokenNotFound)
+}
+
+func testWithTx(t *testing.T, db core.DB) {
+	seed(t, db)
+
+	must(t, db.WithTx(ctx, func(tx core.DB) error {
+		if err := tx.SetTeamLead(ctx, "backend", "u2"); err != nil {
+			return err
+		}
+		team, err := tx.GetTeam(ctx, "backend")
+		if err != nil {
+			return err
+		}
+		equal(t, "lead inside the transaction", team.LeadID, "u2")
+		return nil
+	}))
+	team, err := db.GetTeam(ctx, "backend")
+	must(t, err)
+	equal(t, "committed lead", team.LeadID, "u2")
+
+	rollback := errors.New("rollback")
+	err = db.WithTx(ctx, func(tx core.DB) error {
+		if err := tx.SetTeamLead(ctx, "backend", "u3"); err != nil {
+			return err
+		}
+		// A nested transaction joins the outer one.
+		if err := tx.WithTx(ctx, func(tx core.DB) error {
+			return tx.AddPR(ctx, core.PullRequest{PullRequestID: "p1", PullRequestName: "p1", AuthorID: "u1"})
+		}); err != nil {
+			return err
+		}
+		return rollback
+	})
+	mustFail(t, err, rollback)
+
+	team, err = db.GetTeam(ctx, "backend")
+	must(t, err)
+	equal(t, "rolled back lead", team.LeadID, "u2")
+	_, err = db.GetPRDetailsWithReviewers(ctx, "p1")
+	mustFail(t, err, core.ErrPRNotFound)
+
+	err = db.WithTx(ctx, func(tx core.DB) error {
+		_, err := tx.IsActive(ctx, "missing", false)
+		return err
+	})
+	mustFail(t, err, core.ErrUserNotFound)
+}
+
+func testIdempotency(t *testing.T, db core.DB) {
+	store, ok := db.(core.IdempotencyStore)
+	if !ok {
+		t.Skip("backend does not implement core.IdempotencyStore")
+	}
+
+	expiresAt := now().Add(time.Hour)
+	record := core.IdempotencyRecord{Scope: "member:u1", Key: "key-1", RequestHash: "hash", ExpiresAt: expiresAt}
+
+	reserved, created, err := store.ReserveIdempotencyKey(ctx, record)
+	must(t, err)
+	equal(t, "new key is reserved", created, true)
+	equal(t, "reserved record", reserved, record)
+
+	pending, created, err := store.ReserveIdempotencyKey(ctx, record)
+	must(t, err)
+	equal(t, "reserved key is not reserved again", created, false)
+	equal(t, "pending", pending.Completed(), false)
+	equal(t, "request hash", pending.RequestHash, "hash")
+
+	other := record
+	other.Scope = "member:u2"
+	_, created, err = store.ReserveIdempotencyKey(ctx, other)
+	must(t, err)
+	equal(t, "keys are scoped", created, true)
+
+	// The hash is only final once the request body has been read.
+	record.RequestHash = "hash with body"
+	record.Status = 201
+	record.Headers = map[string]string{"Content-Type": "application/json"}
+	record.Body = []byte(`{"ok":true}`)
+	must(t, store.CompleteIdempotencyKey(ctx, record))
+
+	stored, created, err := store.ReserveIdempotencyKey(ctx, core.IdempotencyRecord{
+		Scope: "member:u1", Key: "key-1", RequestHash: "other", ExpiresAt: expiresAt,
+	})
+	must(t, err)
+	equal(t, "completed key is not reserved", created, false)
+	equal(t, "status", stored.Status, 201)
+	equal(t, "headers", stored.Headers, record.Headers)
+	equal(t, "body", string(stored.Body), `{"ok":true}`)
+	equal(t, "request hash", stored.RequestHash, "hash with body")
+	if !stored.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("expires_at: got %v, want %v", stored.ExpiresAt, expiresAt)
+	}
+
+	must(t, store.ReleaseIdempotencyKey(ctx, "member:u2", "key-1"))
+	_, created, err = store.ReserveIdempotencyKey(ctx, other)
+	must(t, err)
+	equal(t, "released key is reserved again", created, true)
+
+	// An expired key is taken over.
+	expired := core.IdempotencyRecord{Scope: "bot:", Key: "key-2", RequestHash: "old", ExpiresAt: now().Add(-time.Hour)}
+	_, _, err = store.ReserveIdempotencyKey(ctx, expired)
+	must(t, err)
+	expired.RequestHash = "new"
+	expired.ExpiresAt = expiresAt
+	_, created, err = store.ReserveIdempotencyKey(ctx, expired)
+	must(t, err)
+	equal(t, "expired key is taken over", created, true)
+
+	deleted, err := store.DeleteExpiredIdempotencyKeys(ctx, expiresAt.Add(time.Minute))
+	must(t, err)
+	equal(t, "deleted keys", deleted, int64(3))
+	deleted, err = store.DeleteExpiredIdempotencyKeys(ctx, expiresAt.Add(time.Minute))
+	must(t, err)
+	equal(t, "nothing left", deleted, int64(0))
+}
@@ -12,4 +12,18 @@ var (
 	ErrPRAlreadyMerged        = errors.New("cannot reassign on merged PR")
 	ErrReviewerNotAssigned    = errors.New("reviewer is not assigned to this PR")
 	ErrNoReplacementCandidate = errors.New("no active replacement candidate in team")
+	ErrLeadNotMember          = errors.New("team lead must be a member of the team")
+	ErrTokenNotFound          = errors.New("api token not found")
+	ErrInvalidScope           = errors.New("unknown or missing token scope")
+	ErrForbidden              = errors.New("operation is not permitted")
+	ErrInvalidCursor          = errors.New("invalid pagination cursor")
+	ErrPreconditionFailed     = errors.New("PR version does not match If-Match")
+	ErrPRConflict             = errors.New("PR was modified concurrently")
+	ErrInvalidSnapshot        = errors.New("invalid snapshot")
+	ErrInvalidUser            = errors.New("user_id, username and team_name must be 1 to 100 characters")
+	ErrRepositoryExists       = errors.New("repository already exists")
+	ErrRepositoryNotFound     = errors.New("repository not found")
+	ErrInvalidRepository      = errors.New("repository must be 1 to 100 characters and come with a positive PR number")
+	ErrInvalidPRMetadata      = errors.New("labels must be up to 20 distinct words of 1 to 50 characters, " +
+		"sizes must not be negative and priority must be LOW, NORMAL, HIGH or URGENT")
 )
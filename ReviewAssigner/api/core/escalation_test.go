@@ -0,0 +1,102 @@
+package core_test
+
+import (
+	"context"
+	"review-assigner/core"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestEscalateStale(t *testing.T) {
+	sent := &outbox{}
+	service := openService(t, sent, core.EscalationPolicy{Threshold: time.Millisecond, AddLeadAsReviewer: true})
+
+	ctx := context.Background()
+	createTeam(t, ctx, service, core.Team{TeamName: "backend", LeadID: "u4", Members: members("u1", "u2", "u3", "u4")})
+	createTeam(t, ctx, service, core.Team{TeamName: "frontend", Members: members("u5", "u6")})
+
+	createPR(t, ctx, service, "pr-1", "u1") // u2 and u3 review
+	createPR(t, ctx, service, "pr-2", "u1")
+	createPR(t, ctx, service, "pr-3", "u1")
+	createPR(t, ctx, service, "pr-4", "u5") // frontend has no lead
+	if _, err := service.Approve(ctx, "pr-2", "u2"); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if _, err := service.Merged(ctx, "pr-3"); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if err := service.EscalateStale(ctx); err != nil {
+		t.Fatalf("escalate: %v", err)
+	}
+
+	escalated := sent.take(core.NotificationEscalated)
+	if len(escalated) != 1 || escalated[0].PullRequest.PullRequestID != "pr-1" {
+		t.Fatalf("got escalations %+v, want pr-1 only", escalated)
+	}
+	if !slices.Equal(escalated[0].Reviewers, []string{"u4"}) || escalated[0].TeamName != "backend" {
+		t.Errorf("escalated to %v of %s, want the backend lead u4", escalated[0].Reviewers, escalated[0].TeamName)
+	}
+
+	details, err := service.GetPRDetails(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("get details: %v", err)
+	}
+	if !slices.Contains(details.PullRequest.AssignedReviewers, "u4") {
+		t.Errorf("lead was not added as a reviewer: %v", details.PullRequest.AssignedReviewers)
+	}
+	if !slices.ContainsFunc(details.History, func(entry core.PRHistoryEntry) bool { return entry.Event == core.HistoryEscalated }) {
+		t.Errorf("escalation is missing from the history: %+v", details.History)
+	}
+
+	// An escalated PR is not escalated again on the next run.
+	if err := service.EscalateStale(ctx); err != nil {
+		t.Fatalf("escalate again: %v", err)
+	}
+	if again := sent.take(core.NotificationEscalated); len(again) != 0 {
+		t.Errorf("escalated again: %+v", again)
+	}
+}
+
+func TestEscalateStaleKeepsReviewers(t *testing.T) {
+	sent := &outbox{}
+	service := openService(t, sent, core.EscalationPolicy{Threshold: time.Millisecond})
+
+	ctx := context.Background()
+	createTeam(t, ctx, service, core.Team{TeamName: "backend", LeadID: "u4", Members: members("u1", "u2", "u3", "u4")})
+	createPR(t, ctx, service, "pr-1", "u1")
+	time.Sleep(10 * time.Millisecond)
+
+	if err := service.EscalateStale(ctx); err != nil {
+		t.Fatalf("escalate: %v", err)
+	}
+	if escalated := sent.take(core.NotificationEscalated); len(escalated) != 1 {
+		t.Fatalf("got %d escalations, want 1", len(escalated))
+	}
+
+	pullRequest, err := service.GetPR(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("get pr: %v", err)
+	}
+	if !slices.Equal(pullRequest.AssignedReviewers, []string{"u2", "u3"}) {
+		t.Errorf("got reviewers %v, want the lead left out", pullRequest.AssignedReviewers)
+	}
+}
+
+func TestEscalateStaleDisabled(t *testing.T) {
+	sent := &outbox{}
+	service := openService(t, sent, core.EscalationPolicy{})
+
+	ctx := context.Background()
+	createTeam(t, ctx, service, core.Team{TeamName: "backend", LeadID: "u4", Members: members("u1", "u2", "u3", "u4")})
+	createPR(t, ctx, service, "pr-1", "u1")
+
+	if err := service.EscalateStale(ctx); err != nil {
+		t.Fatalf("escalate: %v", err)
+	}
+	if escalated := sent.take(core.NotificationEscalated); len(escalated) != 0 {
+		t.Errorf("escalated without a threshold: %+v", escalated)
+	}
+}
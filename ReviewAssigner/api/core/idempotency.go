@@ -0,0 +1,27 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+type IdempotencyRecord struct {
+	Scope       string
+	Key         string
+	RequestHash string
+	Status      int
+	Headers     map[string]string
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+func (r IdempotencyRecord) Completed() bool {
+	return r.Status != 0
+}
+
+type IdempotencyStore interface {
+	ReserveIdempotencyKey(context.Context, IdempotencyRecord) (IdempotencyRecord, bool, error)
+	CompleteIdempotencyKey(context.Context, IdempotencyRecord) error
+	ReleaseIdempotencyKey(context.Context, string, string) error
+	DeleteExpiredIdempotencyKeys(context.Context, time.Time) (int64, error)
+}
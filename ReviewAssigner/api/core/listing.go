@@ -0,0 +1,116 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+type PRCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+func (c PRCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodePRCursor(value string) (PRCursor, error) {
+	var cursor PRCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return PRCursor{}, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return PRCursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// PRFilter selects pull requests ordered by (created_at, id), which is unique
+// and therefore gives a stable order for keyset pagination.
+type PRFilter struct {
+	ReviewerID  string
+	AuthorID    string
+	Repository  string
+	TeamName    string
+	Status      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Descending  bool
+	After       *PRCursor
+	Limit       int
+}
+
+type PRPage struct {
+	PullRequests []PullRequest
+	NextCursor   string
+}
+
+func (s *Service) ListPRs(ctx context.Context, filter PRFilter) (PRPage, error) {
+	s.log.Info("listing pull requests", "reviewer_id", filter.ReviewerID, "author_id", filter.AuthorID,
+		"repository", filter.Repository, "team_name", filter.TeamName, "status", filter.Status)
+
+	if err := s.authorizeListing(ctx, filter); err != nil {
+		return PRPage{}, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	filter.Limit = min(filter.Limit, MaxPageSize)
+
+	limit := filter.Limit
+	filter.Limit++
+
+	pullRequests, err := s.db.ListPRs(ctx, filter)
+	if err != nil {
+		return PRPage{}, err
+	}
+
+	page := PRPage{PullRequests: pullRequests}
+	if len(pullRequests) > limit {
+		page.PullRequests = pullRequests[:limit]
+
+		last := page.PullRequests[limit-1]
+		createdAt, err := time.Parse(time.RFC3339Nano, *last.CreatedAt)
+		if err != nil {
+			return PRPage{}, err
+		}
+		page.NextCursor = PRCursor{CreatedAt: createdAt, ID: last.PullRequestID}.Encode()
+	}
+
+	return page, nil
+}
+
+// authorizeListing checks the narrowest filter the caller passed: a member
+// may list their own reviews or PRs, and those of the teams they lead, but
+// not every PR in the tenant.
+func (s *Service) authorizeListing(ctx context.Context, filter PRFilter) error {
+	userId := filter.ReviewerID
+	if userId == "" {
+		userId = filter.AuthorID
+	}
+
+	switch {
+	case userId != "":
+		user, err := s.db.GetUser(ctx, userId)
+		if err != nil {
+			return err
+		}
+		return s.authorize(ctx, s.db, rule{users: []string{user.UserID}, team: user.TeamName, bots: true})
+	case filter.TeamName != "":
+		return s.authorize(ctx, s.db, rule{team: filter.TeamName, bots: true})
+	default:
+		return s.authorize(ctx, s.db, rule{bots: true})
+	}
+}
@@ -1,5 +1,7 @@
 package core
 
+import "time"
+
 type UserStatus string
 
 const (
@@ -15,24 +17,58 @@ type TeamMember struct {
 
 type Team struct {
 	TeamName string
+	LeadID   string
 	Members  []TeamMember
 }
 
 type User struct {
-	UserID   string
-	Username string
-	TeamName string
-	IsActive bool
+	UserID           string
+	Username         string
+	TeamName         string
+	IsActive         bool
+	OutOfOfficeUntil *time.Time
+}
+
+// UserUpdate changes only the fields that are set. OutOfOfficeUntil is applied
+// when SetOutOfOffice is true, so that a nil period can clear it.
+type UserUpdate struct {
+	IsActive         *bool
+	SetOutOfOffice   bool
+	OutOfOfficeUntil *time.Time
 }
 
 type PullRequest struct {
 	PullRequestID     string
+	Repository        string
+	Number            int64
 	PullRequestName   string
 	AuthorID          string
 	Status            string
+	Labels            []string
+	LinesAdded        int
+	LinesRemoved      int
+	FilesChanged      int
+	Priority          Priority
 	AssignedReviewers []string
 	CreatedAt         *string
 	MergedAt          *string
+	Version           int64
+}
+
+type Priority string
+
+const (
+	PriorityLow    Priority = "LOW"
+	PriorityNormal Priority = "NORMAL"
+	PriorityHigh   Priority = "HIGH"
+	PriorityUrgent Priority = "URGENT"
+)
+
+// Repository groups pull requests whose numbers are only unique inside it.
+// Teams linked to a repository own its reviews.
+type Repository struct {
+	Name  string
+	Teams []string
 }
 
 type UserPullRequest struct {
@@ -40,6 +76,69 @@ type UserPullRequest struct {
 	PullRequest []PullRequest
 }
 
+type PendingReviews struct {
+	User         User
+	PullRequests []PullRequest
+}
+
+type Assignment struct {
+	PullRequestID string
+	ReviewerID    string
+	ApprovedAt    *time.Time
+}
+
+type HistoryEvent string
+
+const (
+	HistoryCreated    HistoryEvent = "CREATED"
+	HistoryApproved   HistoryEvent = "APPROVED"
+	HistoryReassigned HistoryEvent = "REASSIGNED"
+	HistoryMerged     HistoryEvent = "MERGED"
+	HistoryEscalated  HistoryEvent = "ESCALATED"
+)
+
+type PRHistoryEntry struct {
+	PullRequestID string
+	Event         HistoryEvent
+	ActorID       string
+	Details       string
+	CreatedAt     time.Time
+}
+
+type ReviewState string
+
+const (
+	ReviewPending  ReviewState = "PENDING"
+	ReviewApproved ReviewState = "APPROVED"
+)
+
+type ReviewerDetails struct {
+	User       User
+	State      ReviewState
+	ApprovedAt *time.Time
+}
+
+type PRDetails struct {
+	PullRequest PullRequest
+	Reviewers   []ReviewerDetails
+	History     []PRHistoryEntry
+}
+
+type EscalationPolicy struct {
+	Threshold         time.Duration
+	AddLeadAsReviewer bool
+}
+
+// AssignmentPolicy decides how labels, size and priority of a new pull
+// request change its reviewers. Zero thresholds disable the extra reviewer
+// for large pull requests, an empty SecurityTeam disables security reviews.
+type AssignmentPolicy struct {
+	LargeLines    int
+	LargeFiles    int
+	SecurityLabel string
+	SecurityTeam  string
+}
+
 type ReassignReviewer struct {
 	PRId   string
 	UserID string
@@ -0,0 +1,21 @@
+package core
+
+type NotificationKind string
+
+const (
+	NotificationAssigned   NotificationKind = "assigned"
+	NotificationReassigned NotificationKind = "reassigned"
+	NotificationReminder   NotificationKind = "reminder"
+	NotificationEscalated  NotificationKind = "escalated"
+)
+
+type Notification struct {
+	Kind         NotificationKind
+	Tenant       string
+	TeamName     string
+	PullRequest  PullRequest
+	PullRequests []PullRequest
+	Reviewers    []string
+	OldReviewer  string
+	Reason       string
+}
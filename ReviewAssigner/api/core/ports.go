@@ -3,29 +3,70 @@ package core
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 type Assigner interface {
 	CreateTeam(context.Context, Team) (Team, error)
 	GetTeam(context.Context, string) (Team, error)
+	SetTeamLead(context.Context, string, string) (Team, error)
+	GetUser(context.Context, string) (User, error)
 	IsActive(context.Context, string, bool) (User, error)
+	SetOutOfOffice(context.Context, string, *time.Time) (User, error)
 	CreatePR(context.Context, PullRequest) (PullRequest, error)
+	GetPR(context.Context, string) (PullRequest, error)
+	Approve(context.Context, string, string) (PullRequest, error)
 	Merged(context.Context, string) (PullRequest, error)
 	Reassign(context.Context, ReassignReviewer) (PullRequest, string, error)
 	GetReview(context.Context, string) (UserPullRequest, error)
 }
 
 type DB interface {
+	WithTx(context.Context, func(DB) error) error
 	AddUserTX(context.Context, *sql.Tx, User) error
 	AddTeam(context.Context, Team) error
 	AddPR(context.Context, PullRequest) error
 	GetTeam(context.Context, string) (Team, error)
+	GetTeamNames(context.Context) ([]string, error)
+	GetTenants(context.Context) ([]string, error)
+	SetTeamLead(context.Context, string, string) error
 	GetUser(context.Context, string) (User, error)
 	IsActive(context.Context, string, bool) (User, error)
-	Merged(context.Context, string) (PullRequest, error)
-	Reassign(context.Context, ReassignReviewer, string) error
+	SetOutOfOffice(context.Context, string, *time.Time) (User, error)
+	Merged(context.Context, string, int64) (PullRequest, error)
+	Reassign(context.Context, ReassignReviewer, string, int64) error
+	AddReviewer(context.Context, string, string) error
+	Approve(context.Context, string, string, int64) error
+	AddPRHistory(context.Context, PRHistoryEntry) error
+	GetPRHistory(context.Context, string) ([]PRHistoryEntry, error)
+	GetStalePRs(context.Context, time.Time) ([]PullRequest, error)
 	GetPRDetailsWithReviewers(context.Context, string) (PullRequest, error)
+	FindPR(context.Context, string, int64) (string, error)
+	AddRepository(context.Context, Repository) error
+	GetRepository(context.Context, string) (Repository, error)
+	GetRepositories(context.Context) ([]Repository, error)
+	LinkRepository(context.Context, string, string) error
 	GetReview(context.Context, string) (UserPullRequest, error)
+	ListPRs(context.Context, PRFilter) ([]PullRequest, error)
 	GetUserReviewStats(context.Context) (map[string]int, error)
 	GetPRReviewerCountStats(context.Context) (map[string]int, error)
+	GetPendingReviews(context.Context, time.Time) ([]PendingReviews, error)
+	GetTeams(context.Context, []string) ([]Team, error)
+	GetUsers(context.Context, []string) ([]User, error)
+	GetPRs(context.Context, []string) ([]PullRequest, error)
+	GetReviewsByUsers(context.Context, []string) (map[string][]PullRequest, error)
+	GetAssignments(context.Context, []string) (map[string][]Assignment, error)
+	AddToken(context.Context, Token, string) (Token, error)
+	GetTokenByHash(context.Context, string) (Token, error)
+	GetTokens(context.Context) ([]Token, error)
+	TouchToken(context.Context, int64, time.Time) error
+	DeleteToken(context.Context, int64) error
+	ImportTeam(context.Context, string) error
+	ImportUser(context.Context, User) error
+	ImportRepository(context.Context, Repository) error
+	ImportPR(context.Context, PRSnapshot) error
+}
+
+type Notifier interface {
+	Notify(context.Context, Notification) error
 }
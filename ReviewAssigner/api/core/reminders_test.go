@@ -0,0 +1,56 @@
+package core_test
+
+import (
+	"context"
+	"review-assigner/core"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendReminders(t *testing.T) {
+	sent := &outbox{}
+	service := openService(t, sent, core.EscalationPolicy{})
+
+	ctx := context.Background()
+	acme := core.WithTenant(ctx, "acme")
+	createTeam(t, ctx, service, core.Team{TeamName: "backend", Members: members("u1", "u2", "u3")})
+	createTeam(t, acme, service, core.Team{TeamName: "backend", Members: members("a1", "a2")})
+
+	createPR(t, ctx, service, "pr-1", "u1") // u2 and u3 review
+	createPR(t, ctx, service, "pr-2", "u2") // u1 and u3 review
+	createPR(t, ctx, service, "pr-3", "u3") // u1 and u2 review, merged below
+	createPR(t, acme, service, "pr-1", "a1")
+
+	if _, err := service.Approve(ctx, "pr-1", "u3"); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if _, err := service.Merged(ctx, "pr-3"); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	until := time.Now().Add(24 * time.Hour)
+	if _, err := service.SetOutOfOffice(ctx, "u1", &until); err != nil {
+		t.Fatalf("set out of office: %v", err)
+	}
+
+	if err := service.SendReminders(ctx); err != nil {
+		t.Fatalf("send reminders: %v", err)
+	}
+
+	var got []string
+	for _, reminder := range sent.take(core.NotificationReminder) {
+		var prIds []string
+		for _, pullRequest := range reminder.PullRequests {
+			prIds = append(prIds, pullRequest.PullRequestID)
+		}
+		got = append(got, reminder.Tenant+"/"+strings.Join(reminder.Reviewers, ",")+": "+strings.Join(prIds, ","))
+	}
+	slices.Sort(got)
+
+	// u1 is out of office, u3 approved pr-1 and pr-3 is merged.
+	want := []string{"acme/a2: pr-1", "default/u2: pr-1", "default/u3: pr-2"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got reminders %q, want %q", got, want)
+	}
+}
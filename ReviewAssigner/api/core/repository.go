@@ -0,0 +1,122 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+)
+
+func validRepository(name string) bool {
+	return name != "" && len(name) <= maxNameLength
+}
+
+// newPRID names a pull request that is only known by its number, in the 16
+// characters that pull request ids are limited to.
+func newPRID() (string, error) {
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func (s *Service) CreateRepository(ctx context.Context, repository Repository) (Repository, error) {
+	s.log.Info("creating repository", "repository", repository.Name, "teams", repository.Teams)
+
+	if err := s.authorize(ctx, s.db, rule{}); err != nil {
+		return Repository{}, err
+	}
+
+	if !validRepository(repository.Name) {
+		return Repository{}, ErrInvalidRepository
+	}
+
+	slices.Sort(repository.Teams)
+	repository.Teams = slices.Compact(repository.Teams)
+
+	err := s.db.WithTx(ctx, func(db DB) error {
+		teams, err := db.GetTeams(ctx, repository.Teams)
+		if err != nil {
+			return err
+		}
+		if len(teams) != len(repository.Teams) {
+			return ErrTeamNotFound
+		}
+
+		return db.AddRepository(ctx, repository)
+	})
+	if err != nil {
+		return Repository{}, err
+	}
+
+	return repository, nil
+}
+
+func (s *Service) GetRepository(ctx context.Context, name string) (Repository, error) {
+	s.log.Info("get repository", "repository", name)
+
+	return s.db.GetRepository(ctx, name)
+}
+
+// LinkRepository hands the reviews of a repository to a team. Admins and the
+// lead of the team may link it.
+func (s *Service) LinkRepository(ctx context.Context, name string, teamName string) (Repository, error) {
+	s.log.Info("linking repository", "repository", name, "team_name", teamName)
+
+	var repository Repository
+	err := s.db.WithTx(ctx, func(db DB) error {
+		if _, err := db.GetTeam(ctx, teamName); err != nil {
+			return err
+		}
+
+		if err := s.authorize(ctx, db, rule{team: teamName}); err != nil {
+			return err
+		}
+
+		if _, err := db.GetRepository(ctx, name); err != nil {
+			return err
+		}
+
+		if err := db.LinkRepository(ctx, name, teamName); err != nil {
+			return err
+		}
+
+		var err error
+		repository, err = db.GetRepository(ctx, name)
+		return err
+	})
+	if err != nil {
+		return Repository{}, err
+	}
+
+	return repository, nil
+}
+
+// FindPR returns the id of the pull request with the given number in the
+// repository, so that every PR operation can address it either way.
+func (s *Service) FindPR(ctx context.Context, repository string, number int64) (string, error) {
+	if !validRepository(repository) || number < 1 {
+		return "", ErrInvalidRepository
+	}
+
+	return s.db.FindPR(ctx, repository, number)
+}
+
+// reviewTeam picks the team that reviews a pull request: the author's own
+// team, unless the repository is linked only to other teams, in which case
+// the first of them by name.
+func reviewTeam(ctx context.Context, db DB, author User, repository string) (Team, error) {
+	teamName := author.TeamName
+	if repository != "" {
+		repo, err := db.GetRepository(ctx, repository)
+		if err != nil {
+			return Team{}, err
+		}
+		if len(repo.Teams) > 0 && !slices.Contains(repo.Teams, author.TeamName) {
+			teamName = repo.Teams[0]
+		}
+	}
+
+	return db.GetTeam(ctx, teamName)
+}
@@ -2,23 +2,69 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
+	"time"
 )
 
 type Service struct {
-	log *slog.Logger
-	db  DB
+	log        *slog.Logger
+	db         DB
+	notifier   Notifier
+	escalation EscalationPolicy
+	assignment AssignmentPolicy
 }
 
-func NewService(log *slog.Logger, db DB) (*Service, error) {
+func NewService(log *slog.Logger, db DB, notifier Notifier, escalation EscalationPolicy,
+	assignment AssignmentPolicy) (*Service, error) {
 	return &Service{
-		log: log,
-		db:  db}, nil
+		log:        log,
+		db:         db,
+		notifier:   notifier,
+		escalation: escalation,
+		assignment: assignment}, nil
+}
+
+func (s *Service) record(ctx context.Context, prId string, event HistoryEvent, actorId, details string) {
+	err := s.db.AddPRHistory(ctx, PRHistoryEntry{
+		PullRequestID: prId,
+		Event:         event,
+		ActorID:       actorId,
+		Details:       details,
+	})
+	if err != nil {
+		s.log.Warn("failed to record pr history", "pr_id", prId, "event", event, "error", err)
+	}
+}
+
+func (s *Service) notify(ctx context.Context, notification Notification) {
+	if s.notifier == nil {
+		return
+	}
+	notification.Tenant = TenantFrom(ctx)
+
+	if err := s.notifier.Notify(ctx, notification); err != nil {
+		s.log.Warn("failed to send notification",
+			"kind", notification.Kind,
+			"pr_id", notification.PullRequest.PullRequestID,
+			"error", err)
+	}
 }
 
 func (s *Service) CreateTeam(ctx context.Context, team Team) (Team, error) {
 	s.log.Info("create team", "team_name", team.TeamName)
 
+	if err := s.authorize(ctx, s.db, rule{}); err != nil {
+		return Team{}, err
+	}
+
+	if team.LeadID != "" && !isTeamMember(team, team.LeadID) {
+		return Team{}, ErrLeadNotMember
+	}
+
 	err := s.db.AddTeam(ctx, team)
 	if err != nil {
 		return Team{}, err
@@ -39,10 +85,74 @@ func (s *Service) GetTeam(ctx context.Context, teamName string) (Team, error) {
 
 }
 
+func (s *Service) SetTeamLead(ctx context.Context, teamName string, userId string) (Team, error) {
+	s.log.Info("setting team lead", "team_name", teamName, "user_id", userId)
+
+	if err := s.authorize(ctx, s.db, rule{team: teamName}); err != nil {
+		return Team{}, err
+	}
+
+	var team Team
+	err := s.db.WithTx(ctx, func(db DB) error {
+		var err error
+		team, err = db.GetTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		// An empty userId removes the lead.
+		if userId != "" && !isTeamMember(team, userId) {
+			return ErrLeadNotMember
+		}
+
+		return db.SetTeamLead(ctx, teamName, userId)
+	})
+	if err != nil {
+		return Team{}, err
+	}
+
+	team.LeadID = userId
+
+	return team, nil
+}
+
+func isTeamMember(team Team, userId string) bool {
+	for _, member := range team.Members {
+		if member.UserID == userId {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) GetUser(ctx context.Context, userId string) (User, error) {
+	s.log.Info("get user", "user_id", userId)
+
+	user, err := s.db.GetUser(ctx, userId)
+	if err != nil {
+		return User{}, err
+	}
+
+	return user, nil
+}
+
 func (s *Service) IsActive(ctx context.Context, userId string, userStatus bool) (User, error) {
 	s.log.Info("setting active status for user", "user_id", userId, "new_status", userStatus)
 
-	user, err := s.db.IsActive(ctx, userId, userStatus)
+	var user User
+	err := s.db.WithTx(ctx, func(db DB) error {
+		current, err := db.GetUser(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		if err := s.authorize(ctx, db, rule{team: current.TeamName}); err != nil {
+			return err
+		}
+
+		user, err = db.IsActive(ctx, userId, userStatus)
+		return err
+	})
 	if err != nil {
 		return User{}, err
 	}
@@ -50,131 +160,354 @@ func (s *Service) IsActive(ctx context.Context, userId string, userStatus bool)
 	return user, nil
 }
 
-func (s *Service) CreatePR(ctx context.Context, pullRequest PullRequest) (PullRequest, error) {
-	s.log.Info("creating pull request", "pr_id", pullRequest.PullRequestID, "author_id", pullRequest.AuthorID)
+func (s *Service) SetOutOfOffice(ctx context.Context, userId string, until *time.Time) (User, error) {
+	s.log.Info("setting out of office for user", "user_id", userId, "until", until)
+
+	var user User
+	err := s.db.WithTx(ctx, func(db DB) error {
+		current, err := db.GetUser(ctx, userId)
+		if err != nil {
+			return err
+		}
 
-	user, err := s.db.GetUser(ctx, pullRequest.AuthorID)
+		if err := s.authorize(ctx, db, rule{users: []string{userId}, team: current.TeamName}); err != nil {
+			return err
+		}
+
+		user, err = db.SetOutOfOffice(ctx, userId, until)
+		return err
+	})
 	if err != nil {
-		return PullRequest{}, err
+		return User{}, err
 	}
-	team, err := s.db.GetTeam(ctx, user.TeamName)
+
+	return user, nil
+}
+
+func (s *Service) UpdateUser(ctx context.Context, userId string, update UserUpdate) (User, error) {
+	s.log.Info("updating user", "user_id", userId)
+
+	var user User
+	err := s.db.WithTx(ctx, func(db DB) error {
+		var err error
+		user, err = db.GetUser(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		// Users may set their own absence, activity is up to their team.
+		r := rule{team: user.TeamName}
+		if update.IsActive == nil {
+			r.users = []string{userId}
+		}
+		if err := s.authorize(ctx, db, r); err != nil {
+			return err
+		}
+
+		if update.IsActive != nil {
+			user, err = db.IsActive(ctx, userId, *update.IsActive)
+			if err != nil {
+				return err
+			}
+		}
+		if update.SetOutOfOffice {
+			user, err = db.SetOutOfOffice(ctx, userId, update.OutOfOfficeUntil)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		return PullRequest{}, err
+		return User{}, err
 	}
-	var reviewers []string
 
-	for _, teamMember := range team.Members {
-		if teamMember.IsActive && teamMember.UserID != pullRequest.AuthorID {
-			reviewers = append(reviewers, teamMember.UserID)
+	return user, nil
+}
+
+func (s *Service) CreatePR(ctx context.Context, pullRequest PullRequest) (PullRequest, error) {
+	s.log.Info("creating pull request", "pr_id", pullRequest.PullRequestID,
+		"repository", pullRequest.Repository, "number", pullRequest.Number, "author_id", pullRequest.AuthorID,
+		"labels", pullRequest.Labels, "priority", pullRequest.Priority)
+
+	if !validMetadata(pullRequest) {
+		return PullRequest{}, ErrInvalidPRMetadata
+	}
+	if pullRequest.Priority == "" {
+		pullRequest.Priority = PriorityNormal
+	}
+
+	if pullRequest.Repository != "" || pullRequest.Number != 0 {
+		if !validRepository(pullRequest.Repository) || pullRequest.Number < 1 {
+			return PullRequest{}, ErrInvalidRepository
 		}
-		if len(reviewers) == 2 {
-			break
+		if pullRequest.PullRequestID == "" {
+			id, err := newPRID()
+			if err != nil {
+				return PullRequest{}, err
+			}
+			pullRequest.PullRequestID = id
 		}
 	}
-	if len(reviewers) == 0 {
-		return PullRequest{}, ErrNotEnoughReviewers
-	}
 
-	pullRequest.AssignedReviewers = reviewers
+	var team Team
+	err := s.db.WithTx(ctx, func(db DB) error {
+		user, err := db.GetUser(ctx, pullRequest.AuthorID)
+		if err != nil {
+			return err
+		}
 
-	err = s.db.AddPR(ctx, pullRequest)
+		if err := s.authorize(ctx, db, rule{users: []string{user.UserID}, team: user.TeamName, bots: true}); err != nil {
+			return err
+		}
+
+		team, err = reviewTeam(ctx, db, user, pullRequest.Repository)
+		if err != nil {
+			return err
+		}
+
+		pullRequest.AssignedReviewers, err = s.pickReviewers(ctx, db, team, pullRequest)
+		if err != nil {
+			return err
+		}
+		pullRequest.Version = 1
+
+		return db.AddPR(ctx, pullRequest)
+	})
 	if err != nil {
 		return PullRequest{}, err
 	}
 
+	reviewers := pullRequest.AssignedReviewers
+
 	s.log.Info("successfully created pull request",
 		"pr_id", pullRequest.PullRequestID,
 		"reviewers_count", len(reviewers))
 
+	s.record(ctx, pullRequest.PullRequestID, HistoryCreated, pullRequest.AuthorID,
+		"assigned reviewers: "+strings.Join(reviewers, ", "))
+
+	s.notify(ctx, Notification{
+		Kind:        NotificationAssigned,
+		TeamName:    team.TeamName,
+		PullRequest: pullRequest,
+		Reviewers:   reviewers,
+	})
+
 	return pullRequest, nil
 }
 
-func (s *Service) Merged(ctx context.Context, prId string) (PullRequest, error) {
-	s.log.Info("merged pr", "prId", prId)
+func (s *Service) GetPR(ctx context.Context, prId string) (PullRequest, error) {
+	s.log.Info("get pull request", "pr_id", prId)
 
 	pullRequest, err := s.db.GetPRDetailsWithReviewers(ctx, prId)
 	if err != nil {
 		return PullRequest{}, err
 	}
 
-	if pullRequest.Status == "MERGED" {
-		return PullRequest{}, ErrPRAlreadyMerged
-	}
-
-	pullRequest, err = s.db.Merged(ctx, prId)
-	if err != nil {
+	if err := s.authorizePRs(ctx, s.db, []PullRequest{pullRequest}); err != nil {
 		return PullRequest{}, err
 	}
 
 	return pullRequest, nil
 }
 
-func (s *Service) Reassign(ctx context.Context, reassignReviewer ReassignReviewer) (PullRequest, string, error) {
-	s.log.Info("reassigning pull request reviewer",
-		"reviewer_id", reassignReviewer.UserID,
-		"pull_request_id", reassignReviewer.PRId)
+func (s *Service) GetPRDetails(ctx context.Context, prId string) (PRDetails, error) {
+	s.log.Info("get pull request details", "pr_id", prId)
 
-	pullRequest, err := s.db.GetPRDetailsWithReviewers(ctx, reassignReviewer.PRId)
+	pullRequest, err := s.db.GetPRDetailsWithReviewers(ctx, prId)
 	if err != nil {
-		return PullRequest{}, "", err
+		return PRDetails{}, err
 	}
 
-	if pullRequest.Status == "MERGED" {
-		return PullRequest{}, "", ErrPRAlreadyMerged
+	if err := s.authorizePRs(ctx, s.db, []PullRequest{pullRequest}); err != nil {
+		return PRDetails{}, err
 	}
 
-	user, err := s.db.GetUser(ctx, reassignReviewer.UserID)
+	assignments, err := s.db.GetAssignments(ctx, []string{prId})
 	if err != nil {
-		return PullRequest{}, "", err
+		return PRDetails{}, err
 	}
 
-	isReviewer := false
-	for _, reviewer := range pullRequest.AssignedReviewers {
-		if user.UserID == reviewer {
-			isReviewer = true
-			break
-		}
+	users, err := s.db.GetUsers(ctx, pullRequest.AssignedReviewers)
+	if err != nil {
+		return PRDetails{}, err
+	}
+	usersById := make(map[string]User, len(users))
+	for _, user := range users {
+		usersById[user.UserID] = user
 	}
 
-	if !isReviewer {
-		return PullRequest{}, "", ErrReviewerNotAssigned
+	details := PRDetails{PullRequest: pullRequest}
+	for _, assignment := range assignments[prId] {
+		reviewer := ReviewerDetails{
+			User:       usersById[assignment.ReviewerID],
+			State:      ReviewPending,
+			ApprovedAt: assignment.ApprovedAt,
+		}
+		reviewer.User.UserID = assignment.ReviewerID
+		if assignment.ApprovedAt != nil {
+			reviewer.State = ReviewApproved
+		}
+		details.Reviewers = append(details.Reviewers, reviewer)
 	}
 
-	team, err := s.db.GetTeam(ctx, user.TeamName)
+	details.History, err = s.db.GetPRHistory(ctx, prId)
 	if err != nil {
-		return PullRequest{}, "", err
+		return PRDetails{}, err
 	}
 
-	var availableReviewer string
-	found := false
+	return details, nil
+}
 
-	for _, teamMember := range team.Members {
-		if teamMember.IsActive && teamMember.UserID != user.UserID && teamMember.UserID != pullRequest.AuthorID {
-			isAlreadyReviewer := false
-			for _, reviewer := range pullRequest.AssignedReviewers {
-				if reviewer == teamMember.UserID {
-					isAlreadyReviewer = true
-					break
-				}
-			}
+func (s *Service) Approve(ctx context.Context, prId string, userId string) (PullRequest, error) {
+	s.log.Info("approving pull request", "pr_id", prId, "reviewer_id", userId)
 
-			if !isAlreadyReviewer {
-				found = true
-				availableReviewer = teamMember.UserID
-				break
-			}
-		}
+	if err := s.authorize(ctx, s.db, rule{users: []string{userId}}); err != nil {
+		return PullRequest{}, err
 	}
-	if !found {
-		return PullRequest{}, "", ErrNoReplacementCandidate
+
+	var pullRequest PullRequest
+	err := s.db.WithTx(ctx, func(db DB) error {
+		var err error
+		pullRequest, err = db.GetPRDetailsWithReviewers(ctx, prId)
+		if err != nil {
+			return err
+		}
+
+		if pullRequest.Status == "MERGED" {
+			return ErrPRAlreadyMerged
+		}
+
+		if !slices.Contains(pullRequest.AssignedReviewers, userId) {
+			return ErrReviewerNotAssigned
+		}
+
+		if err := checkVersion(ctx, pullRequest); err != nil {
+			return err
+		}
+
+		return db.Approve(ctx, prId, userId, pullRequest.Version)
+	})
+	if err != nil {
+		return PullRequest{}, err
 	}
+	pullRequest.Version++
+
+	s.record(ctx, prId, HistoryApproved, userId, "")
+
+	return pullRequest, nil
+}
+
+func (s *Service) Merged(ctx context.Context, prId string) (PullRequest, error) {
+	s.log.Info("merged pr", "prId", prId)
+
+	var pullRequest PullRequest
+	err := s.db.WithTx(ctx, func(db DB) error {
+		current, err := db.GetPRDetailsWithReviewers(ctx, prId)
+		if err != nil {
+			return err
+		}
+
+		if current.Status == "MERGED" {
+			return ErrPRAlreadyMerged
+		}
+
+		author, err := db.GetUser(ctx, current.AuthorID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.authorize(ctx, db, rule{users: []string{author.UserID}, team: author.TeamName, bots: true}); err != nil {
+			return err
+		}
 
-	err = s.db.Reassign(ctx, reassignReviewer, availableReviewer)
+		if err := checkVersion(ctx, current); err != nil {
+			return err
+		}
+
+		pullRequest, err = db.Merged(ctx, prId, current.Version)
+		return err
+	})
 	if err != nil {
-		return PullRequest{}, "", err
+		return PullRequest{}, err
 	}
 
-	updatedPR, err := s.db.GetPRDetailsWithReviewers(ctx, reassignReviewer.PRId)
+	s.record(ctx, prId, HistoryMerged, actor(ctx), "")
+
+	return pullRequest, nil
+}
+
+func (s *Service) Reassign(ctx context.Context, reassignReviewer ReassignReviewer) (PullRequest, string, error) {
+	s.log.Info("reassigning pull request reviewer",
+		"reviewer_id", reassignReviewer.UserID,
+		"pull_request_id", reassignReviewer.PRId)
+
+	var (
+		pullRequest       PullRequest
+		updatedPR         PullRequest
+		team              Team
+		availableReviewer string
+	)
+	err := s.db.WithTx(ctx, func(db DB) error {
+		var err error
+		pullRequest, err = db.GetPRDetailsWithReviewers(ctx, reassignReviewer.PRId)
+		if err != nil {
+			return err
+		}
+
+		if pullRequest.Status == "MERGED" {
+			return ErrPRAlreadyMerged
+		}
+
+		user, err := db.GetUser(ctx, reassignReviewer.UserID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.authorize(ctx, db, rule{users: []string{user.UserID}, team: user.TeamName}); err != nil {
+			return err
+		}
+
+		if !slices.Contains(pullRequest.AssignedReviewers, user.UserID) {
+			return ErrReviewerNotAssigned
+		}
+
+		if err := checkVersion(ctx, pullRequest); err != nil {
+			return err
+		}
+
+		team, err = db.GetTeam(ctx, user.TeamName)
+		if err != nil {
+			return err
+		}
+
+		userIds, err := candidates(ctx, db, team, pullRequest, pullRequest.AssignedReviewers)
+		if err != nil {
+			return err
+		}
+		if len(userIds) == 0 {
+			return ErrNoReplacementCandidate
+		}
+		availableReviewer = userIds[0]
+
+		err = db.Reassign(ctx, reassignReviewer, availableReviewer, pullRequest.Version)
+		if err != nil {
+			return err
+		}
+
+		updatedPR, err = db.GetPRDetailsWithReviewers(ctx, reassignReviewer.PRId)
+		return err
+	})
+	if errors.Is(err, ErrNoReplacementCandidate) {
+		// Escalation happens outside of the rolled back transaction so that
+		// adding the lead as a reviewer sticks.
+		reason := fmt.Sprintf("no active replacement candidate for reviewer %s", reassignReviewer.UserID)
+		if err := s.escalate(ctx, pullRequest, team, reason); err != nil {
+			s.log.Warn("failed to escalate pull request", "pr_id", pullRequest.PullRequestID, "error", err)
+		}
+	}
 	if err != nil {
 		return PullRequest{}, "", err
 	}
@@ -184,18 +517,32 @@ func (s *Service) Reassign(ctx context.Context, reassignReviewer ReassignReviewe
 		"new_reviewer", availableReviewer,
 		"pr_id", reassignReviewer.PRId)
 
-	return updatedPR, availableReviewer, err
+	s.record(ctx, reassignReviewer.PRId, HistoryReassigned, actor(ctx),
+		fmt.Sprintf("%s replaced by %s", reassignReviewer.UserID, availableReviewer))
 
+	s.notify(ctx, Notification{
+		Kind:        NotificationReassigned,
+		TeamName:    team.TeamName,
+		PullRequest: updatedPR,
+		Reviewers:   []string{availableReviewer},
+		OldReviewer: reassignReviewer.UserID,
+	})
+
+	return updatedPR, availableReviewer, nil
 }
 
 func (s *Service) GetReview(ctx context.Context, userId string) (UserPullRequest, error) {
 	s.log.Info("finding user's assigned pull requests", "user_id", userId)
 
-	_, err := s.db.GetUser(ctx, userId)
+	user, err := s.db.GetUser(ctx, userId)
 	if err != nil {
 		return UserPullRequest{}, err
 	}
 
+	if err := s.authorize(ctx, s.db, rule{users: []string{userId}, team: user.TeamName, bots: true}); err != nil {
+		return UserPullRequest{}, err
+	}
+
 	userPullRequest, err := s.db.GetReview(ctx, userId)
 	if err != nil {
 		return UserPullRequest{}, err
@@ -206,6 +553,15 @@ func (s *Service) GetReview(ctx context.Context, userId string) (UserPullRequest
 }
 
 func (s *Service) GetStats(ctx context.Context) (Stats, error) {
+	if err := requireScope(ctx, ScopeStatsRead); err != nil {
+		return nil, err
+	}
+
+	// The stats name every reviewer in the tenant, so members don't get them.
+	if err := s.authorize(ctx, s.db, rule{bots: true}); err != nil {
+		return nil, err
+	}
+
 	userStats, err := s.db.GetUserReviewStats(ctx)
 	if err != nil {
 		return nil, err
@@ -229,3 +585,179 @@ func (s *Service) GetStats(ctx context.Context) (Stats, error) {
 		"unique_prs_with_reviewers":     len(prStats),
 	}, nil
 }
+
+// forEachTenant runs a background job once per tenant.
+func (s *Service) forEachTenant(ctx context.Context, job func(context.Context) error) error {
+	tenants, err := s.db.GetTenants(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, tenant := range tenants {
+		if err := job(WithTenant(ctx, tenant)); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenant, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) SendReminders(ctx context.Context) error {
+	return s.forEachTenant(ctx, s.sendReminders)
+}
+
+func (s *Service) sendReminders(ctx context.Context) error {
+	s.log.Info("sending pending review reminders", "tenant", TenantFrom(ctx))
+
+	pending, err := s.db.GetPendingReviews(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, reviews := range pending {
+		s.notify(ctx, Notification{
+			Kind:         NotificationReminder,
+			TeamName:     reviews.User.TeamName,
+			PullRequests: reviews.PullRequests,
+			Reviewers:    []string{reviews.User.UserID},
+		})
+	}
+
+	s.log.Info("pending review reminders sent", "users_count", len(pending))
+
+	return nil
+}
+
+func (s *Service) EscalateStale(ctx context.Context) error {
+	if s.escalation.Threshold <= 0 {
+		return nil
+	}
+
+	return s.forEachTenant(ctx, s.escalateStale)
+}
+
+func (s *Service) escalateStale(ctx context.Context) error {
+	s.log.Info("escalating stale pull requests", "tenant", TenantFrom(ctx), "threshold", s.escalation.Threshold)
+
+	stale, err := s.db.GetStalePRs(ctx, time.Now().Add(-s.escalation.Threshold))
+	if err != nil {
+		return err
+	}
+
+	for _, pullRequest := range stale {
+		author, err := s.db.GetUser(ctx, pullRequest.AuthorID)
+		if err != nil {
+			return err
+		}
+
+		team, err := s.db.GetTeam(ctx, author.TeamName)
+		if err != nil {
+			return err
+		}
+
+		reason := fmt.Sprintf("no approvals for more than %s", s.escalation.Threshold)
+		if err := s.escalate(ctx, pullRequest, team, reason); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) escalate(ctx context.Context, pullRequest PullRequest, team Team, reason string) error {
+	if team.LeadID == "" {
+		s.log.Warn("cannot escalate pull request, team has no lead",
+			"pr_id", pullRequest.PullRequestID,
+			"team_name", team.TeamName)
+		return nil
+	}
+
+	s.log.Info("escalating pull request to team lead",
+		"pr_id", pullRequest.PullRequestID,
+		"lead_id", team.LeadID,
+		"reason", reason)
+
+	addLead := s.escalation.AddLeadAsReviewer && team.LeadID != pullRequest.AuthorID &&
+		!slices.Contains(pullRequest.AssignedReviewers, team.LeadID)
+
+	err := s.db.WithTx(ctx, func(db DB) error {
+		if addLead {
+			if err := db.AddReviewer(ctx, pullRequest.PullRequestID, team.LeadID); err != nil {
+				return err
+			}
+		}
+
+		return db.AddPRHistory(ctx, PRHistoryEntry{
+			PullRequestID: pullRequest.PullRequestID,
+			Event:         HistoryEscalated,
+			Details:       fmt.Sprintf("escalated to %s: %s", team.LeadID, reason),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if addLead {
+		pullRequest.AssignedReviewers = append(pullRequest.AssignedReviewers, team.LeadID)
+	}
+
+	s.notify(ctx, Notification{
+		Kind:        NotificationEscalated,
+		TeamName:    team.TeamName,
+		PullRequest: pullRequest,
+		Reviewers:   []string{team.LeadID},
+		Reason:      reason,
+	})
+
+	return nil
+}
+
+func (s *Service) GetTeams(ctx context.Context, teamNames []string) ([]Team, error) {
+	s.log.Debug("batch loading teams", "count", len(teamNames))
+	return s.db.GetTeams(ctx, teamNames)
+}
+
+func (s *Service) GetUsers(ctx context.Context, userIds []string) ([]User, error) {
+	s.log.Debug("batch loading users", "count", len(userIds))
+	return s.db.GetUsers(ctx, userIds)
+}
+
+func (s *Service) GetPRs(ctx context.Context, prIds []string) ([]PullRequest, error) {
+	s.log.Debug("batch loading pull requests", "count", len(prIds))
+
+	pullRequests, err := s.db.GetPRs(ctx, prIds)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizePRs(ctx, s.db, pullRequests); err != nil {
+		return nil, err
+	}
+
+	return pullRequests, nil
+}
+
+func (s *Service) GetReviewsByUsers(ctx context.Context, userIds []string) (map[string][]PullRequest, error) {
+	s.log.Debug("batch loading reviews", "count", len(userIds))
+
+	if err := s.authorizeUsers(ctx, s.db, userIds); err != nil {
+		return nil, err
+	}
+
+	return s.db.GetReviewsByUsers(ctx, userIds)
+}
+
+func (s *Service) GetAssignments(ctx context.Context, prIds []string) (map[string][]Assignment, error) {
+	s.log.Debug("batch loading assignments", "count", len(prIds))
+
+	if _, ok := PrincipalFrom(ctx); ok {
+		pullRequests, err := s.db.GetPRs(ctx, prIds)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.authorizePRs(ctx, s.db, pullRequests); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.db.GetAssignments(ctx, prIds)
+}
@@ -0,0 +1,92 @@
+package core_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"review-assigner/adapters/sqlite"
+	"review-assigner/core"
+	"sync"
+	"testing"
+)
+
+// openService returns a service over an empty in-memory SQLite database.
+func openService(t *testing.T, notifier core.Notifier, escalation core.EscalationPolicy) *core.Service {
+	t.Helper()
+	return openServiceWith(t, notifier, escalation, func(db core.DB) core.DB { return db })
+}
+
+// openServiceWith is openService with the storage of the service wrapped.
+func openServiceWith(t *testing.T, notifier core.Notifier, escalation core.EscalationPolicy,
+	wrap func(core.DB) core.DB) *core.Service {
+	t.Helper()
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	db, err := sqlite.New(log, "sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	service, err := core.NewService(log, wrap(db), notifier, escalation, core.AssignmentPolicy{})
+	if err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+	return service
+}
+
+// outbox records the notifications the service sends.
+type outbox struct {
+	mu   sync.Mutex
+	sent []core.Notification
+}
+
+func (o *outbox) Notify(_ context.Context, notification core.Notification) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.sent = append(o.sent, notification)
+	return nil
+}
+
+// take returns the notifications of the given kind sent since the last call.
+func (o *outbox) take(kind core.NotificationKind) []core.Notification {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var taken []core.Notification
+	for _, notification := range o.sent {
+		if notification.Kind == kind {
+			taken = append(taken, notification)
+		}
+	}
+	o.sent = nil
+	return taken
+}
+
+func createTeam(t *testing.T, ctx context.Context, service *core.Service, team core.Team) {
+	t.Helper()
+	if _, err := service.CreateTeam(ctx, team); err != nil {
+		t.Fatalf("create team %s: %v", team.TeamName, err)
+	}
+}
+
+func createPR(t *testing.T, ctx context.Context, service *core.Service, id, authorId string) core.PullRequest {
+	t.Helper()
+	pullRequest, err := service.CreatePR(ctx, core.PullRequest{PullRequestID: id, PullRequestName: "title of " + id, AuthorID: authorId})
+	if err != nil {
+		t.Fatalf("create pr %s: %v", id, err)
+	}
+	return pullRequest
+}
+
+func members(userIds ...string) []core.TeamMember {
+	teamMembers := make([]core.TeamMember, 0, len(userIds))
+	for _, userId := range userIds {
+		teamMembers = append(teamMembers, core.TeamMember{UserID: userId, Username: "user " + userId, IsActive: true})
+	}
+	return teamMembers
+}
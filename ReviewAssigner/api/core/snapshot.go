@@ -0,0 +1,423 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const SnapshotVersion = 1
+
+const snapshotPageSize = 500
+
+// Snapshot is a logical backup of teams, users, repositories and pull
+// requests. Team members are listed in Users, tokens and idempotency keys are
+// not included.
+type Snapshot struct {
+	Version      int
+	ExportedAt   time.Time
+	Teams        []Team
+	Users        []User
+	Repositories []Repository
+	PullRequests []PRSnapshot
+}
+
+type PRSnapshot struct {
+	PullRequest PullRequest
+	Reviewers   []Assignment
+	History     []PRHistoryEntry
+}
+
+type ImportCount struct {
+	Created int
+	Updated int
+}
+
+type ImportResult struct {
+	DryRun       bool
+	Teams        ImportCount
+	Users        ImportCount
+	Repositories ImportCount
+	PullRequests ImportCount
+}
+
+var errDryRun = errors.New("dry run")
+
+func (s *Service) Export(ctx context.Context) (Snapshot, error) {
+	s.log.Info("export snapshot")
+
+	if err := s.authorize(ctx, s.db, rule{}); err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot := Snapshot{Version: SnapshotVersion, ExportedAt: time.Now().UTC()}
+	err := s.db.WithTx(ctx, func(db DB) error {
+		names, err := db.GetTeamNames(ctx)
+		if err != nil {
+			return err
+		}
+		teams, err := db.GetTeams(ctx, names)
+		if err != nil {
+			return err
+		}
+
+		var userIds []string
+		for _, team := range teams {
+			for _, member := range team.Members {
+				userIds = append(userIds, member.UserID)
+			}
+			team.Members = nil
+			snapshot.Teams = append(snapshot.Teams, team)
+		}
+		snapshot.Users, err = db.GetUsers(ctx, userIds)
+		if err != nil {
+			return err
+		}
+		snapshot.Repositories, err = db.GetRepositories(ctx)
+		if err != nil {
+			return err
+		}
+
+		filter := PRFilter{Limit: snapshotPageSize}
+		for {
+			pullRequests, err := db.ListPRs(ctx, filter)
+			if err != nil {
+				return err
+			}
+			if len(pullRequests) == 0 {
+				return nil
+			}
+
+			prIds := make([]string, 0, len(pullRequests))
+			for _, pullRequest := range pullRequests {
+				prIds = append(prIds, pullRequest.PullRequestID)
+			}
+			assignments, err := db.GetAssignments(ctx, prIds)
+			if err != nil {
+				return err
+			}
+
+			for _, pullRequest := range pullRequests {
+				history, err := db.GetPRHistory(ctx, pullRequest.PullRequestID)
+				if err != nil {
+					return err
+				}
+				snapshot.PullRequests = append(snapshot.PullRequests, PRSnapshot{
+					PullRequest: pullRequest,
+					Reviewers:   assignments[pullRequest.PullRequestID],
+					History:     history,
+				})
+			}
+
+			last := pullRequests[len(pullRequests)-1]
+			createdAt, err := time.Parse(time.RFC3339Nano, *last.CreatedAt)
+			if err != nil {
+				return err
+			}
+			filter.After = &PRCursor{CreatedAt: createdAt, ID: last.PullRequestID}
+		}
+	})
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	return snapshot, nil
+}
+
+// Import restores a snapshot. Objects in the snapshot replace the stored ones
+// with the same id, including reviewers and history of pull requests, and
+// everything else is left alone, so importing the same snapshot twice is a
+// no-op. A dry run validates and applies the snapshot in a transaction that
+// is then rolled back.
+func (s *Service) Import(ctx context.Context, snapshot Snapshot, dryRun bool) (ImportResult, error) {
+	s.log.Info("import snapshot", "teams", len(snapshot.Teams), "users", len(snapshot.Users),
+		"pull_requests", len(snapshot.PullRequests), "dry_run", dryRun)
+
+	if err := s.authorize(ctx, s.db, rule{}); err != nil {
+		return ImportResult{}, err
+	}
+
+	result := ImportResult{DryRun: dryRun}
+	err := s.db.WithTx(ctx, func(db DB) error {
+		result = ImportResult{DryRun: dryRun}
+
+		if err := validateSnapshot(ctx, db, snapshot); err != nil {
+			return err
+		}
+
+		if err := s.importTeamsAndUsers(ctx, db, snapshot, &result); err != nil {
+			return err
+		}
+
+		for _, repository := range snapshot.Repositories {
+			_, err := db.GetRepository(ctx, repository.Name)
+			switch {
+			case errors.Is(err, ErrRepositoryNotFound):
+				result.Repositories.Created++
+			case err != nil:
+				return err
+			default:
+				result.Repositories.Updated++
+			}
+			if err := db.ImportRepository(ctx, repository); err != nil {
+				return err
+			}
+		}
+
+		prIds := make([]string, 0, len(snapshot.PullRequests))
+		for _, pullRequest := range snapshot.PullRequests {
+			prIds = append(prIds, pullRequest.PullRequest.PullRequestID)
+		}
+		existing, err := db.GetPRs(ctx, prIds)
+		if err != nil {
+			return err
+		}
+		result.PullRequests.Updated = len(existing)
+		result.PullRequests.Created = len(prIds) - len(existing)
+
+		for _, pullRequest := range snapshot.PullRequests {
+			pullRequest.PullRequest.AssignedReviewers = nil
+			for _, reviewer := range pullRequest.Reviewers {
+				pullRequest.PullRequest.AssignedReviewers = append(pullRequest.PullRequest.AssignedReviewers,
+					reviewer.ReviewerID)
+			}
+			if pullRequest.PullRequest.Version < 1 {
+				pullRequest.PullRequest.Version = 1
+			}
+			if pullRequest.PullRequest.Priority == "" {
+				pullRequest.PullRequest.Priority = PriorityNormal
+			}
+			if err := db.ImportPR(ctx, pullRequest); err != nil {
+				return err
+			}
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return ImportResult{}, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) importTeamsAndUsers(ctx context.Context, db DB, snapshot Snapshot, result *ImportResult) error {
+	teamNames := make([]string, 0, len(snapshot.Teams))
+	for _, team := range snapshot.Teams {
+		teamNames = append(teamNames, team.TeamName)
+	}
+	existingTeams, err := db.GetTeams(ctx, teamNames)
+	if err != nil {
+		return err
+	}
+	result.Teams.Updated = len(existingTeams)
+	result.Teams.Created = len(teamNames) - len(existingTeams)
+
+	userIds := make([]string, 0, len(snapshot.Users))
+	for _, user := range snapshot.Users {
+		userIds = append(userIds, user.UserID)
+	}
+	existingUsers, err := db.GetUsers(ctx, userIds)
+	if err != nil {
+		return err
+	}
+	result.Users.Updated = len(existingUsers)
+	result.Users.Created = len(userIds) - len(existingUsers)
+
+	// Users reference their team and teams reference their lead, so leads
+	// are set once both exist.
+	for _, name := range teamNames {
+		if err := db.ImportTeam(ctx, name); err != nil {
+			return err
+		}
+	}
+	for _, user := range snapshot.Users {
+		if err := db.ImportUser(ctx, user); err != nil {
+			return err
+		}
+	}
+	for _, team := range snapshot.Teams {
+		if err := db.SetTeamLead(ctx, team.TeamName, team.LeadID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func invalidSnapshot(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidSnapshot, fmt.Sprintf(format, args...))
+}
+
+// validateSnapshot checks that every reference points either into the
+// snapshot or at a stored object.
+func validateSnapshot(ctx context.Context, db DB, snapshot Snapshot) error {
+	if snapshot.Version != SnapshotVersion {
+		return invalidSnapshot("unsupported version %d", snapshot.Version)
+	}
+
+	teams := make(map[string]bool, len(snapshot.Teams))
+	for _, team := range snapshot.Teams {
+		if team.TeamName == "" {
+			return invalidSnapshot("team without name")
+		}
+		if teams[team.TeamName] {
+			return invalidSnapshot("duplicate team %q", team.TeamName)
+		}
+		teams[team.TeamName] = true
+	}
+
+	users := make(map[string]User, len(snapshot.Users))
+	for _, user := range snapshot.Users {
+		if user.UserID == "" {
+			return invalidSnapshot("user without id")
+		}
+		if _, ok := users[user.UserID]; ok {
+			return invalidSnapshot("duplicate user %q", user.UserID)
+		}
+		users[user.UserID] = user
+	}
+
+	repositories := make(map[string]bool, len(snapshot.Repositories))
+	var referencedTeams []string
+	for _, repository := range snapshot.Repositories {
+		if !validRepository(repository.Name) {
+			return invalidSnapshot("repository with invalid name %q", repository.Name)
+		}
+		if repositories[repository.Name] {
+			return invalidSnapshot("duplicate repository %q", repository.Name)
+		}
+		repositories[repository.Name] = true
+		referencedTeams = append(referencedTeams, repository.Teams...)
+	}
+
+	var referencedUsers []string
+	prs := make(map[string]bool, len(snapshot.PullRequests))
+	for _, pr := range snapshot.PullRequests {
+		pullRequest := pr.PullRequest
+		if pullRequest.PullRequestID == "" {
+			return invalidSnapshot("pull request without id")
+		}
+		if prs[pullRequest.PullRequestID] {
+			return invalidSnapshot("duplicate pull request %q", pullRequest.PullRequestID)
+		}
+		prs[pullRequest.PullRequestID] = true
+
+		if pullRequest.Repository != "" || pullRequest.Number != 0 {
+			if !validRepository(pullRequest.Repository) || pullRequest.Number < 1 {
+				return invalidSnapshot("pull request %q has invalid repository or number", pullRequest.PullRequestID)
+			}
+			if !repositories[pullRequest.Repository] {
+				if _, err := db.GetRepository(ctx, pullRequest.Repository); errors.Is(err, ErrRepositoryNotFound) {
+					return invalidSnapshot("pull request %q references unknown repository %q",
+						pullRequest.PullRequestID, pullRequest.Repository)
+				} else if err != nil {
+					return err
+				}
+				repositories[pullRequest.Repository] = true
+			}
+		}
+
+		if pullRequest.Status != "OPEN" && pullRequest.Status != "MERGED" {
+			return invalidSnapshot("pull request %q has unknown status %q", pullRequest.PullRequestID, pullRequest.Status)
+		}
+		if !validMetadata(pullRequest) {
+			return invalidSnapshot("pull request %q has invalid labels, size or priority", pullRequest.PullRequestID)
+		}
+		for _, value := range []*string{pullRequest.CreatedAt, pullRequest.MergedAt} {
+			if value == nil {
+				continue
+			}
+			if _, err := time.Parse(time.RFC3339Nano, *value); err != nil {
+				return invalidSnapshot("pull request %q has invalid time %q", pullRequest.PullRequestID, *value)
+			}
+		}
+
+		referencedUsers = append(referencedUsers, pullRequest.AuthorID)
+		reviewers := make(map[string]bool, len(pr.Reviewers))
+		for _, reviewer := range pr.Reviewers {
+			if reviewers[reviewer.ReviewerID] {
+				return invalidSnapshot("pull request %q lists reviewer %q twice",
+					pullRequest.PullRequestID, reviewer.ReviewerID)
+			}
+			reviewers[reviewer.ReviewerID] = true
+			referencedUsers = append(referencedUsers, reviewer.ReviewerID)
+		}
+		for _, entry := range pr.History {
+			if entry.Event == "" {
+				return invalidSnapshot("pull request %q has history without event", pullRequest.PullRequestID)
+			}
+		}
+	}
+
+	for _, user := range snapshot.Users {
+		if !teams[user.TeamName] {
+			referencedTeams = append(referencedTeams, user.TeamName)
+		}
+	}
+	storedTeams, err := db.GetTeams(ctx, referencedTeams)
+	if err != nil {
+		return err
+	}
+	for _, team := range storedTeams {
+		teams[team.TeamName] = true
+	}
+	for _, user := range snapshot.Users {
+		if !teams[user.TeamName] {
+			return invalidSnapshot("user %q references unknown team %q", user.UserID, user.TeamName)
+		}
+	}
+	for _, repository := range snapshot.Repositories {
+		for _, teamName := range repository.Teams {
+			if !teams[teamName] {
+				return invalidSnapshot("repository %q references unknown team %q", repository.Name, teamName)
+			}
+		}
+	}
+
+	for _, team := range snapshot.Teams {
+		if team.LeadID != "" {
+			referencedUsers = append(referencedUsers, team.LeadID)
+		}
+	}
+	var missing []string
+	for _, userId := range referencedUsers {
+		if _, ok := users[userId]; !ok {
+			missing = append(missing, userId)
+		}
+	}
+	storedUsers, err := db.GetUsers(ctx, missing)
+	if err != nil {
+		return err
+	}
+	for _, user := range storedUsers {
+		users[user.UserID] = user
+	}
+
+	for _, team := range snapshot.Teams {
+		if team.LeadID == "" {
+			continue
+		}
+		if lead, ok := users[team.LeadID]; !ok || lead.TeamName != team.TeamName {
+			return invalidSnapshot("lead %q of team %q is not its member", team.LeadID, team.TeamName)
+		}
+	}
+	for _, pr := range snapshot.PullRequests {
+		if _, ok := users[pr.PullRequest.AuthorID]; !ok {
+			return invalidSnapshot("pull request %q references unknown author %q",
+				pr.PullRequest.PullRequestID, pr.PullRequest.AuthorID)
+		}
+		for _, reviewer := range pr.Reviewers {
+			if _, ok := users[reviewer.ReviewerID]; !ok {
+				return invalidSnapshot("pull request %q references unknown reviewer %q",
+					pr.PullRequest.PullRequestID, reviewer.ReviewerID)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package core
+
+import "context"
+
+// DefaultTenant owns every row created before tenants existed and every
+// request that does not name an organisation.
+const DefaultTenant = "default"
+
+type tenantKey struct{}
+
+// WithTenant scopes all storage calls made with the context to one
+// organisation.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+func TenantFrom(ctx context.Context) string {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	if !ok || tenant == "" {
+		return DefaultTenant
+	}
+	return tenant
+}
+
+// ValidTenant accepts short names of letters, digits, '.', '_' and '-', so
+// that a tenant can be used in configuration keys like "acme/u1".
+func ValidTenant(tenant string) bool {
+	if tenant == "" || len(tenant) > 100 {
+		return false
+	}
+	for _, r := range tenant {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
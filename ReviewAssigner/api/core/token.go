@@ -0,0 +1,137 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"time"
+)
+
+const tokenPrefix = "ra_"
+
+type Scope string
+
+const (
+	ScopeAdmin      Scope = "admin"
+	ScopeTeamsRead  Scope = "teams:read"
+	ScopeTeamsWrite Scope = "teams:write"
+	ScopeUsersRead  Scope = "users:read"
+	ScopeUsersWrite Scope = "users:write"
+	ScopePRsRead    Scope = "prs:read"
+	ScopePRsWrite   Scope = "prs:write"
+	ScopeStatsRead  Scope = "stats:read"
+)
+
+var Scopes = []Scope{
+	ScopeAdmin,
+	ScopeTeamsRead,
+	ScopeTeamsWrite,
+	ScopeUsersRead,
+	ScopeUsersWrite,
+	ScopePRsRead,
+	ScopePRsWrite,
+	ScopeStatsRead,
+}
+
+type Token struct {
+	ID         int64
+	Tenant     string
+	Name       string
+	UserID     string
+	Scopes     []Scope
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+func (t Token) IsAdmin() bool {
+	return slices.Contains(t.Scopes, ScopeAdmin)
+}
+
+func (t Token) HasScope(scope Scope) bool {
+	return t.IsAdmin() || slices.Contains(t.Scopes, scope)
+}
+
+func (t Token) Principal() Principal {
+	switch {
+	case t.IsAdmin():
+		return Principal{UserID: t.UserID, Role: RoleAdmin, Scopes: t.Scopes}
+	case t.UserID == "":
+		return Principal{Role: RoleBot, Scopes: t.Scopes}
+	default:
+		return Principal{UserID: t.UserID, Role: RoleMember, Scopes: t.Scopes}
+	}
+}
+
+func HashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *Service) CreateToken(ctx context.Context, token Token) (Token, string, error) {
+	s.log.Info("create api token", "name", token.Name, "user_id", token.UserID, "scopes", token.Scopes)
+
+	if err := s.authorize(ctx, s.db, rule{}); err != nil {
+		return Token{}, "", err
+	}
+
+	if len(token.Scopes) == 0 {
+		return Token{}, "", ErrInvalidScope
+	}
+	for _, scope := range token.Scopes {
+		if !slices.Contains(Scopes, scope) {
+			return Token{}, "", ErrInvalidScope
+		}
+	}
+
+	if token.UserID != "" {
+		if _, err := s.db.GetUser(ctx, token.UserID); err != nil {
+			return Token{}, "", err
+		}
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return Token{}, "", err
+	}
+	secret := tokenPrefix + hex.EncodeToString(raw)
+
+	created, err := s.db.AddToken(ctx, token, HashToken(secret))
+	if err != nil {
+		return Token{}, "", err
+	}
+
+	return created, secret, nil
+}
+
+func (s *Service) Authenticate(ctx context.Context, secret string) (Token, error) {
+	token, err := s.db.GetTokenByHash(ctx, HashToken(secret))
+	if err != nil {
+		return Token{}, err
+	}
+
+	if err := s.db.TouchToken(ctx, token.ID, time.Now()); err != nil {
+		s.log.Warn("failed to update token usage", "token_id", token.ID, "error", err)
+	}
+
+	return token, nil
+}
+
+func (s *Service) ListTokens(ctx context.Context) ([]Token, error) {
+	if err := s.authorize(ctx, s.db, rule{}); err != nil {
+		return nil, err
+	}
+
+	return s.db.GetTokens(ctx)
+}
+
+func (s *Service) RevokeToken(ctx context.Context, id int64) error {
+	s.log.Info("revoke api token", "token_id", id)
+
+	if err := s.authorize(ctx, s.db, rule{}); err != nil {
+		return err
+	}
+
+	return s.db.DeleteToken(ctx, id)
+}
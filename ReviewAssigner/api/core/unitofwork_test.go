@@ -0,0 +1,78 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"review-assigner/core"
+	"slices"
+	"testing"
+	"time"
+)
+
+var errOutOfOffice = errors.New("out of office is unavailable")
+
+// failingOutOfOffice fails setting absences, after the other changes of the
+// unit of work have been written.
+type failingOutOfOffice struct {
+	core.DB
+}
+
+func (db failingOutOfOffice) WithTx(ctx context.Context, fn func(core.DB) error) error {
+	return db.DB.WithTx(ctx, func(tx core.DB) error {
+		return fn(failingOutOfOffice{tx})
+	})
+}
+
+func (db failingOutOfOffice) SetOutOfOffice(context.Context, string, *time.Time) (core.User, error) {
+	return core.User{}, errOutOfOffice
+}
+
+func TestUpdateUserRollsBack(t *testing.T) {
+	service := openServiceWith(t, nil, core.EscalationPolicy{}, func(db core.DB) core.DB { return failingOutOfOffice{db} })
+
+	ctx := context.Background()
+	createTeam(t, ctx, service, core.Team{TeamName: "backend", Members: members("u1", "u2")})
+
+	inactive := false
+	until := time.Now().Add(time.Hour)
+	_, err := service.UpdateUser(ctx, "u2", core.UserUpdate{IsActive: &inactive, SetOutOfOffice: true, OutOfOfficeUntil: &until})
+	if !errors.Is(err, errOutOfOffice) {
+		t.Fatalf("got error %v, want %v", err, errOutOfOffice)
+	}
+
+	user, err := service.GetUser(ctx, "u2")
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if !user.IsActive {
+		t.Error("deactivation was kept although the update failed")
+	}
+}
+
+func TestReassignWithoutCandidateKeepsEscalation(t *testing.T) {
+	sent := &outbox{}
+	service := openService(t, sent, core.EscalationPolicy{AddLeadAsReviewer: true})
+
+	ctx := context.Background()
+	team := core.Team{TeamName: "backend", LeadID: "u4", Members: members("u1", "u2", "u3", "u4")}
+	team.Members[3].IsActive = false
+	createTeam(t, ctx, service, team)
+	createPR(t, ctx, service, "pr-1", "u1") // u2 and u3 review, the lead is inactive
+
+	_, _, err := service.Reassign(ctx, core.ReassignReviewer{PRId: "pr-1", UserID: "u2"})
+	if !errors.Is(err, core.ErrNoReplacementCandidate) {
+		t.Fatalf("got error %v, want %v", err, core.ErrNoReplacementCandidate)
+	}
+
+	// The reassignment is rolled back, the escalation it caused is not.
+	pullRequest, err := service.GetPR(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("get pr: %v", err)
+	}
+	if !slices.Equal(pullRequest.AssignedReviewers, []string{"u2", "u3", "u4"}) {
+		t.Errorf("got reviewers %v, want u2 and u3 kept and the lead added", pullRequest.AssignedReviewers)
+	}
+	if escalated := sent.take(core.NotificationEscalated); len(escalated) != 1 {
+		t.Errorf("got %d escalations, want 1", len(escalated))
+	}
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+const maxNameLength = 100
+
+type UserImport struct {
+	UserCreated bool
+	TeamCreated bool
+}
+
+// ImportUser creates the user or replaces its name, team and activity,
+// creating the team when it does not exist yet. The out of office period of
+// an existing user is kept, and a lead moving to another team stops being
+// the lead of the old one.
+func (s *Service) ImportUser(ctx context.Context, user User) (UserImport, error) {
+	if err := s.authorize(ctx, s.db, rule{}); err != nil {
+		return UserImport{}, err
+	}
+
+	for _, value := range []string{user.UserID, user.Username, user.TeamName} {
+		if value == "" || len(value) > maxNameLength {
+			return UserImport{}, ErrInvalidUser
+		}
+	}
+
+	var result UserImport
+	err := s.db.WithTx(ctx, func(db DB) error {
+		result = UserImport{}
+
+		if _, err := db.GetTeam(ctx, user.TeamName); errors.Is(err, ErrTeamNotFound) {
+			result.TeamCreated = true
+			if err := db.ImportTeam(ctx, user.TeamName); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		current, err := db.GetUser(ctx, user.UserID)
+		switch {
+		case errors.Is(err, ErrUserNotFound):
+			result.UserCreated = true
+			user.OutOfOfficeUntil = nil
+		case err != nil:
+			return err
+		default:
+			user.OutOfOfficeUntil = current.OutOfOfficeUntil
+			if current.TeamName != user.TeamName {
+				team, err := db.GetTeam(ctx, current.TeamName)
+				if err != nil {
+					return err
+				}
+				if team.LeadID == user.UserID {
+					if err := db.SetTeamLead(ctx, team.TeamName, ""); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		return db.ImportUser(ctx, user)
+	})
+	if err != nil {
+		return UserImport{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"review-assigner/core"
+	"testing"
+	"time"
+)
+
+func TestImportUser(t *testing.T) {
+	service := openService(t, nil, core.EscalationPolicy{})
+
+	ctx := context.Background()
+	createTeam(t, ctx, service, core.Team{TeamName: "backend", LeadID: "u1", Members: members("u1", "u2")})
+	until := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	if _, err := service.SetOutOfOffice(ctx, "u1", &until); err != nil {
+		t.Fatalf("set out of office: %v", err)
+	}
+
+	result, err := service.ImportUser(ctx, core.User{UserID: "u3", Username: "Carol", TeamName: "frontend", IsActive: true})
+	if err != nil {
+		t.Fatalf("import new user: %v", err)
+	}
+	if result != (core.UserImport{UserCreated: true, TeamCreated: true}) {
+		t.Errorf("got %+v, want the user and the team created", result)
+	}
+
+	// The lead moves to frontend: backend loses its lead, the absence stays.
+	result, err = service.ImportUser(ctx, core.User{UserID: "u1", Username: "Alice Smith", TeamName: "frontend"})
+	if err != nil {
+		t.Fatalf("import existing user: %v", err)
+	}
+	if result != (core.UserImport{}) {
+		t.Errorf("got %+v, want an update", result)
+	}
+
+	user, err := service.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if user.Username != "Alice Smith" || user.TeamName != "frontend" || user.IsActive {
+		t.Errorf("got user %+v", user)
+	}
+	if user.OutOfOfficeUntil == nil || !user.OutOfOfficeUntil.Equal(until) {
+		t.Errorf("out of office: got %v, want %v", user.OutOfOfficeUntil, until)
+	}
+
+	team, err := service.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("get team: %v", err)
+	}
+	if team.LeadID != "" {
+		t.Errorf("backend is still led by %s", team.LeadID)
+	}
+
+	if _, err := service.ImportUser(ctx, core.User{UserID: "u4", TeamName: "backend"}); !errors.Is(err, core.ErrInvalidUser) {
+		t.Errorf("got error %v, want %v", err, core.ErrInvalidUser)
+	}
+}
@@ -0,0 +1,19 @@
+package core
+
+import "context"
+
+type expectedVersionKey struct{}
+
+// WithExpectedVersion makes PR mutations fail with ErrPreconditionFailed
+// unless the PR is still at the given version.
+func WithExpectedVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+func checkVersion(ctx context.Context, pullRequest PullRequest) error {
+	expected, ok := ctx.Value(expectedVersionKey{}).(int64)
+	if ok && expected != pullRequest.Version {
+		return ErrPreconditionFailed
+	}
+	return nil
+}
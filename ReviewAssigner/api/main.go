@@ -1,18 +1,53 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"github.com/golang-migrate/migrate/v4"
 	"github.com/ilyakaznacheev/cleanenv"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"review-assigner/adapters/db"
+	"review-assigner/adapters/graphql"
+	"review-assigner/adapters/grpc"
+	"review-assigner/adapters/notifier"
 	"review-assigner/adapters/rest"
+	"review-assigner/adapters/scheduler"
+	"review-assigner/adapters/sqlite"
 	"review-assigner/config"
 	"review-assigner/core"
+	"strings"
+	"syscall"
 )
 
+type storage interface {
+	core.DB
+	core.IdempotencyStore
+	migrator
+	Migrate() error
+}
+
+func openStorage(log *slog.Logger, address string) (storage, error) {
+	if strings.HasPrefix(address, sqlite.Scheme) {
+		lite, err := sqlite.New(log, address)
+		if err != nil {
+			return nil, err
+		}
+		return lite, nil
+	}
+
+	postgres, err := db.New(log, address)
+	if err != nil {
+		return nil, err
+	}
+	return postgres, nil
+}
+
 func main() {
 	var configPath string
 	flag.StringVar(&configPath, "config", "config.yaml", "server configuration file")
@@ -32,36 +67,148 @@ func main() {
 	log.Info("starting server")
 	log.Debug("debug messages are enabled")
 
-	storage, err := db.New(log, cfg.DBAddress)
+	storage, err := openStorage(log, cfg.DBAddress)
 	if err != nil {
 		log.Error("failed to create storage", "error", err)
 		return
 	}
 
-	if err := storage.CleanMigrations(); err != nil {
-		log.Warn("failed to clean migrations, continuing...", "error", err)
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(storage, flag.Args()[1:], os.Stdout); err != nil {
+			log.Error("migrate command failed", "error", err)
+			os.Exit(1)
+		}
+		return
 	}
 
+	// Cancelled on SIGINT or SIGTERM: the servers drain, background jobs stop
+	// and the queued notifications are delivered before exiting.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	if err := storage.Migrate(); err != nil {
-		log.Error("failed to migrate db: %v", err)
-		return
+		var dirty migrate.ErrDirty
+		if errors.As(err, &dirty) {
+			log.Error("database schema is dirty, fix it with the migrate command before starting",
+				"version", dirty.Version)
+			os.Exit(1)
+		}
+		log.Error("failed to migrate db", "error", err)
+		os.Exit(1)
+	}
+
+	var notifiers notifier.Multi
+	if cfg.Notifier.Webhook.URL != "" {
+		webhook, err := notifier.NewWebhook(log, cfg.Notifier.Webhook)
+		if err != nil {
+			log.Error("failed to create webhook notifier", "error", err)
+			return
+		}
+		notifiers = append(notifiers, webhook)
 	}
+	if cfg.Notifier.SMTP.Host != "" {
+		mailer, err := notifier.NewSMTP(log, cfg.Notifier.SMTP, storage)
+		if err != nil {
+			log.Error("failed to create smtp notifier", "error", err)
+			return
+		}
+		notifiers = append(notifiers, mailer)
+
+		if cfg.Notifier.SMTP.Digest {
+			go mailer.RunDigest(ctx)
+		}
+	}
+
+	notifications := notifier.NewAsync(log, notifiers, cfg.Notifier.QueueSize)
+	defer notifications.Close()
 
-	service, err := core.NewService(log, storage)
+	service, err := core.NewService(log, storage, notifications, core.EscalationPolicy{
+		Threshold:         cfg.Escalation.Threshold,
+		AddLeadAsReviewer: cfg.Escalation.AddLeadAsReviewer,
+	}, core.AssignmentPolicy{
+		LargeLines:    cfg.Assignment.LargeLines,
+		LargeFiles:    cfg.Assignment.LargeFiles,
+		SecurityLabel: cfg.Assignment.SecurityLabel,
+		SecurityTeam:  cfg.Assignment.SecurityTeam,
+	})
 	if err != nil {
 		log.Error("failed to create service", "error", err)
 		return
 	}
 
-	handler := rest.NewHandler(service, log)
+	jobs := scheduler.New(log, cfg.Scheduler.Timeout)
+	if cfg.Scheduler.ReminderSchedule != "" {
+		if err := jobs.AddJob("reminders", cfg.Scheduler.ReminderSchedule, service.SendReminders); err != nil {
+			log.Error("failed to schedule reminders", "error", err)
+			return
+		}
+	}
+	if cfg.Scheduler.EscalationSchedule != "" {
+		if err := jobs.AddJob("escalations", cfg.Scheduler.EscalationSchedule, service.EscalateStale); err != nil {
+			log.Error("failed to schedule escalations", "error", err)
+			return
+		}
+	}
+	idempotency := rest.NewIdempotency(storage, log, cfg.Idempotency.TTL)
+	if cfg.Scheduler.CleanupSchedule != "" {
+		if err := jobs.AddJob("idempotency-cleanup", cfg.Scheduler.CleanupSchedule, idempotency.Cleanup); err != nil {
+			log.Error("failed to schedule idempotency cleanup", "error", err)
+			return
+		}
+	}
+	jobs.Start(ctx)
+	defer jobs.Stop()
+
+	auth, err := rest.NewAuthenticator(service, log, cfg.Auth)
+	if err != nil {
+		log.Error("failed to create authenticator", "error", err)
+		return
+	}
+
+	listener, err := net.Listen("tcp", cfg.GRPCConfig.Address)
+	if err != nil {
+		log.Error("failed to listen for grpc", "address", cfg.GRPCConfig.Address, "error", err)
+		return
+	}
+	grpcServer := grpc.NewServer(service, log, auth)
+	defer grpcServer.GracefulStop()
+
+	go func() {
+		log.Info("grpc server running", "address", cfg.GRPCConfig.Address)
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Error("grpc server failed", "error", err)
+		}
+	}()
+
+	handler := http.NewServeMux()
+	handler.Handle("/graphql", auth.RequireScope(core.ScopePRsRead)(graphql.NewHandler(service, log)))
+	handler.Handle("/", rest.NewHandler(service, log, auth, idempotency))
+
 	server := &http.Server{
 		Addr:    cfg.HTTPConfig.Address,
 		Handler: handler,
 	}
 
-	log.Info("server running", "address", cfg.HTTPConfig.Address)
-	if err := server.ListenAndServe(); err != nil {
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Info("server running", "address", cfg.HTTPConfig.Address)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
 		log.Error("server failed", "error", err)
+		return
+	case <-ctx.Done():
+	}
+	// A second signal kills the process instead of waiting for the drain.
+	stop()
+
+	log.Info("shutting down", "timeout", cfg.HTTPConfig.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTPConfig.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Error("failed to shut down server", "error", err)
 	}
 }
 
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+const migrateUsage = `usage: %s [-config file] migrate <command>
+
+commands:
+  up          apply all pending migrations
+  down N      roll back the last N migrations
+  status      list migrations and the current version
+  goto V      migrate up or down to version V
+  force V     mark version V as applied and clean, without running it
+              (-1 marks the schema as empty)
+`
+
+type migrator interface {
+	Migrations() (source.Driver, error)
+	Migrator() (*migrate.Migrate, error)
+}
+
+func runMigrate(storage migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprintf(out, migrateUsage, os.Args[0])
+		return errors.New("missing migrate command")
+	}
+
+	m, err := storage.Migrator()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		if len(args) != 1 {
+			return errors.New("usage: migrate up")
+		}
+		err = m.Up()
+	case "down":
+		steps, parseErr := migrateArg(args, "down N")
+		if parseErr != nil {
+			return parseErr
+		}
+		if steps <= 0 {
+			return errors.New("down needs a positive number of migrations")
+		}
+		err = m.Steps(-steps)
+	case "goto":
+		version, parseErr := migrateArg(args, "goto V")
+		if parseErr != nil {
+			return parseErr
+		}
+		if version < 0 {
+			return errors.New("version must not be negative")
+		}
+		err = m.Migrate(uint(version))
+	case "force":
+		version, parseErr := migrateArg(args, "force V")
+		if parseErr != nil {
+			return parseErr
+		}
+		err = m.Force(version)
+	case "status":
+		if len(args) != 1 {
+			return errors.New("usage: migrate status")
+		}
+		return migrationStatus(storage, m, out)
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Fprintln(out, "no change")
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return printVersion(m, out)
+}
+
+func migrateArg(args []string, usage string) (int, error) {
+	if len(args) != 2 {
+		return 0, fmt.Errorf("usage: migrate %s", usage)
+	}
+	value, err := strconv.Atoi(args[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", args[1])
+	}
+	return value, nil
+}
+
+func printVersion(m *migrate.Migrate, out io.Writer) error {
+	version, dirty, err := m.Version()
+	switch {
+	case errors.Is(err, migrate.ErrNilVersion):
+		fmt.Fprintln(out, "version: none")
+	case err != nil:
+		return err
+	case dirty:
+		fmt.Fprintf(out, "version: %d (dirty)\n", version)
+	default:
+		fmt.Fprintf(out, "version: %d\n", version)
+	}
+	return nil
+}
+
+func migrationStatus(storage migrator, m *migrate.Migrate, out io.Writer) error {
+	current, dirty, err := m.Version()
+	applied := err == nil
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return err
+	}
+
+	migrations, err := storage.Migrations()
+	if err != nil {
+		return err
+	}
+	defer migrations.Close()
+
+	version, err := migrations.First()
+	for err == nil {
+		body, name, readErr := migrations.ReadUp(version)
+		if readErr != nil {
+			return readErr
+		}
+		body.Close()
+
+		state := "pending"
+		switch {
+		case applied && version == current && dirty:
+			state = "dirty"
+		case applied && version <= current:
+			state = "applied"
+		}
+		fmt.Fprintf(out, "%03d  %-8s %s\n", version, state, name)
+
+		version, err = migrations.Next(version)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return printVersion(m, out)
+}
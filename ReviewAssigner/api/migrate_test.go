@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"review-assigner/adapters/sqlite"
+	"strings"
+	"testing"
+)
+
+func TestRunMigrate(t *testing.T) {
+	storage, err := sqlite.New(slog.New(slog.NewTextHandler(io.Discard, nil)), "sqlite://:memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { storage.Close() })
+
+	// The steps run in order against the same database.
+	steps := []struct {
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{args: nil, wantErr: "missing migrate command"},
+		{args: []string{"status"}, want: []string{"001  pending  create_schema", "004  pending  add_pr_metadata", "version: none"}},
+		{args: []string{"up"}, want: []string{"version: 4"}},
+		{args: []string{"up"}, want: []string{"no change", "version: 4"}},
+		{args: []string{"status"}, want: []string{"001  applied  create_schema", "004  applied  add_pr_metadata", "version: 4"}},
+		{args: []string{"down", "1"}, want: []string{"version: 3"}},
+		{args: []string{"status"}, want: []string{"003  applied  add_repositories", "004  pending  add_pr_metadata"}},
+		{args: []string{"down"}, wantErr: "usage: migrate down N"},
+		{args: []string{"down", "0"}, wantErr: "positive number"},
+		{args: []string{"down", "two"}, wantErr: `invalid number "two"`},
+		{args: []string{"goto", "1"}, want: []string{"version: 1"}},
+		{args: []string{"goto", "4"}, want: []string{"version: 4"}},
+		{args: []string{"goto", "-1"}, wantErr: "must not be negative"},
+		{args: []string{"force", "2"}, want: []string{"version: 2"}},
+		{args: []string{"status"}, want: []string{"002  applied  add_tenants", "003  pending  add_repositories"}},
+		{args: []string{"force", "4"}, want: []string{"version: 4"}},
+		{args: []string{"up", "now"}, wantErr: "usage: migrate up"},
+		{args: []string{"sideways"}, wantErr: `unknown migrate command "sideways"`},
+	}
+
+	for _, step := range steps {
+		var out bytes.Buffer
+		err := runMigrate(storage, step.args, &out)
+
+		name := strings.Join(append([]string{"migrate"}, step.args...), " ")
+		switch {
+		case step.wantErr != "":
+			if err == nil || !strings.Contains(err.Error(), step.wantErr) {
+				t.Fatalf("%s: got error %v, want %q", name, err, step.wantErr)
+			}
+		case err != nil:
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, line := range step.want {
+			if !strings.Contains(out.String(), line) {
+				t.Fatalf("%s: output does not contain %q:\n%s", name, line, out.String())
+			}
+		}
+	}
+}